@@ -7,6 +7,8 @@
 package ticket
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -21,14 +23,139 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// ErrorReason - Stable reasons sent as google.rpc.ErrorInfo.reason on failed calls
+// The name, e.g. "SEAT_OCCUPIED", is the reason string; the domain is "ticket.TicketService"
+type ErrorReason int32
+
+const (
+	ErrorReason_ERROR_REASON_UNSPECIFIED       ErrorReason = 0
+	ErrorReason_TICKET_NOT_FOUND               ErrorReason = 1
+	ErrorReason_DUPLICATE_TICKET               ErrorReason = 2 // metadata: email
+	ErrorReason_SEAT_OCCUPIED                  ErrorReason = 3 // metadata: section, seat_number; with SeatSuggestions
+	ErrorReason_TRAIN_FULL                     ErrorReason = 4 // metadata: capacity
+	ErrorReason_SECTION_FULL                   ErrorReason = 5
+	ErrorReason_INVALID_SEAT                   ErrorReason = 6 // metadata: section, seat_number; with SeatSuggestions
+	ErrorReason_SECTION_OUT_OF_SERVICE         ErrorReason = 7 // metadata: section; with SeatSuggestions when moving
+	ErrorReason_SECTION_IN_SERVICE             ErrorReason = 8 // metadata: section
+	ErrorReason_TICKET_NOT_PENDING             ErrorReason = 9
+	ErrorReason_HOLD_EXPIRED                   ErrorReason = 10 // metadata: email
+	ErrorReason_VERIFICATION_NOT_FOUND         ErrorReason = 11 // metadata: email
+	ErrorReason_VERIFICATION_EXPIRED           ErrorReason = 12 // metadata: email
+	ErrorReason_VERIFICATION_MISMATCH          ErrorReason = 13 // metadata: email
+	ErrorReason_VERIFICATION_ATTEMPTS_EXCEEDED ErrorReason = 14 // metadata: email
+	ErrorReason_SWAP_NOT_FOUND                 ErrorReason = 15 // metadata: swap_id
+	ErrorReason_SWAP_WITH_SELF                 ErrorReason = 16
+	ErrorReason_SWAP_STALE                     ErrorReason = 17 // metadata: swap_id
+	ErrorReason_BATCH_ROLLED_BACK              ErrorReason = 18
+	ErrorReason_EMAIL_DOMAIN_CAP               ErrorReason = 19 // metadata: domain
+	ErrorReason_IP_PURCHASES_BLOCKED           ErrorReason = 20
+	ErrorReason_CHALLENGE_REQUIRED             ErrorReason = 21
+	ErrorReason_CHALLENGE_INVALID              ErrorReason = 22
+	ErrorReason_CHALLENGE_EXPIRED              ErrorReason = 23
+	ErrorReason_CHALLENGE_USED                 ErrorReason = 24
+	ErrorReason_CHALLENGE_UNSOLVED             ErrorReason = 25
+	ErrorReason_TICKET_PENDING                 ErrorReason = 26 // metadata: email
+)
+
+// Enum value maps for ErrorReason.
+var (
+	ErrorReason_name = map[int32]string{
+		0:  "ERROR_REASON_UNSPECIFIED",
+		1:  "TICKET_NOT_FOUND",
+		2:  "DUPLICATE_TICKET",
+		3:  "SEAT_OCCUPIED",
+		4:  "TRAIN_FULL",
+		5:  "SECTION_FULL",
+		6:  "INVALID_SEAT",
+		7:  "SECTION_OUT_OF_SERVICE",
+		8:  "SECTION_IN_SERVICE",
+		9:  "TICKET_NOT_PENDING",
+		10: "HOLD_EXPIRED",
+		11: "VERIFICATION_NOT_FOUND",
+		12: "VERIFICATION_EXPIRED",
+		13: "VERIFICATION_MISMATCH",
+		14: "VERIFICATION_ATTEMPTS_EXCEEDED",
+		15: "SWAP_NOT_FOUND",
+		16: "SWAP_WITH_SELF",
+		17: "SWAP_STALE",
+		18: "BATCH_ROLLED_BACK",
+		19: "EMAIL_DOMAIN_CAP",
+		20: "IP_PURCHASES_BLOCKED",
+		21: "CHALLENGE_REQUIRED",
+		22: "CHALLENGE_INVALID",
+		23: "CHALLENGE_EXPIRED",
+		24: "CHALLENGE_USED",
+		25: "CHALLENGE_UNSOLVED",
+		26: "TICKET_PENDING",
+	}
+	ErrorReason_value = map[string]int32{
+		"ERROR_REASON_UNSPECIFIED":       0,
+		"TICKET_NOT_FOUND":               1,
+		"DUPLICATE_TICKET":               2,
+		"SEAT_OCCUPIED":                  3,
+		"TRAIN_FULL":                     4,
+		"SECTION_FULL":                   5,
+		"INVALID_SEAT":                   6,
+		"SECTION_OUT_OF_SERVICE":         7,
+		"SECTION_IN_SERVICE":             8,
+		"TICKET_NOT_PENDING":             9,
+		"HOLD_EXPIRED":                   10,
+		"VERIFICATION_NOT_FOUND":         11,
+		"VERIFICATION_EXPIRED":           12,
+		"VERIFICATION_MISMATCH":          13,
+		"VERIFICATION_ATTEMPTS_EXCEEDED": 14,
+		"SWAP_NOT_FOUND":                 15,
+		"SWAP_WITH_SELF":                 16,
+		"SWAP_STALE":                     17,
+		"BATCH_ROLLED_BACK":              18,
+		"EMAIL_DOMAIN_CAP":               19,
+		"IP_PURCHASES_BLOCKED":           20,
+		"CHALLENGE_REQUIRED":             21,
+		"CHALLENGE_INVALID":              22,
+		"CHALLENGE_EXPIRED":              23,
+		"CHALLENGE_USED":                 24,
+		"CHALLENGE_UNSOLVED":             25,
+		"TICKET_PENDING":                 26,
+	}
+)
+
+func (x ErrorReason) Enum() *ErrorReason {
+	p := new(ErrorReason)
+	*p = x
+	return p
+}
+
+func (x ErrorReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_ticket_proto_enumTypes[0].Descriptor()
+}
+
+func (ErrorReason) Type() protoreflect.EnumType {
+	return &file_api_ticket_proto_enumTypes[0]
+}
+
+func (x ErrorReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorReason.Descriptor instead.
+func (ErrorReason) EnumDescriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{0}
+}
+
 // PurchaseTicketRequest - Request to purchase a ticket
 type PurchaseTicketRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	FirstName     string                 `protobuf:"bytes,1,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
-	LastName      string                 `protobuf:"bytes,2,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
-	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	FirstName      string                 `protobuf:"bytes,1,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName       string                 `protobuf:"bytes,2,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Email          string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	ChallengeToken string                 `protobuf:"bytes,4,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"` // From RequestPurchaseChallenge, when a challenge is required
+	ChallengeNonce string                 `protobuf:"bytes,5,opt,name=challenge_nonce,json=challengeNonce,proto3" json:"challenge_nonce,omitempty"` // Solution to the challenge
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *PurchaseTicketRequest) Reset() {
@@ -82,6 +209,20 @@ func (x *PurchaseTicketRequest) GetEmail() string {
 	return ""
 }
 
+func (x *PurchaseTicketRequest) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+func (x *PurchaseTicketRequest) GetChallengeNonce() string {
+	if x != nil {
+		return x.ChallengeNonce
+	}
+	return ""
+}
+
 // PurchaseTicketResponse - Response containing the receipt
 type PurchaseTicketResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -127,6 +268,104 @@ func (x *PurchaseTicketResponse) GetReceipt() *Receipt {
 	return nil
 }
 
+// VerifyPurchaseRequest - Request to confirm an anonymous purchase
+type VerifyPurchaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"` // Verification code sent to the email address
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyPurchaseRequest) Reset() {
+	*x = VerifyPurchaseRequest{}
+	mi := &file_api_ticket_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyPurchaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyPurchaseRequest) ProtoMessage() {}
+
+func (x *VerifyPurchaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyPurchaseRequest.ProtoReflect.Descriptor instead.
+func (*VerifyPurchaseRequest) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *VerifyPurchaseRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *VerifyPurchaseRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+// VerifyPurchaseResponse - Response containing the confirmed receipt
+type VerifyPurchaseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Receipt       *Receipt               `protobuf:"bytes,1,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyPurchaseResponse) Reset() {
+	*x = VerifyPurchaseResponse{}
+	mi := &file_api_ticket_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyPurchaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyPurchaseResponse) ProtoMessage() {}
+
+func (x *VerifyPurchaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyPurchaseResponse.ProtoReflect.Descriptor instead.
+func (*VerifyPurchaseResponse) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *VerifyPurchaseResponse) GetReceipt() *Receipt {
+	if x != nil {
+		return x.Receipt
+	}
+	return nil
+}
+
 // ViewUserReceiptRequest - Request to view user's receipt (empty, uses JWT)
 type ViewUserReceiptRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -136,7 +375,7 @@ type ViewUserReceiptRequest struct {
 
 func (x *ViewUserReceiptRequest) Reset() {
 	*x = ViewUserReceiptRequest{}
-	mi := &file_api_ticket_proto_msgTypes[2]
+	mi := &file_api_ticket_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -148,7 +387,7 @@ func (x *ViewUserReceiptRequest) String() string {
 func (*ViewUserReceiptRequest) ProtoMessage() {}
 
 func (x *ViewUserReceiptRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_ticket_proto_msgTypes[2]
+	mi := &file_api_ticket_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -161,7 +400,7 @@ func (x *ViewUserReceiptRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ViewUserReceiptRequest.ProtoReflect.Descriptor instead.
 func (*ViewUserReceiptRequest) Descriptor() ([]byte, []int) {
-	return file_api_ticket_proto_rawDescGZIP(), []int{2}
+	return file_api_ticket_proto_rawDescGZIP(), []int{4}
 }
 
 // ViewUserReceiptResponse - Response containing the user's receipt
@@ -174,7 +413,7 @@ type ViewUserReceiptResponse struct {
 
 func (x *ViewUserReceiptResponse) Reset() {
 	*x = ViewUserReceiptResponse{}
-	mi := &file_api_ticket_proto_msgTypes[3]
+	mi := &file_api_ticket_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -186,7 +425,7 @@ func (x *ViewUserReceiptResponse) String() string {
 func (*ViewUserReceiptResponse) ProtoMessage() {}
 
 func (x *ViewUserReceiptResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_ticket_proto_msgTypes[3]
+	mi := &file_api_ticket_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -199,7 +438,7 @@ func (x *ViewUserReceiptResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ViewUserReceiptResponse.ProtoReflect.Descriptor instead.
 func (*ViewUserReceiptResponse) Descriptor() ([]byte, []int) {
-	return file_api_ticket_proto_rawDescGZIP(), []int{3}
+	return file_api_ticket_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *ViewUserReceiptResponse) GetReceipt() *Receipt {
@@ -214,13 +453,18 @@ type ViewAllocationsRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Optional: filter by section (A or B). Empty means all sections
 	Section       string `protobuf:"bytes,1,opt,name=section,proto3" json:"section,omitempty"`
+	PageSize      int32  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // Default 50, at most 500
+	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous page, sent with the same filters
+	OrderBy       string `protobuf:"bytes,4,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`       // "seat" (default), "name" or "email", optionally followed by " desc"
+	Search        string `protobuf:"bytes,5,opt,name=search,proto3" json:"search,omitempty"`                        // Case-insensitive substring of the email or full name
+	Status        string `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`                        // "confirmed" or "pending_verification"; empty means both
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ViewAllocationsRequest) Reset() {
 	*x = ViewAllocationsRequest{}
-	mi := &file_api_ticket_proto_msgTypes[4]
+	mi := &file_api_ticket_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -232,7 +476,7 @@ func (x *ViewAllocationsRequest) String() string {
 func (*ViewAllocationsRequest) ProtoMessage() {}
 
 func (x *ViewAllocationsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_ticket_proto_msgTypes[4]
+	mi := &file_api_ticket_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -245,7 +489,7 @@ func (x *ViewAllocationsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ViewAllocationsRequest.ProtoReflect.Descriptor instead.
 func (*ViewAllocationsRequest) Descriptor() ([]byte, []int) {
-	return file_api_ticket_proto_rawDescGZIP(), []int{4}
+	return file_api_ticket_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ViewAllocationsRequest) GetSection() string {
@@ -255,17 +499,54 @@ func (x *ViewAllocationsRequest) GetSection() string {
 	return ""
 }
 
-// ViewAllocationsResponse - Response containing all allocations
+func (x *ViewAllocationsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ViewAllocationsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ViewAllocationsRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *ViewAllocationsRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *ViewAllocationsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// ViewAllocationsResponse - Response containing a page of allocations
 type ViewAllocationsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Allocations   []*Allocation          `protobuf:"bytes,1,rep,name=allocations,proto3" json:"allocations,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	TotalSize     int32                  `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`              // Allocations matching the filters, across all pages
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ViewAllocationsResponse) Reset() {
 	*x = ViewAllocationsResponse{}
-	mi := &file_api_ticket_proto_msgTypes[5]
+	mi := &file_api_ticket_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -277,7 +558,7 @@ func (x *ViewAllocationsResponse) String() string {
 func (*ViewAllocationsResponse) ProtoMessage() {}
 
 func (x *ViewAllocationsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_ticket_proto_msgTypes[5]
+	mi := &file_api_ticket_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -290,7 +571,7 @@ func (x *ViewAllocationsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ViewAllocationsResponse.ProtoReflect.Descriptor instead.
 func (*ViewAllocationsResponse) Descriptor() ([]byte, []int) {
-	return file_api_ticket_proto_rawDescGZIP(), []int{5}
+	return file_api_ticket_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ViewAllocationsResponse) GetAllocations() []*Allocation {
@@ -300,19 +581,34 @@ func (x *ViewAllocationsResponse) GetAllocations() []*Allocation {
 	return nil
 }
 
+func (x *ViewAllocationsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ViewAllocationsResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
 // Allocation - Represents a seat allocation
 type Allocation struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Section       string                 `protobuf:"bytes,1,opt,name=section,proto3" json:"section,omitempty"`
 	SeatNumber    int32                  `protobuf:"varint,2,opt,name=seat_number,json=seatNumber,proto3" json:"seat_number,omitempty"`
 	User          *User                  `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"` // "confirmed" or "pending_verification"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Allocation) Reset() {
 	*x = Allocation{}
-	mi := &file_api_ticket_proto_msgTypes[6]
+	mi := &file_api_ticket_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -324,7 +620,7 @@ func (x *Allocation) String() string {
 func (*Allocation) ProtoMessage() {}
 
 func (x *Allocation) ProtoReflect() protoreflect.Message {
-	mi := &file_api_ticket_proto_msgTypes[6]
+	mi := &file_api_ticket_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -337,7 +633,7 @@ func (x *Allocation) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Allocation.ProtoReflect.Descriptor instead.
 func (*Allocation) Descriptor() ([]byte, []int) {
-	return file_api_ticket_proto_rawDescGZIP(), []int{6}
+	return file_api_ticket_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *Allocation) GetSection() string {
@@ -361,6 +657,13 @@ func (x *Allocation) GetUser() *User {
 	return nil
 }
 
+func (x *Allocation) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
 // RemoveUserFromTrainRequest - Request to remove a user from the train
 type RemoveUserFromTrainRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -372,7 +675,7 @@ type RemoveUserFromTrainRequest struct {
 
 func (x *RemoveUserFromTrainRequest) Reset() {
 	*x = RemoveUserFromTrainRequest{}
-	mi := &file_api_ticket_proto_msgTypes[7]
+	mi := &file_api_ticket_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -384,7 +687,7 @@ func (x *RemoveUserFromTrainRequest) String() string {
 func (*RemoveUserFromTrainRequest) ProtoMessage() {}
 
 func (x *RemoveUserFromTrainRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_ticket_proto_msgTypes[7]
+	mi := &file_api_ticket_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -397,7 +700,7 @@ func (x *RemoveUserFromTrainRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveUserFromTrainRequest.ProtoReflect.Descriptor instead.
 func (*RemoveUserFromTrainRequest) Descriptor() ([]byte, []int) {
-	return file_api_ticket_proto_rawDescGZIP(), []int{7}
+	return file_api_ticket_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *RemoveUserFromTrainRequest) GetEmail() string {
@@ -418,7 +721,7 @@ type RemoveUserFromTrainResponse struct {
 
 func (x *RemoveUserFromTrainResponse) Reset() {
 	*x = RemoveUserFromTrainResponse{}
-	mi := &file_api_ticket_proto_msgTypes[8]
+	mi := &file_api_ticket_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -430,7 +733,7 @@ func (x *RemoveUserFromTrainResponse) String() string {
 func (*RemoveUserFromTrainResponse) ProtoMessage() {}
 
 func (x *RemoveUserFromTrainResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_ticket_proto_msgTypes[8]
+	mi := &file_api_ticket_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -443,7 +746,7 @@ func (x *RemoveUserFromTrainResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveUserFromTrainResponse.ProtoReflect.Descriptor instead.
 func (*RemoveUserFromTrainResponse) Descriptor() ([]byte, []int) {
-	return file_api_ticket_proto_rawDescGZIP(), []int{8}
+	return file_api_ticket_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *RemoveUserFromTrainResponse) GetSuccess() bool {
@@ -473,7 +776,7 @@ type ModifyUserSeatRequest struct {
 
 func (x *ModifyUserSeatRequest) Reset() {
 	*x = ModifyUserSeatRequest{}
-	mi := &file_api_ticket_proto_msgTypes[9]
+	mi := &file_api_ticket_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -485,7 +788,7 @@ func (x *ModifyUserSeatRequest) String() string {
 func (*ModifyUserSeatRequest) ProtoMessage() {}
 
 func (x *ModifyUserSeatRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_ticket_proto_msgTypes[9]
+	mi := &file_api_ticket_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -498,7 +801,7 @@ func (x *ModifyUserSeatRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ModifyUserSeatRequest.ProtoReflect.Descriptor instead.
 func (*ModifyUserSeatRequest) Descriptor() ([]byte, []int) {
-	return file_api_ticket_proto_rawDescGZIP(), []int{9}
+	return file_api_ticket_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ModifyUserSeatRequest) GetEmail() string {
@@ -532,7 +835,7 @@ type ModifyUserSeatResponse struct {
 
 func (x *ModifyUserSeatResponse) Reset() {
 	*x = ModifyUserSeatResponse{}
-	mi := &file_api_ticket_proto_msgTypes[10]
+	mi := &file_api_ticket_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -544,7 +847,7 @@ func (x *ModifyUserSeatResponse) String() string {
 func (*ModifyUserSeatResponse) ProtoMessage() {}
 
 func (x *ModifyUserSeatResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_ticket_proto_msgTypes[10]
+	mi := &file_api_ticket_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -557,7 +860,7 @@ func (x *ModifyUserSeatResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ModifyUserSeatResponse.ProtoReflect.Descriptor instead.
 func (*ModifyUserSeatResponse) Descriptor() ([]byte, []int) {
-	return file_api_ticket_proto_rawDescGZIP(), []int{10}
+	return file_api_ticket_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *ModifyUserSeatResponse) GetReceipt() *Receipt {
@@ -567,33 +870,32 @@ func (x *ModifyUserSeatResponse) GetReceipt() *Receipt {
 	return nil
 }
 
-// Receipt - Represents a ticket receipt
-type Receipt struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"` // "London"
-	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`     // "France"
-	User          *User                  `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
-	PricePaid     int32                  `protobuf:"varint,4,opt,name=price_paid,json=pricePaid,proto3" json:"price_paid,omitempty"` // in cents, so $20 = 2000
-	Seat          *Seat                  `protobuf:"bytes,5,opt,name=seat,proto3" json:"seat,omitempty"`
+// RequestSeatSwapRequest - Request to swap seats with another passenger
+type RequestSeatSwapRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	CounterpartyEmail string                 `protobuf:"bytes,1,opt,name=counterparty_email,json=counterpartyEmail,proto3" json:"counterparty_email,omitempty"` // Passenger to swap seats with
+	// Optional: email of the requesting passenger (for admin). If empty, uses the user from JWT
+	Email         string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Force         bool   `protobuf:"varint,3,opt,name=force,proto3" json:"force,omitempty"` // Admin only: swap immediately without the counterparty's approval
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Receipt) Reset() {
-	*x = Receipt{}
-	mi := &file_api_ticket_proto_msgTypes[11]
+func (x *RequestSeatSwapRequest) Reset() {
+	*x = RequestSeatSwapRequest{}
+	mi := &file_api_ticket_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Receipt) String() string {
+func (x *RequestSeatSwapRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Receipt) ProtoMessage() {}
+func (*RequestSeatSwapRequest) ProtoMessage() {}
 
-func (x *Receipt) ProtoReflect() protoreflect.Message {
-	mi := &file_api_ticket_proto_msgTypes[11]
+func (x *RequestSeatSwapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -604,71 +906,2448 @@ func (x *Receipt) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Receipt.ProtoReflect.Descriptor instead.
-func (*Receipt) Descriptor() ([]byte, []int) {
-	return file_api_ticket_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use RequestSeatSwapRequest.ProtoReflect.Descriptor instead.
+func (*RequestSeatSwapRequest) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *Receipt) GetFrom() string {
+func (x *RequestSeatSwapRequest) GetCounterpartyEmail() string {
 	if x != nil {
-		return x.From
+		return x.CounterpartyEmail
 	}
 	return ""
 }
 
-func (x *Receipt) GetTo() string {
+func (x *RequestSeatSwapRequest) GetEmail() string {
 	if x != nil {
-		return x.To
+		return x.Email
 	}
 	return ""
 }
 
-func (x *Receipt) GetUser() *User {
-	if x != nil {
-		return x.User
-	}
-	return nil
-}
-
-func (x *Receipt) GetPricePaid() int32 {
-	if x != nil {
-		return x.PricePaid
-	}
-	return 0
-}
-
-func (x *Receipt) GetSeat() *Seat {
+func (x *RequestSeatSwapRequest) GetForce() bool {
 	if x != nil {
-		return x.Seat
+		return x.Force
 	}
-	return nil
+	return false
 }
 
-// User - Represents a user
-type User struct {
+// RequestSeatSwapResponse - Response containing the swap request
+type RequestSeatSwapResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	FirstName     string                 `protobuf:"bytes,1,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
-	LastName      string                 `protobuf:"bytes,2,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
-	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Swap          *SeatSwap              `protobuf:"bytes,1,opt,name=swap,proto3" json:"swap,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *User) Reset() {
+func (x *RequestSeatSwapResponse) Reset() {
+	*x = RequestSeatSwapResponse{}
+	mi := &file_api_ticket_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestSeatSwapResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestSeatSwapResponse) ProtoMessage() {}
+
+func (x *RequestSeatSwapResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestSeatSwapResponse.ProtoReflect.Descriptor instead.
+func (*RequestSeatSwapResponse) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RequestSeatSwapResponse) GetSwap() *SeatSwap {
+	if x != nil {
+		return x.Swap
+	}
+	return nil
+}
+
+// AcceptSeatSwapRequest - Request to accept a pending swap
+type AcceptSeatSwapRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SwapId        string                 `protobuf:"bytes,1,opt,name=swap_id,json=swapId,proto3" json:"swap_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcceptSeatSwapRequest) Reset() {
+	*x = AcceptSeatSwapRequest{}
+	mi := &file_api_ticket_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptSeatSwapRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptSeatSwapRequest) ProtoMessage() {}
+
+func (x *AcceptSeatSwapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptSeatSwapRequest.ProtoReflect.Descriptor instead.
+func (*AcceptSeatSwapRequest) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AcceptSeatSwapRequest) GetSwapId() string {
+	if x != nil {
+		return x.SwapId
+	}
+	return ""
+}
+
+// AcceptSeatSwapResponse - Response containing the completed swap
+type AcceptSeatSwapResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Swap          *SeatSwap              `protobuf:"bytes,1,opt,name=swap,proto3" json:"swap,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcceptSeatSwapResponse) Reset() {
+	*x = AcceptSeatSwapResponse{}
+	mi := &file_api_ticket_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptSeatSwapResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptSeatSwapResponse) ProtoMessage() {}
+
+func (x *AcceptSeatSwapResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptSeatSwapResponse.ProtoReflect.Descriptor instead.
+func (*AcceptSeatSwapResponse) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AcceptSeatSwapResponse) GetSwap() *SeatSwap {
+	if x != nil {
+		return x.Swap
+	}
+	return nil
+}
+
+// SeatSwap - Represents a seat swap between two passengers
+type SeatSwap struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RequesterEmail    string                 `protobuf:"bytes,2,opt,name=requester_email,json=requesterEmail,proto3" json:"requester_email,omitempty"`
+	RequesterSeat     *Seat                  `protobuf:"bytes,3,opt,name=requester_seat,json=requesterSeat,proto3" json:"requester_seat,omitempty"` // Requester's seat when the swap was requested
+	CounterpartyEmail string                 `protobuf:"bytes,4,opt,name=counterparty_email,json=counterpartyEmail,proto3" json:"counterparty_email,omitempty"`
+	CounterpartySeat  *Seat                  `protobuf:"bytes,5,opt,name=counterparty_seat,json=counterpartySeat,proto3" json:"counterparty_seat,omitempty"` // Counterparty's seat when the swap was requested; unset until completed
+	Status            string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`                                             // "pending" or "completed"
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SeatSwap) Reset() {
+	*x = SeatSwap{}
+	mi := &file_api_ticket_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeatSwap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeatSwap) ProtoMessage() {}
+
+func (x *SeatSwap) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeatSwap.ProtoReflect.Descriptor instead.
+func (*SeatSwap) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SeatSwap) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SeatSwap) GetRequesterEmail() string {
+	if x != nil {
+		return x.RequesterEmail
+	}
+	return ""
+}
+
+func (x *SeatSwap) GetRequesterSeat() *Seat {
+	if x != nil {
+		return x.RequesterSeat
+	}
+	return nil
+}
+
+func (x *SeatSwap) GetCounterpartyEmail() string {
+	if x != nil {
+		return x.CounterpartyEmail
+	}
+	return ""
+}
+
+func (x *SeatSwap) GetCounterpartySeat() *Seat {
+	if x != nil {
+		return x.CounterpartySeat
+	}
+	return nil
+}
+
+func (x *SeatSwap) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// BulkApplyRequest - Request to apply a batch of admin operations in order
+type BulkApplyRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Operations []*BulkOperation       `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"`
+	// If false, any failure rolls back the whole batch. If true, failed operations are skipped
+	BestEffort    bool `protobuf:"varint,2,opt,name=best_effort,json=bestEffort,proto3" json:"best_effort,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkApplyRequest) Reset() {
+	*x = BulkApplyRequest{}
+	mi := &file_api_ticket_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkApplyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkApplyRequest) ProtoMessage() {}
+
+func (x *BulkApplyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkApplyRequest.ProtoReflect.Descriptor instead.
+func (*BulkApplyRequest) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *BulkApplyRequest) GetOperations() []*BulkOperation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *BulkApplyRequest) GetBestEffort() bool {
+	if x != nil {
+		return x.BestEffort
+	}
+	return false
+}
+
+// BulkOperation - A single operation in a bulk request
+type BulkOperation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Operation:
+	//
+	//	*BulkOperation_Remove
+	//	*BulkOperation_Move
+	//	*BulkOperation_Reassign
+	Operation     isBulkOperation_Operation `protobuf_oneof:"operation"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkOperation) Reset() {
+	*x = BulkOperation{}
+	mi := &file_api_ticket_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkOperation) ProtoMessage() {}
+
+func (x *BulkOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkOperation.ProtoReflect.Descriptor instead.
+func (*BulkOperation) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *BulkOperation) GetOperation() isBulkOperation_Operation {
+	if x != nil {
+		return x.Operation
+	}
+	return nil
+}
+
+func (x *BulkOperation) GetRemove() *RemoveOperation {
+	if x != nil {
+		if x, ok := x.Operation.(*BulkOperation_Remove); ok {
+			return x.Remove
+		}
+	}
+	return nil
+}
+
+func (x *BulkOperation) GetMove() *MoveOperation {
+	if x != nil {
+		if x, ok := x.Operation.(*BulkOperation_Move); ok {
+			return x.Move
+		}
+	}
+	return nil
+}
+
+func (x *BulkOperation) GetReassign() *ReassignOperation {
+	if x != nil {
+		if x, ok := x.Operation.(*BulkOperation_Reassign); ok {
+			return x.Reassign
+		}
+	}
+	return nil
+}
+
+type isBulkOperation_Operation interface {
+	isBulkOperation_Operation()
+}
+
+type BulkOperation_Remove struct {
+	Remove *RemoveOperation `protobuf:"bytes,1,opt,name=remove,proto3,oneof"`
+}
+
+type BulkOperation_Move struct {
+	Move *MoveOperation `protobuf:"bytes,2,opt,name=move,proto3,oneof"`
+}
+
+type BulkOperation_Reassign struct {
+	Reassign *ReassignOperation `protobuf:"bytes,3,opt,name=reassign,proto3,oneof"`
+}
+
+func (*BulkOperation_Remove) isBulkOperation_Operation() {}
+
+func (*BulkOperation_Move) isBulkOperation_Operation() {}
+
+func (*BulkOperation_Reassign) isBulkOperation_Operation() {}
+
+// RemoveOperation - Removes a passenger from the train
+type RemoveOperation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveOperation) Reset() {
+	*x = RemoveOperation{}
+	mi := &file_api_ticket_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveOperation) ProtoMessage() {}
+
+func (x *RemoveOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveOperation.ProtoReflect.Descriptor instead.
+func (*RemoveOperation) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RemoveOperation) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+// MoveOperation - Moves a passenger to a specific seat
+type MoveOperation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Section       string                 `protobuf:"bytes,2,opt,name=section,proto3" json:"section,omitempty"`
+	SeatNumber    int32                  `protobuf:"varint,3,opt,name=seat_number,json=seatNumber,proto3" json:"seat_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveOperation) Reset() {
+	*x = MoveOperation{}
+	mi := &file_api_ticket_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveOperation) ProtoMessage() {}
+
+func (x *MoveOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveOperation.ProtoReflect.Descriptor instead.
+func (*MoveOperation) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *MoveOperation) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *MoveOperation) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *MoveOperation) GetSeatNumber() int32 {
+	if x != nil {
+		return x.SeatNumber
+	}
+	return 0
+}
+
+// ReassignOperation - Moves a passenger to the first free seat
+type ReassignOperation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Section       string                 `protobuf:"bytes,2,opt,name=section,proto3" json:"section,omitempty"` // Optional: restrict the new seat to this section
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReassignOperation) Reset() {
+	*x = ReassignOperation{}
+	mi := &file_api_ticket_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReassignOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReassignOperation) ProtoMessage() {}
+
+func (x *ReassignOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReassignOperation.ProtoReflect.Descriptor instead.
+func (*ReassignOperation) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ReassignOperation) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *ReassignOperation) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+// BulkApplyResponse - Response containing one result per operation
+type BulkApplyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*BulkResult          `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Applied       bool                   `protobuf:"varint,2,opt,name=applied,proto3" json:"applied,omitempty"` // False when an all-or-nothing batch was rolled back
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkApplyResponse) Reset() {
+	*x = BulkApplyResponse{}
+	mi := &file_api_ticket_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkApplyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkApplyResponse) ProtoMessage() {}
+
+func (x *BulkApplyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkApplyResponse.ProtoReflect.Descriptor instead.
+func (*BulkApplyResponse) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *BulkApplyResponse) GetResults() []*BulkResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *BulkApplyResponse) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+// BulkResult - Outcome of a single bulk operation
+type BulkResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"` // Position of the operation in the request
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Code          string                 `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"` // gRPC status code name when the operation failed
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	Seat          *Seat                  `protobuf:"bytes,5,opt,name=seat,proto3" json:"seat,omitempty"`     // New seat for move and reassign operations
+	Reason        string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"` // ErrorReason name when the operation failed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkResult) Reset() {
+	*x = BulkResult{}
+	mi := &file_api_ticket_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkResult) ProtoMessage() {}
+
+func (x *BulkResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkResult.ProtoReflect.Descriptor instead.
+func (*BulkResult) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *BulkResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BulkResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *BulkResult) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *BulkResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *BulkResult) GetSeat() *Seat {
+	if x != nil {
+		return x.Seat
+	}
+	return nil
+}
+
+func (x *BulkResult) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// DecommissionSectionRequest - Request to take a section out of service
+type DecommissionSectionRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Section        string                 `protobuf:"bytes,1,opt,name=section,proto3" json:"section,omitempty"`
+	OverflowPolicy string                 `protobuf:"bytes,2,opt,name=overflow_policy,json=overflowPolicy,proto3" json:"overflow_policy,omitempty"` // "waitlist" (default) or "refund" for passengers who cannot be reseated
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DecommissionSectionRequest) Reset() {
+	*x = DecommissionSectionRequest{}
+	mi := &file_api_ticket_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecommissionSectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecommissionSectionRequest) ProtoMessage() {}
+
+func (x *DecommissionSectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecommissionSectionRequest.ProtoReflect.Descriptor instead.
+func (*DecommissionSectionRequest) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *DecommissionSectionRequest) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *DecommissionSectionRequest) GetOverflowPolicy() string {
+	if x != nil {
+		return x.OverflowPolicy
+	}
+	return ""
+}
+
+// DecommissionSectionResponse - Response containing the re-accommodation report
+type DecommissionSectionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Report        *ReaccommodationReport `protobuf:"bytes,1,opt,name=report,proto3" json:"report,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecommissionSectionResponse) Reset() {
+	*x = DecommissionSectionResponse{}
+	mi := &file_api_ticket_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecommissionSectionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecommissionSectionResponse) ProtoMessage() {}
+
+func (x *DecommissionSectionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecommissionSectionResponse.ProtoReflect.Descriptor instead.
+func (*DecommissionSectionResponse) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DecommissionSectionResponse) GetReport() *ReaccommodationReport {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
+// ReinstateSectionRequest - Request to return a section to service
+type ReinstateSectionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Section       string                 `protobuf:"bytes,1,opt,name=section,proto3" json:"section,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReinstateSectionRequest) Reset() {
+	*x = ReinstateSectionRequest{}
+	mi := &file_api_ticket_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReinstateSectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReinstateSectionRequest) ProtoMessage() {}
+
+func (x *ReinstateSectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReinstateSectionRequest.ProtoReflect.Descriptor instead.
+func (*ReinstateSectionRequest) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ReinstateSectionRequest) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+// ReinstateSectionResponse - Response listing waitlisted passengers who were seated
+type ReinstateSectionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Placed        []*SeatMove            `protobuf:"bytes,1,rep,name=placed,proto3" json:"placed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReinstateSectionResponse) Reset() {
+	*x = ReinstateSectionResponse{}
+	mi := &file_api_ticket_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReinstateSectionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReinstateSectionResponse) ProtoMessage() {}
+
+func (x *ReinstateSectionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReinstateSectionResponse.ProtoReflect.Descriptor instead.
+func (*ReinstateSectionResponse) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ReinstateSectionResponse) GetPlaced() []*SeatMove {
+	if x != nil {
+		return x.Placed
+	}
+	return nil
+}
+
+// ReaccommodationReport - What happened to each passenger of a decommissioned section
+type ReaccommodationReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Section       string                 `protobuf:"bytes,1,opt,name=section,proto3" json:"section,omitempty"`
+	Moved         []*SeatMove            `protobuf:"bytes,2,rep,name=moved,proto3" json:"moved,omitempty"`
+	Waitlisted    []*DisplacedPassenger  `protobuf:"bytes,3,rep,name=waitlisted,proto3" json:"waitlisted,omitempty"`
+	Refunded      []*DisplacedPassenger  `protobuf:"bytes,4,rep,name=refunded,proto3" json:"refunded,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReaccommodationReport) Reset() {
+	*x = ReaccommodationReport{}
+	mi := &file_api_ticket_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReaccommodationReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReaccommodationReport) ProtoMessage() {}
+
+func (x *ReaccommodationReport) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReaccommodationReport.ProtoReflect.Descriptor instead.
+func (*ReaccommodationReport) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ReaccommodationReport) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *ReaccommodationReport) GetMoved() []*SeatMove {
+	if x != nil {
+		return x.Moved
+	}
+	return nil
+}
+
+func (x *ReaccommodationReport) GetWaitlisted() []*DisplacedPassenger {
+	if x != nil {
+		return x.Waitlisted
+	}
+	return nil
+}
+
+func (x *ReaccommodationReport) GetRefunded() []*DisplacedPassenger {
+	if x != nil {
+		return x.Refunded
+	}
+	return nil
+}
+
+// SeatMove - A passenger moved from one seat to another
+type SeatMove struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	From          *Seat                  `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            *Seat                  `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SeatMove) Reset() {
+	*x = SeatMove{}
+	mi := &file_api_ticket_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeatMove) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeatMove) ProtoMessage() {}
+
+func (x *SeatMove) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeatMove.ProtoReflect.Descriptor instead.
+func (*SeatMove) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SeatMove) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *SeatMove) GetFrom() *Seat {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *SeatMove) GetTo() *Seat {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+// DisplacedPassenger - A passenger who lost their seat
+type DisplacedPassenger struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	From          *Seat                  `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	RefundCents   int32                  `protobuf:"varint,3,opt,name=refund_cents,json=refundCents,proto3" json:"refund_cents,omitempty"` // Amount refunded, 0 for waitlisted passengers
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisplacedPassenger) Reset() {
+	*x = DisplacedPassenger{}
+	mi := &file_api_ticket_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisplacedPassenger) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisplacedPassenger) ProtoMessage() {}
+
+func (x *DisplacedPassenger) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisplacedPassenger.ProtoReflect.Descriptor instead.
+func (*DisplacedPassenger) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DisplacedPassenger) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *DisplacedPassenger) GetFrom() *Seat {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *DisplacedPassenger) GetRefundCents() int32 {
+	if x != nil {
+		return x.RefundCents
+	}
+	return 0
+}
+
+// AdminPurchaseTicketRequest - Request to purchase a ticket for another passenger
+type AdminPurchaseTicketRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Passenger     *User                  `protobuf:"bytes,1,opt,name=passenger,proto3" json:"passenger,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminPurchaseTicketRequest) Reset() {
+	*x = AdminPurchaseTicketRequest{}
+	mi := &file_api_ticket_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminPurchaseTicketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminPurchaseTicketRequest) ProtoMessage() {}
+
+func (x *AdminPurchaseTicketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminPurchaseTicketRequest.ProtoReflect.Descriptor instead.
+func (*AdminPurchaseTicketRequest) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *AdminPurchaseTicketRequest) GetPassenger() *User {
+	if x != nil {
+		return x.Passenger
+	}
+	return nil
+}
+
+// AdminPurchaseTicketResponse - Response containing the passenger's receipt
+type AdminPurchaseTicketResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Receipt       *Receipt               `protobuf:"bytes,1,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminPurchaseTicketResponse) Reset() {
+	*x = AdminPurchaseTicketResponse{}
+	mi := &file_api_ticket_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminPurchaseTicketResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminPurchaseTicketResponse) ProtoMessage() {}
+
+func (x *AdminPurchaseTicketResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminPurchaseTicketResponse.ProtoReflect.Descriptor instead.
+func (*AdminPurchaseTicketResponse) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *AdminPurchaseTicketResponse) GetReceipt() *Receipt {
+	if x != nil {
+		return x.Receipt
+	}
+	return nil
+}
+
+// Receipt - Represents a ticket receipt
+type Receipt struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"` // "London"
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`     // "France"
+	User          *User                  `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	PricePaid     int32                  `protobuf:"varint,4,opt,name=price_paid,json=pricePaid,proto3" json:"price_paid,omitempty"` // in cents, so $20 = 2000
+	Seat          *Seat                  `protobuf:"bytes,5,opt,name=seat,proto3" json:"seat,omitempty"`
+	PurchasedBy   string                 `protobuf:"bytes,6,opt,name=purchased_by,json=purchasedBy,proto3" json:"purchased_by,omitempty"` // Email of the admin who booked on the passenger's behalf, empty for self-service
+	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`                              // "confirmed" or "pending_verification"
+	TicketId      string                 `protobuf:"bytes,8,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	TicketToken   string                 `protobuf:"bytes,9,opt,name=ticket_token,json=ticketToken,proto3" json:"ticket_token,omitempty"` // Signed token for the conductor, empty until the ticket is confirmed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Receipt) Reset() {
+	*x = Receipt{}
+	mi := &file_api_ticket_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Receipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Receipt) ProtoMessage() {}
+
+func (x *Receipt) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Receipt.ProtoReflect.Descriptor instead.
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *Receipt) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *Receipt) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *Receipt) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *Receipt) GetPricePaid() int32 {
+	if x != nil {
+		return x.PricePaid
+	}
+	return 0
+}
+
+func (x *Receipt) GetSeat() *Seat {
+	if x != nil {
+		return x.Seat
+	}
+	return nil
+}
+
+func (x *Receipt) GetPurchasedBy() string {
+	if x != nil {
+		return x.PurchasedBy
+	}
+	return ""
+}
+
+func (x *Receipt) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Receipt) GetTicketId() string {
+	if x != nil {
+		return x.TicketId
+	}
+	return ""
+}
+
+func (x *Receipt) GetTicketToken() string {
+	if x != nil {
+		return x.TicketToken
+	}
+	return ""
+}
+
+// User - Represents a user
+type User struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FirstName     string                 `protobuf:"bytes,1,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName      string                 `protobuf:"bytes,2,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
 	*x = User{}
-	mi := &file_api_ticket_proto_msgTypes[12]
+	mi := &file_api_ticket_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *User) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *User) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+// Seat - Represents a seat assignment
+type Seat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Section       string                 `protobuf:"bytes,1,opt,name=section,proto3" json:"section,omitempty"`                          // "A" or "B"
+	SeatNumber    int32                  `protobuf:"varint,2,opt,name=seat_number,json=seatNumber,proto3" json:"seat_number,omitempty"` // 1-10
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Seat) Reset() {
+	*x = Seat{}
+	mi := &file_api_ticket_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Seat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Seat) ProtoMessage() {}
+
+func (x *Seat) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Seat.ProtoReflect.Descriptor instead.
+func (*Seat) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *Seat) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *Seat) GetSeatNumber() int32 {
+	if x != nil {
+		return x.SeatNumber
+	}
+	return 0
+}
+
+// WatchOccupancyRequest - Request to stream occupancy updates
+type WatchOccupancyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchOccupancyRequest) Reset() {
+	*x = WatchOccupancyRequest{}
+	mi := &file_api_ticket_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchOccupancyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchOccupancyRequest) ProtoMessage() {}
+
+func (x *WatchOccupancyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchOccupancyRequest.ProtoReflect.Descriptor instead.
+func (*WatchOccupancyRequest) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{37}
+}
+
+// OccupancyUpdate - Seat availability across the train at one point in time
+type OccupancyUpdate struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Sections       []*SectionOccupancy    `protobuf:"bytes,1,rep,name=sections,proto3" json:"sections,omitempty"`
+	Holds          int32                  `protobuf:"varint,2,opt,name=holds,proto3" json:"holds,omitempty"`                                         // Seats held by unverified anonymous purchases
+	WaitlistLength int32                  `protobuf:"varint,3,opt,name=waitlist_length,json=waitlistLength,proto3" json:"waitlist_length,omitempty"` // Passengers waiting for a seat after a decommission
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *OccupancyUpdate) Reset() {
+	*x = OccupancyUpdate{}
+	mi := &file_api_ticket_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OccupancyUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OccupancyUpdate) ProtoMessage() {}
+
+func (x *OccupancyUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OccupancyUpdate.ProtoReflect.Descriptor instead.
+func (*OccupancyUpdate) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *OccupancyUpdate) GetSections() []*SectionOccupancy {
+	if x != nil {
+		return x.Sections
+	}
+	return nil
+}
+
+func (x *OccupancyUpdate) GetHolds() int32 {
+	if x != nil {
+		return x.Holds
+	}
+	return 0
+}
+
+func (x *OccupancyUpdate) GetWaitlistLength() int32 {
+	if x != nil {
+		return x.WaitlistLength
+	}
+	return 0
+}
+
+// SectionOccupancy - Seat counts for one section
+type SectionOccupancy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Section       string                 `protobuf:"bytes,1,opt,name=section,proto3" json:"section,omitempty"`
+	Occupied      int32                  `protobuf:"varint,2,opt,name=occupied,proto3" json:"occupied,omitempty"` // Seats held by confirmed tickets
+	Capacity      int32                  `protobuf:"varint,3,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	InService     bool                   `protobuf:"varint,4,opt,name=in_service,json=inService,proto3" json:"in_service,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SectionOccupancy) Reset() {
+	*x = SectionOccupancy{}
+	mi := &file_api_ticket_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SectionOccupancy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SectionOccupancy) ProtoMessage() {}
+
+func (x *SectionOccupancy) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SectionOccupancy.ProtoReflect.Descriptor instead.
+func (*SectionOccupancy) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *SectionOccupancy) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *SectionOccupancy) GetOccupied() int32 {
+	if x != nil {
+		return x.Occupied
+	}
+	return 0
+}
+
+func (x *SectionOccupancy) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *SectionOccupancy) GetInService() bool {
+	if x != nil {
+		return x.InService
+	}
+	return false
+}
+
+// RequestPurchaseChallengeRequest - Request a challenge for purchasing as email
+type RequestPurchaseChallengeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestPurchaseChallengeRequest) Reset() {
+	*x = RequestPurchaseChallengeRequest{}
+	mi := &file_api_ticket_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestPurchaseChallengeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestPurchaseChallengeRequest) ProtoMessage() {}
+
+func (x *RequestPurchaseChallengeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestPurchaseChallengeRequest.ProtoReflect.Descriptor instead.
+func (*RequestPurchaseChallengeRequest) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *RequestPurchaseChallengeRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+// RequestPurchaseChallengeResponse - A proof-of-work puzzle
+// Find a nonce where sha256(token + ":" + nonce) starts with difficulty zero bits
+type RequestPurchaseChallengeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Difficulty    int32                  `protobuf:"varint,2,opt,name=difficulty,proto3" json:"difficulty,omitempty"`
+	ExpiresAt     string                 `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // RFC 3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestPurchaseChallengeResponse) Reset() {
+	*x = RequestPurchaseChallengeResponse{}
+	mi := &file_api_ticket_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestPurchaseChallengeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestPurchaseChallengeResponse) ProtoMessage() {}
+
+func (x *RequestPurchaseChallengeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestPurchaseChallengeResponse.ProtoReflect.Descriptor instead.
+func (*RequestPurchaseChallengeResponse) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *RequestPurchaseChallengeResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *RequestPurchaseChallengeResponse) GetDifficulty() int32 {
+	if x != nil {
+		return x.Difficulty
+	}
+	return 0
+}
+
+func (x *RequestPurchaseChallengeResponse) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+// ListFlaggedPurchasesRequest - Request to list flagged purchases
+type ListFlaggedPurchasesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFlaggedPurchasesRequest) Reset() {
+	*x = ListFlaggedPurchasesRequest{}
+	mi := &file_api_ticket_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFlaggedPurchasesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFlaggedPurchasesRequest) ProtoMessage() {}
+
+func (x *ListFlaggedPurchasesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFlaggedPurchasesRequest.ProtoReflect.Descriptor instead.
+func (*ListFlaggedPurchasesRequest) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{42}
+}
+
+// ListFlaggedPurchasesResponse - Response listing flagged purchases
+type ListFlaggedPurchasesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Purchases     []*FlaggedPurchase     `protobuf:"bytes,1,rep,name=purchases,proto3" json:"purchases,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFlaggedPurchasesResponse) Reset() {
+	*x = ListFlaggedPurchasesResponse{}
+	mi := &file_api_ticket_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFlaggedPurchasesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFlaggedPurchasesResponse) ProtoMessage() {}
+
+func (x *ListFlaggedPurchasesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFlaggedPurchasesResponse.ProtoReflect.Descriptor instead.
+func (*ListFlaggedPurchasesResponse) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ListFlaggedPurchasesResponse) GetPurchases() []*FlaggedPurchase {
+	if x != nil {
+		return x.Purchases
+	}
+	return nil
+}
+
+// FlaggedPurchase - A public purchase that tripped an abuse control
+type FlaggedPurchase struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Ip            string                 `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`                        // "email_domain_cap" or "ip_velocity"
+	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`                        // "blocked", "challenged" or "solved"
+	FlaggedAt     string                 `protobuf:"bytes,5,opt,name=flagged_at,json=flaggedAt,proto3" json:"flagged_at,omitempty"` // RFC 3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlaggedPurchase) Reset() {
+	*x = FlaggedPurchase{}
+	mi := &file_api_ticket_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlaggedPurchase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlaggedPurchase) ProtoMessage() {}
+
+func (x *FlaggedPurchase) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlaggedPurchase.ProtoReflect.Descriptor instead.
+func (*FlaggedPurchase) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *FlaggedPurchase) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *FlaggedPurchase) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *FlaggedPurchase) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *FlaggedPurchase) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *FlaggedPurchase) GetFlaggedAt() string {
+	if x != nil {
+		return x.FlaggedAt
+	}
+	return ""
+}
+
+// GetSeatMapRequest - Request for the seat map
+type GetSeatMapRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional: a single section. Empty means all sections
+	Section       string `protobuf:"bytes,1,opt,name=section,proto3" json:"section,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSeatMapRequest) Reset() {
+	*x = GetSeatMapRequest{}
+	mi := &file_api_ticket_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSeatMapRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSeatMapRequest) ProtoMessage() {}
+
+func (x *GetSeatMapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSeatMapRequest.ProtoReflect.Descriptor instead.
+func (*GetSeatMapRequest) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *GetSeatMapRequest) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+// GetSeatMapResponse - Every seat of the requested sections, in layout order
+type GetSeatMapResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sections      []*SeatMapSection      `protobuf:"bytes,1,rep,name=sections,proto3" json:"sections,omitempty"`
+	SeatsPerRow   int32                  `protobuf:"varint,2,opt,name=seats_per_row,json=seatsPerRow,proto3" json:"seats_per_row,omitempty"`
+	AisleAfter    int32                  `protobuf:"varint,3,opt,name=aisle_after,json=aisleAfter,proto3" json:"aisle_after,omitempty"` // Position in a row the aisle follows, 0 if none
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSeatMapResponse) Reset() {
+	*x = GetSeatMapResponse{}
+	mi := &file_api_ticket_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSeatMapResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSeatMapResponse) ProtoMessage() {}
+
+func (x *GetSeatMapResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSeatMapResponse.ProtoReflect.Descriptor instead.
+func (*GetSeatMapResponse) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *GetSeatMapResponse) GetSections() []*SeatMapSection {
+	if x != nil {
+		return x.Sections
+	}
+	return nil
+}
+
+func (x *GetSeatMapResponse) GetSeatsPerRow() int32 {
+	if x != nil {
+		return x.SeatsPerRow
+	}
+	return 0
+}
+
+func (x *GetSeatMapResponse) GetAisleAfter() int32 {
+	if x != nil {
+		return x.AisleAfter
+	}
+	return 0
+}
+
+// SeatMapSection - The seats of one section
+type SeatMapSection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Section       string                 `protobuf:"bytes,1,opt,name=section,proto3" json:"section,omitempty"`
+	InService     bool                   `protobuf:"varint,2,opt,name=in_service,json=inService,proto3" json:"in_service,omitempty"`
+	Seats         []*SeatMapSeat         `protobuf:"bytes,3,rep,name=seats,proto3" json:"seats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SeatMapSection) Reset() {
+	*x = SeatMapSection{}
+	mi := &file_api_ticket_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeatMapSection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeatMapSection) ProtoMessage() {}
+
+func (x *SeatMapSection) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeatMapSection.ProtoReflect.Descriptor instead.
+func (*SeatMapSection) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *SeatMapSection) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *SeatMapSection) GetInService() bool {
+	if x != nil {
+		return x.InService
+	}
+	return false
+}
+
+func (x *SeatMapSection) GetSeats() []*SeatMapSeat {
+	if x != nil {
+		return x.Seats
+	}
+	return nil
+}
+
+// SeatMapSeat - One seat and whether it can be taken
+type SeatMapSeat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SeatNumber    int32                  `protobuf:"varint,1,opt,name=seat_number,json=seatNumber,proto3" json:"seat_number,omitempty"`
+	Row           int32                  `protobuf:"varint,2,opt,name=row,proto3" json:"row,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`       // "free", "held", "occupied" or "blocked"
+	Attribute     string                 `protobuf:"bytes,4,opt,name=attribute,proto3" json:"attribute,omitempty"` // "window", "aisle" or "middle"
+	Yours         bool                   `protobuf:"varint,5,opt,name=yours,proto3" json:"yours,omitempty"`        // The caller holds this seat
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SeatMapSeat) Reset() {
+	*x = SeatMapSeat{}
+	mi := &file_api_ticket_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeatMapSeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeatMapSeat) ProtoMessage() {}
+
+func (x *SeatMapSeat) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeatMapSeat.ProtoReflect.Descriptor instead.
+func (*SeatMapSeat) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *SeatMapSeat) GetSeatNumber() int32 {
+	if x != nil {
+		return x.SeatNumber
+	}
+	return 0
+}
+
+func (x *SeatMapSeat) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *SeatMapSeat) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SeatMapSeat) GetAttribute() string {
+	if x != nil {
+		return x.Attribute
+	}
+	return ""
+}
+
+func (x *SeatMapSeat) GetYours() bool {
+	if x != nil {
+		return x.Yours
+	}
+	return false
+}
+
+// ExportManifestRequest - Request to export the passenger manifest
+type ExportManifestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`                            // "csv" (default), "jsonl" or "pdf"
+	Fields        []string               `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty"`                            // Columns in order; empty means section, seat_number, first_name, last_name, email
+	MaskEmails    bool                   `protobuf:"varint,3,opt,name=mask_emails,json=maskEmails,proto3" json:"mask_emails,omitempty"` // Show j***@example.com instead of full emails
+	Section       string                 `protobuf:"bytes,4,opt,name=section,proto3" json:"section,omitempty"`                          // Optional: a single section. Empty means all sections
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportManifestRequest) Reset() {
+	*x = ExportManifestRequest{}
+	mi := &file_api_ticket_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportManifestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportManifestRequest) ProtoMessage() {}
+
+func (x *ExportManifestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportManifestRequest.ProtoReflect.Descriptor instead.
+func (*ExportManifestRequest) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ExportManifestRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ExportManifestRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *ExportManifestRequest) GetMaskEmails() bool {
+	if x != nil {
+		return x.MaskEmails
+	}
+	return false
+}
+
+func (x *ExportManifestRequest) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+// ImportBookingsRequest - A batch of rows to import
+type ImportBookingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Commit        bool                   `protobuf:"varint,1,opt,name=commit,proto3" json:"commit,omitempty"` // Book the rows if none conflict; false for a dry run. Read from the first message
+	Rows          []*BookingRow          `protobuf:"bytes,2,rep,name=rows,proto3" json:"rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportBookingsRequest) Reset() {
+	*x = ImportBookingsRequest{}
+	mi := &file_api_ticket_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportBookingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportBookingsRequest) ProtoMessage() {}
+
+func (x *ImportBookingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportBookingsRequest.ProtoReflect.Descriptor instead.
+func (*ImportBookingsRequest) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ImportBookingsRequest) GetCommit() bool {
+	if x != nil {
+		return x.Commit
+	}
+	return false
+}
+
+func (x *ImportBookingsRequest) GetRows() []*BookingRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+// BookingRow - An existing booking: a passenger and their seat
+type BookingRow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Row           int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"` // Optional: number to report conflicts against, e.g. the CSV line. Defaults to the position in the import
+	FirstName     string                 `protobuf:"bytes,2,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName      string                 `protobuf:"bytes,3,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Email         string                 `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
+	Section       string                 `protobuf:"bytes,5,opt,name=section,proto3" json:"section,omitempty"`
+	SeatNumber    int32                  `protobuf:"varint,6,opt,name=seat_number,json=seatNumber,proto3" json:"seat_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BookingRow) Reset() {
+	*x = BookingRow{}
+	mi := &file_api_ticket_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BookingRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookingRow) ProtoMessage() {}
+
+func (x *BookingRow) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookingRow.ProtoReflect.Descriptor instead.
+func (*BookingRow) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *BookingRow) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *BookingRow) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *BookingRow) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *BookingRow) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *BookingRow) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *BookingRow) GetSeatNumber() int32 {
+	if x != nil {
+		return x.SeatNumber
+	}
+	return 0
+}
+
+// ImportBookingsResponse - Outcome of an import
+type ImportBookingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Committed     bool                   `protobuf:"varint,1,opt,name=committed,proto3" json:"committed,omitempty"` // False for a dry run or when any row conflicts
+	TotalRows     int32                  `protobuf:"varint,2,opt,name=total_rows,json=totalRows,proto3" json:"total_rows,omitempty"`
+	ValidRows     int32                  `protobuf:"varint,3,opt,name=valid_rows,json=validRows,proto3" json:"valid_rows,omitempty"` // Rows without conflicts
+	Conflicts     []*ImportConflict      `protobuf:"bytes,4,rep,name=conflicts,proto3" json:"conflicts,omitempty"`                   // In row order
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportBookingsResponse) Reset() {
+	*x = ImportBookingsResponse{}
+	mi := &file_api_ticket_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportBookingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportBookingsResponse) ProtoMessage() {}
+
+func (x *ImportBookingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportBookingsResponse.ProtoReflect.Descriptor instead.
+func (*ImportBookingsResponse) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ImportBookingsResponse) GetCommitted() bool {
+	if x != nil {
+		return x.Committed
+	}
+	return false
+}
+
+func (x *ImportBookingsResponse) GetTotalRows() int32 {
+	if x != nil {
+		return x.TotalRows
+	}
+	return 0
+}
+
+func (x *ImportBookingsResponse) GetValidRows() int32 {
+	if x != nil {
+		return x.ValidRows
+	}
+	return 0
+}
+
+func (x *ImportBookingsResponse) GetConflicts() []*ImportConflict {
+	if x != nil {
+		return x.Conflicts
+	}
+	return nil
+}
+
+// ImportConflict - Why one row cannot be imported
+type ImportConflict struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Row            int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	Field          string                 `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`   // The field at fault, e.g. "email" or "seat_number"
+	Reason         string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // An ErrorReason name such as "SEAT_OCCUPIED"; empty for invalid input
+	Description    string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	ConflictingRow int32                  `protobuf:"varint,5,opt,name=conflicting_row,json=conflictingRow,proto3" json:"conflicting_row,omitempty"` // The earlier row in this import it clashes with, 0 if it clashes with an existing booking
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ImportConflict) Reset() {
+	*x = ImportConflict{}
+	mi := &file_api_ticket_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportConflict) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportConflict) ProtoMessage() {}
+
+func (x *ImportConflict) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportConflict.ProtoReflect.Descriptor instead.
+func (*ImportConflict) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ImportConflict) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *ImportConflict) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *ImportConflict) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ImportConflict) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ImportConflict) GetConflictingRow() int32 {
+	if x != nil {
+		return x.ConflictingRow
+	}
+	return 0
+}
+
+// DownloadReceiptRequest - Request for the caller's e-ticket
+type DownloadReceiptRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"` // "html" (default) or "pdf"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadReceiptRequest) Reset() {
+	*x = DownloadReceiptRequest{}
+	mi := &file_api_ticket_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *User) String() string {
+func (x *DownloadReceiptRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*User) ProtoMessage() {}
+func (*DownloadReceiptRequest) ProtoMessage() {}
 
-func (x *User) ProtoReflect() protoreflect.Message {
-	mi := &file_api_ticket_proto_msgTypes[12]
+func (x *DownloadReceiptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -679,56 +3358,173 @@ func (x *User) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use User.ProtoReflect.Descriptor instead.
-func (*User) Descriptor() ([]byte, []int) {
-	return file_api_ticket_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use DownloadReceiptRequest.ProtoReflect.Descriptor instead.
+func (*DownloadReceiptRequest) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{54}
 }
 
-func (x *User) GetFirstName() string {
+func (x *DownloadReceiptRequest) GetFormat() string {
 	if x != nil {
-		return x.FirstName
+		return x.Format
 	}
 	return ""
 }
 
-func (x *User) GetLastName() string {
+// SyncRevocationsRequest - Request for the revocations after a cursor
+type SyncRevocationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Since         int64                  `protobuf:"varint,1,opt,name=since,proto3" json:"since,omitempty"` // Cursor from the last sync, 0 for all
+	Epoch         string                 `protobuf:"bytes,2,opt,name=epoch,proto3" json:"epoch,omitempty"`  // Epoch from the last sync; since is ignored when it is not the server's
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncRevocationsRequest) Reset() {
+	*x = SyncRevocationsRequest{}
+	mi := &file_api_ticket_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncRevocationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncRevocationsRequest) ProtoMessage() {}
+
+func (x *SyncRevocationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[55]
 	if x != nil {
-		return x.LastName
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncRevocationsRequest.ProtoReflect.Descriptor instead.
+func (*SyncRevocationsRequest) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *SyncRevocationsRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *SyncRevocationsRequest) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
 	}
 	return ""
 }
 
-func (x *User) GetEmail() string {
+// SyncRevocationsResponse - What a conductor needs to verify tickets offline
+type SyncRevocationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Train         string                 `protobuf:"bytes,1,opt,name=train,proto3" json:"train,omitempty"` // Train the tokens must name
+	Keys          []*TicketKey           `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`
+	Revocations   []*Revocation          `protobuf:"bytes,3,rep,name=revocations,proto3" json:"revocations,omitempty"`
+	Cursor        int64                  `protobuf:"varint,4,opt,name=cursor,proto3" json:"cursor,omitempty"` // Pass as since on the next sync
+	Epoch         string                 `protobuf:"bytes,5,opt,name=epoch,proto3" json:"epoch,omitempty"`    // Pass as epoch on the next sync; changes when the server restarts
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncRevocationsResponse) Reset() {
+	*x = SyncRevocationsResponse{}
+	mi := &file_api_ticket_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncRevocationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncRevocationsResponse) ProtoMessage() {}
+
+func (x *SyncRevocationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[56]
 	if x != nil {
-		return x.Email
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncRevocationsResponse.ProtoReflect.Descriptor instead.
+func (*SyncRevocationsResponse) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *SyncRevocationsResponse) GetTrain() string {
+	if x != nil {
+		return x.Train
 	}
 	return ""
 }
 
-// Seat - Represents a seat assignment
-type Seat struct {
+func (x *SyncRevocationsResponse) GetKeys() []*TicketKey {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *SyncRevocationsResponse) GetRevocations() []*Revocation {
+	if x != nil {
+		return x.Revocations
+	}
+	return nil
+}
+
+func (x *SyncRevocationsResponse) GetCursor() int64 {
+	if x != nil {
+		return x.Cursor
+	}
+	return 0
+}
+
+func (x *SyncRevocationsResponse) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+// TicketKey - A public key verifying ticket tokens
+type TicketKey struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Section       string                 `protobuf:"bytes,1,opt,name=section,proto3" json:"section,omitempty"`                          // "A" or "B"
-	SeatNumber    int32                  `protobuf:"varint,2,opt,name=seat_number,json=seatNumber,proto3" json:"seat_number,omitempty"` // 1-10
+	KeyId         string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`             // The kid header of the tokens it signs
+	Algorithm     string                 `protobuf:"bytes,2,opt,name=algorithm,proto3" json:"algorithm,omitempty"`                  // "EdDSA"
+	PublicKey     []byte                 `protobuf:"bytes,3,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"` // Raw 32-byte Ed25519 key
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Seat) Reset() {
-	*x = Seat{}
-	mi := &file_api_ticket_proto_msgTypes[13]
+func (x *TicketKey) Reset() {
+	*x = TicketKey{}
+	mi := &file_api_ticket_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Seat) String() string {
+func (x *TicketKey) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Seat) ProtoMessage() {}
+func (*TicketKey) ProtoMessage() {}
 
-func (x *Seat) ProtoReflect() protoreflect.Message {
-	mi := &file_api_ticket_proto_msgTypes[13]
+func (x *TicketKey) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -739,50 +3535,196 @@ func (x *Seat) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Seat.ProtoReflect.Descriptor instead.
-func (*Seat) Descriptor() ([]byte, []int) {
-	return file_api_ticket_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use TicketKey.ProtoReflect.Descriptor instead.
+func (*TicketKey) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{57}
 }
 
-func (x *Seat) GetSection() string {
+func (x *TicketKey) GetKeyId() string {
 	if x != nil {
-		return x.Section
+		return x.KeyId
 	}
 	return ""
 }
 
-func (x *Seat) GetSeatNumber() int32 {
+func (x *TicketKey) GetAlgorithm() string {
 	if x != nil {
-		return x.SeatNumber
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *TicketKey) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+// Revocation - Tokens of a ticket that are no longer valid
+type Revocation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seq           int64                  `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	TicketId      string                 `protobuf:"bytes,2,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	Revision      int32                  `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`                   // Tokens with this revision or lower are revoked
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`                        // "seat_changed" or "removed"
+	RevokedAt     string                 `protobuf:"bytes,5,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"` // RFC 3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Revocation) Reset() {
+	*x = Revocation{}
+	mi := &file_api_ticket_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Revocation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Revocation) ProtoMessage() {}
+
+func (x *Revocation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Revocation.ProtoReflect.Descriptor instead.
+func (*Revocation) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *Revocation) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *Revocation) GetTicketId() string {
+	if x != nil {
+		return x.TicketId
+	}
+	return ""
+}
+
+func (x *Revocation) GetRevision() int32 {
+	if x != nil {
+		return x.Revision
 	}
 	return 0
 }
 
+func (x *Revocation) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Revocation) GetRevokedAt() string {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return ""
+}
+
+// SeatSuggestions - Error detail listing free seats to try instead, nearest first
+type SeatSuggestions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seats         []*Seat                `protobuf:"bytes,1,rep,name=seats,proto3" json:"seats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SeatSuggestions) Reset() {
+	*x = SeatSuggestions{}
+	mi := &file_api_ticket_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SeatSuggestions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SeatSuggestions) ProtoMessage() {}
+
+func (x *SeatSuggestions) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ticket_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SeatSuggestions.ProtoReflect.Descriptor instead.
+func (*SeatSuggestions) Descriptor() ([]byte, []int) {
+	return file_api_ticket_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *SeatSuggestions) GetSeats() []*Seat {
+	if x != nil {
+		return x.Seats
+	}
+	return nil
+}
+
 var File_api_ticket_proto protoreflect.FileDescriptor
 
 const file_api_ticket_proto_rawDesc = "" +
 	"\n" +
-	"\x10api/ticket.proto\x12\x06ticket\"i\n" +
+	"\x10api/ticket.proto\x12\x06ticket\x1a\x1cgoogle/api/annotations.proto\x1a\x19google/api/httpbody.proto\"\xbb\x01\n" +
 	"\x15PurchaseTicketRequest\x12\x1d\n" +
 	"\n" +
 	"first_name\x18\x01 \x01(\tR\tfirstName\x12\x1b\n" +
 	"\tlast_name\x18\x02 \x01(\tR\blastName\x12\x14\n" +
-	"\x05email\x18\x03 \x01(\tR\x05email\"C\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12'\n" +
+	"\x0fchallenge_token\x18\x04 \x01(\tR\x0echallengeToken\x12'\n" +
+	"\x0fchallenge_nonce\x18\x05 \x01(\tR\x0echallengeNonce\"C\n" +
 	"\x16PurchaseTicketResponse\x12)\n" +
+	"\areceipt\x18\x01 \x01(\v2\x0f.ticket.ReceiptR\areceipt\"A\n" +
+	"\x15VerifyPurchaseRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"C\n" +
+	"\x16VerifyPurchaseResponse\x12)\n" +
 	"\areceipt\x18\x01 \x01(\v2\x0f.ticket.ReceiptR\areceipt\"\x18\n" +
 	"\x16ViewUserReceiptRequest\"D\n" +
 	"\x17ViewUserReceiptResponse\x12)\n" +
-	"\areceipt\x18\x01 \x01(\v2\x0f.ticket.ReceiptR\areceipt\"2\n" +
+	"\areceipt\x18\x01 \x01(\v2\x0f.ticket.ReceiptR\areceipt\"\xb9\x01\n" +
 	"\x16ViewAllocationsRequest\x12\x18\n" +
-	"\asection\x18\x01 \x01(\tR\asection\"O\n" +
+	"\asection\x18\x01 \x01(\tR\asection\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12\x19\n" +
+	"\border_by\x18\x04 \x01(\tR\aorderBy\x12\x16\n" +
+	"\x06search\x18\x05 \x01(\tR\x06search\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\"\x96\x01\n" +
 	"\x17ViewAllocationsResponse\x124\n" +
-	"\vallocations\x18\x01 \x03(\v2\x12.ticket.AllocationR\vallocations\"i\n" +
+	"\vallocations\x18\x01 \x03(\v2\x12.ticket.AllocationR\vallocations\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize\"\x81\x01\n" +
 	"\n" +
 	"Allocation\x12\x18\n" +
 	"\asection\x18\x01 \x01(\tR\asection\x12\x1f\n" +
 	"\vseat_number\x18\x02 \x01(\x05R\n" +
 	"seatNumber\x12 \n" +
-	"\x04user\x18\x03 \x01(\v2\f.ticket.UserR\x04user\"2\n" +
+	"\x04user\x18\x03 \x01(\v2\f.ticket.UserR\x04user\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\"2\n" +
 	"\x1aRemoveUserFromTrainRequest\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\"Q\n" +
 	"\x1bRemoveUserFromTrainResponse\x12\x18\n" +
@@ -794,14 +3736,95 @@ const file_api_ticket_proto_rawDesc = "" +
 	"\vseat_number\x18\x03 \x01(\x05R\n" +
 	"seatNumber\"C\n" +
 	"\x16ModifyUserSeatResponse\x12)\n" +
-	"\areceipt\x18\x01 \x01(\v2\x0f.ticket.ReceiptR\areceipt\"\x90\x01\n" +
+	"\areceipt\x18\x01 \x01(\v2\x0f.ticket.ReceiptR\areceipt\"s\n" +
+	"\x16RequestSeatSwapRequest\x12-\n" +
+	"\x12counterparty_email\x18\x01 \x01(\tR\x11counterpartyEmail\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x14\n" +
+	"\x05force\x18\x03 \x01(\bR\x05force\"?\n" +
+	"\x17RequestSeatSwapResponse\x12$\n" +
+	"\x04swap\x18\x01 \x01(\v2\x10.ticket.SeatSwapR\x04swap\"0\n" +
+	"\x15AcceptSeatSwapRequest\x12\x17\n" +
+	"\aswap_id\x18\x01 \x01(\tR\x06swapId\">\n" +
+	"\x16AcceptSeatSwapResponse\x12$\n" +
+	"\x04swap\x18\x01 \x01(\v2\x10.ticket.SeatSwapR\x04swap\"\xfa\x01\n" +
+	"\bSeatSwap\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0frequester_email\x18\x02 \x01(\tR\x0erequesterEmail\x123\n" +
+	"\x0erequester_seat\x18\x03 \x01(\v2\f.ticket.SeatR\rrequesterSeat\x12-\n" +
+	"\x12counterparty_email\x18\x04 \x01(\tR\x11counterpartyEmail\x129\n" +
+	"\x11counterparty_seat\x18\x05 \x01(\v2\f.ticket.SeatR\x10counterpartySeat\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\"j\n" +
+	"\x10BulkApplyRequest\x125\n" +
+	"\n" +
+	"operations\x18\x01 \x03(\v2\x15.ticket.BulkOperationR\n" +
+	"operations\x12\x1f\n" +
+	"\vbest_effort\x18\x02 \x01(\bR\n" +
+	"bestEffort\"\xb5\x01\n" +
+	"\rBulkOperation\x121\n" +
+	"\x06remove\x18\x01 \x01(\v2\x17.ticket.RemoveOperationH\x00R\x06remove\x12+\n" +
+	"\x04move\x18\x02 \x01(\v2\x15.ticket.MoveOperationH\x00R\x04move\x127\n" +
+	"\breassign\x18\x03 \x01(\v2\x19.ticket.ReassignOperationH\x00R\breassignB\v\n" +
+	"\toperation\"'\n" +
+	"\x0fRemoveOperation\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"`\n" +
+	"\rMoveOperation\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x18\n" +
+	"\asection\x18\x02 \x01(\tR\asection\x12\x1f\n" +
+	"\vseat_number\x18\x03 \x01(\x05R\n" +
+	"seatNumber\"C\n" +
+	"\x11ReassignOperation\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x18\n" +
+	"\asection\x18\x02 \x01(\tR\asection\"[\n" +
+	"\x11BulkApplyResponse\x12,\n" +
+	"\aresults\x18\x01 \x03(\v2\x12.ticket.BulkResultR\aresults\x12\x18\n" +
+	"\aapplied\x18\x02 \x01(\bR\aapplied\"\xa4\x01\n" +
+	"\n" +
+	"BulkResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12 \n" +
+	"\x04seat\x18\x05 \x01(\v2\f.ticket.SeatR\x04seat\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\"_\n" +
+	"\x1aDecommissionSectionRequest\x12\x18\n" +
+	"\asection\x18\x01 \x01(\tR\asection\x12'\n" +
+	"\x0foverflow_policy\x18\x02 \x01(\tR\x0eoverflowPolicy\"T\n" +
+	"\x1bDecommissionSectionResponse\x125\n" +
+	"\x06report\x18\x01 \x01(\v2\x1d.ticket.ReaccommodationReportR\x06report\"3\n" +
+	"\x17ReinstateSectionRequest\x12\x18\n" +
+	"\asection\x18\x01 \x01(\tR\asection\"D\n" +
+	"\x18ReinstateSectionResponse\x12(\n" +
+	"\x06placed\x18\x01 \x03(\v2\x10.ticket.SeatMoveR\x06placed\"\xcd\x01\n" +
+	"\x15ReaccommodationReport\x12\x18\n" +
+	"\asection\x18\x01 \x01(\tR\asection\x12&\n" +
+	"\x05moved\x18\x02 \x03(\v2\x10.ticket.SeatMoveR\x05moved\x12:\n" +
+	"\n" +
+	"waitlisted\x18\x03 \x03(\v2\x1a.ticket.DisplacedPassengerR\n" +
+	"waitlisted\x126\n" +
+	"\brefunded\x18\x04 \x03(\v2\x1a.ticket.DisplacedPassengerR\brefunded\"l\n" +
+	"\bSeatMove\x12 \n" +
+	"\x04user\x18\x01 \x01(\v2\f.ticket.UserR\x04user\x12 \n" +
+	"\x04from\x18\x02 \x01(\v2\f.ticket.SeatR\x04from\x12\x1c\n" +
+	"\x02to\x18\x03 \x01(\v2\f.ticket.SeatR\x02to\"{\n" +
+	"\x12DisplacedPassenger\x12 \n" +
+	"\x04user\x18\x01 \x01(\v2\f.ticket.UserR\x04user\x12 \n" +
+	"\x04from\x18\x02 \x01(\v2\f.ticket.SeatR\x04from\x12!\n" +
+	"\frefund_cents\x18\x03 \x01(\x05R\vrefundCents\"H\n" +
+	"\x1aAdminPurchaseTicketRequest\x12*\n" +
+	"\tpassenger\x18\x01 \x01(\v2\f.ticket.UserR\tpassenger\"H\n" +
+	"\x1bAdminPurchaseTicketResponse\x12)\n" +
+	"\areceipt\x18\x01 \x01(\v2\x0f.ticket.ReceiptR\areceipt\"\x8b\x02\n" +
 	"\aReceipt\x12\x12\n" +
 	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
 	"\x02to\x18\x02 \x01(\tR\x02to\x12 \n" +
 	"\x04user\x18\x03 \x01(\v2\f.ticket.UserR\x04user\x12\x1d\n" +
 	"\n" +
 	"price_paid\x18\x04 \x01(\x05R\tpricePaid\x12 \n" +
-	"\x04seat\x18\x05 \x01(\v2\f.ticket.SeatR\x04seat\"X\n" +
+	"\x04seat\x18\x05 \x01(\v2\f.ticket.SeatR\x04seat\x12!\n" +
+	"\fpurchased_by\x18\x06 \x01(\tR\vpurchasedBy\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x1b\n" +
+	"\tticket_id\x18\b \x01(\tR\bticketId\x12!\n" +
+	"\fticket_token\x18\t \x01(\tR\vticketToken\"X\n" +
 	"\x04User\x12\x1d\n" +
 	"\n" +
 	"first_name\x18\x01 \x01(\tR\tfirstName\x12\x1b\n" +
@@ -810,13 +3833,167 @@ const file_api_ticket_proto_rawDesc = "" +
 	"\x04Seat\x12\x18\n" +
 	"\asection\x18\x01 \x01(\tR\asection\x12\x1f\n" +
 	"\vseat_number\x18\x02 \x01(\x05R\n" +
-	"seatNumber2\xb9\x03\n" +
-	"\rTicketService\x12O\n" +
-	"\x0ePurchaseTicket\x12\x1d.ticket.PurchaseTicketRequest\x1a\x1e.ticket.PurchaseTicketResponse\x12R\n" +
-	"\x0fViewUserReceipt\x12\x1e.ticket.ViewUserReceiptRequest\x1a\x1f.ticket.ViewUserReceiptResponse\x12R\n" +
-	"\x0fViewAllocations\x12\x1e.ticket.ViewAllocationsRequest\x1a\x1f.ticket.ViewAllocationsResponse\x12^\n" +
-	"\x13RemoveUserFromTrain\x12\".ticket.RemoveUserFromTrainRequest\x1a#.ticket.RemoveUserFromTrainResponse\x12O\n" +
-	"\x0eModifyUserSeat\x12\x1d.ticket.ModifyUserSeatRequest\x1a\x1e.ticket.ModifyUserSeatResponseB6Z4github.com/cloudbees/train-ticket-service/api/ticketb\x06proto3"
+	"seatNumber\"\x17\n" +
+	"\x15WatchOccupancyRequest\"\x86\x01\n" +
+	"\x0fOccupancyUpdate\x124\n" +
+	"\bsections\x18\x01 \x03(\v2\x18.ticket.SectionOccupancyR\bsections\x12\x14\n" +
+	"\x05holds\x18\x02 \x01(\x05R\x05holds\x12'\n" +
+	"\x0fwaitlist_length\x18\x03 \x01(\x05R\x0ewaitlistLength\"\x83\x01\n" +
+	"\x10SectionOccupancy\x12\x18\n" +
+	"\asection\x18\x01 \x01(\tR\asection\x12\x1a\n" +
+	"\boccupied\x18\x02 \x01(\x05R\boccupied\x12\x1a\n" +
+	"\bcapacity\x18\x03 \x01(\x05R\bcapacity\x12\x1d\n" +
+	"\n" +
+	"in_service\x18\x04 \x01(\bR\tinService\"7\n" +
+	"\x1fRequestPurchaseChallengeRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"w\n" +
+	" RequestPurchaseChallengeResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1e\n" +
+	"\n" +
+	"difficulty\x18\x02 \x01(\x05R\n" +
+	"difficulty\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\tR\texpiresAt\"\x1d\n" +
+	"\x1bListFlaggedPurchasesRequest\"U\n" +
+	"\x1cListFlaggedPurchasesResponse\x125\n" +
+	"\tpurchases\x18\x01 \x03(\v2\x17.ticket.FlaggedPurchaseR\tpurchases\"\x86\x01\n" +
+	"\x0fFlaggedPurchase\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x0e\n" +
+	"\x02ip\x18\x02 \x01(\tR\x02ip\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12\x1d\n" +
+	"\n" +
+	"flagged_at\x18\x05 \x01(\tR\tflaggedAt\"-\n" +
+	"\x11GetSeatMapRequest\x12\x18\n" +
+	"\asection\x18\x01 \x01(\tR\asection\"\x8d\x01\n" +
+	"\x12GetSeatMapResponse\x122\n" +
+	"\bsections\x18\x01 \x03(\v2\x16.ticket.SeatMapSectionR\bsections\x12\"\n" +
+	"\rseats_per_row\x18\x02 \x01(\x05R\vseatsPerRow\x12\x1f\n" +
+	"\vaisle_after\x18\x03 \x01(\x05R\n" +
+	"aisleAfter\"t\n" +
+	"\x0eSeatMapSection\x12\x18\n" +
+	"\asection\x18\x01 \x01(\tR\asection\x12\x1d\n" +
+	"\n" +
+	"in_service\x18\x02 \x01(\bR\tinService\x12)\n" +
+	"\x05seats\x18\x03 \x03(\v2\x13.ticket.SeatMapSeatR\x05seats\"\x8c\x01\n" +
+	"\vSeatMapSeat\x12\x1f\n" +
+	"\vseat_number\x18\x01 \x01(\x05R\n" +
+	"seatNumber\x12\x10\n" +
+	"\x03row\x18\x02 \x01(\x05R\x03row\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1c\n" +
+	"\tattribute\x18\x04 \x01(\tR\tattribute\x12\x14\n" +
+	"\x05yours\x18\x05 \x01(\bR\x05yours\"\x82\x01\n" +
+	"\x15ExportManifestRequest\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12\x16\n" +
+	"\x06fields\x18\x02 \x03(\tR\x06fields\x12\x1f\n" +
+	"\vmask_emails\x18\x03 \x01(\bR\n" +
+	"maskEmails\x12\x18\n" +
+	"\asection\x18\x04 \x01(\tR\asection\"W\n" +
+	"\x15ImportBookingsRequest\x12\x16\n" +
+	"\x06commit\x18\x01 \x01(\bR\x06commit\x12&\n" +
+	"\x04rows\x18\x02 \x03(\v2\x12.ticket.BookingRowR\x04rows\"\xab\x01\n" +
+	"\n" +
+	"BookingRow\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x05R\x03row\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x02 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x03 \x01(\tR\blastName\x12\x14\n" +
+	"\x05email\x18\x04 \x01(\tR\x05email\x12\x18\n" +
+	"\asection\x18\x05 \x01(\tR\asection\x12\x1f\n" +
+	"\vseat_number\x18\x06 \x01(\x05R\n" +
+	"seatNumber\"\xaa\x01\n" +
+	"\x16ImportBookingsResponse\x12\x1c\n" +
+	"\tcommitted\x18\x01 \x01(\bR\tcommitted\x12\x1d\n" +
+	"\n" +
+	"total_rows\x18\x02 \x01(\x05R\ttotalRows\x12\x1d\n" +
+	"\n" +
+	"valid_rows\x18\x03 \x01(\x05R\tvalidRows\x124\n" +
+	"\tconflicts\x18\x04 \x03(\v2\x16.ticket.ImportConflictR\tconflicts\"\x9b\x01\n" +
+	"\x0eImportConflict\x12\x10\n" +
+	"\x03row\x18\x01 \x01(\x05R\x03row\x12\x14\n" +
+	"\x05field\x18\x02 \x01(\tR\x05field\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12'\n" +
+	"\x0fconflicting_row\x18\x05 \x01(\x05R\x0econflictingRow\"0\n" +
+	"\x16DownloadReceiptRequest\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\"D\n" +
+	"\x16SyncRevocationsRequest\x12\x14\n" +
+	"\x05since\x18\x01 \x01(\x03R\x05since\x12\x14\n" +
+	"\x05epoch\x18\x02 \x01(\tR\x05epoch\"\xba\x01\n" +
+	"\x17SyncRevocationsResponse\x12\x14\n" +
+	"\x05train\x18\x01 \x01(\tR\x05train\x12%\n" +
+	"\x04keys\x18\x02 \x03(\v2\x11.ticket.TicketKeyR\x04keys\x124\n" +
+	"\vrevocations\x18\x03 \x03(\v2\x12.ticket.RevocationR\vrevocations\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\x03R\x06cursor\x12\x14\n" +
+	"\x05epoch\x18\x05 \x01(\tR\x05epoch\"_\n" +
+	"\tTicketKey\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x1c\n" +
+	"\talgorithm\x18\x02 \x01(\tR\talgorithm\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x03 \x01(\fR\tpublicKey\"\x8e\x01\n" +
+	"\n" +
+	"Revocation\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x03R\x03seq\x12\x1b\n" +
+	"\tticket_id\x18\x02 \x01(\tR\bticketId\x12\x1a\n" +
+	"\brevision\x18\x03 \x01(\x05R\brevision\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"revoked_at\x18\x05 \x01(\tR\trevokedAt\"5\n" +
+	"\x0fSeatSuggestions\x12\"\n" +
+	"\x05seats\x18\x01 \x03(\v2\f.ticket.SeatR\x05seats*\xf6\x04\n" +
+	"\vErrorReason\x12\x1c\n" +
+	"\x18ERROR_REASON_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10TICKET_NOT_FOUND\x10\x01\x12\x14\n" +
+	"\x10DUPLICATE_TICKET\x10\x02\x12\x11\n" +
+	"\rSEAT_OCCUPIED\x10\x03\x12\x0e\n" +
+	"\n" +
+	"TRAIN_FULL\x10\x04\x12\x10\n" +
+	"\fSECTION_FULL\x10\x05\x12\x10\n" +
+	"\fINVALID_SEAT\x10\x06\x12\x1a\n" +
+	"\x16SECTION_OUT_OF_SERVICE\x10\a\x12\x16\n" +
+	"\x12SECTION_IN_SERVICE\x10\b\x12\x16\n" +
+	"\x12TICKET_NOT_PENDING\x10\t\x12\x10\n" +
+	"\fHOLD_EXPIRED\x10\n" +
+	"\x12\x1a\n" +
+	"\x16VERIFICATION_NOT_FOUND\x10\v\x12\x18\n" +
+	"\x14VERIFICATION_EXPIRED\x10\f\x12\x19\n" +
+	"\x15VERIFICATION_MISMATCH\x10\r\x12\"\n" +
+	"\x1eVERIFICATION_ATTEMPTS_EXCEEDED\x10\x0e\x12\x12\n" +
+	"\x0eSWAP_NOT_FOUND\x10\x0f\x12\x12\n" +
+	"\x0eSWAP_WITH_SELF\x10\x10\x12\x0e\n" +
+	"\n" +
+	"SWAP_STALE\x10\x11\x12\x15\n" +
+	"\x11BATCH_ROLLED_BACK\x10\x12\x12\x14\n" +
+	"\x10EMAIL_DOMAIN_CAP\x10\x13\x12\x18\n" +
+	"\x14IP_PURCHASES_BLOCKED\x10\x14\x12\x16\n" +
+	"\x12CHALLENGE_REQUIRED\x10\x15\x12\x15\n" +
+	"\x11CHALLENGE_INVALID\x10\x16\x12\x15\n" +
+	"\x11CHALLENGE_EXPIRED\x10\x17\x12\x12\n" +
+	"\x0eCHALLENGE_USED\x10\x18\x12\x16\n" +
+	"\x12CHALLENGE_UNSOLVED\x10\x19\x12\x12\n" +
+	"\x0eTICKET_PENDING\x10\x1a2\xac\x12\n" +
+	"\rTicketService\x12g\n" +
+	"\x0ePurchaseTicket\x12\x1d.ticket.PurchaseTicketRequest\x1a\x1e.ticket.PurchaseTicketResponse\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/v1/tickets\x12n\n" +
+	"\x0eVerifyPurchase\x12\x1d.ticket.VerifyPurchaseRequest\x1a\x1e.ticket.VerifyPurchaseResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/tickets/verify\x12j\n" +
+	"\x0fViewUserReceipt\x12\x1e.ticket.ViewUserReceiptRequest\x1a\x1f.ticket.ViewUserReceiptResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/me/receipt\x12k\n" +
+	"\x0fViewAllocations\x12\x1e.ticket.ViewAllocationsRequest\x1a\x1f.ticket.ViewAllocationsResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/allocations\x12\x8c\x01\n" +
+	"\x13RemoveUserFromTrain\x12\".ticket.RemoveUserFromTrainRequest\x1a#.ticket.RemoveUserFromTrainResponse\",\x82\xd3\xe4\x93\x02&Z\x0f*\r/v1/me/ticket*\x13/v1/tickets/{email}\x12\x86\x01\n" +
+	"\x0eModifyUserSeat\x12\x1d.ticket.ModifyUserSeatRequest\x1a\x1e.ticket.ModifyUserSeatResponse\"5\x82\xd3\xe4\x93\x02/:\x01*Z\x10:\x01*2\v/v1/me/seat2\x18/v1/tickets/{email}/seat\x12h\n" +
+	"\x0fRequestSeatSwap\x12\x1e.ticket.RequestSeatSwapRequest\x1a\x1f.ticket.RequestSeatSwapResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1/swaps\x12s\n" +
+	"\x0eAcceptSeatSwap\x12\x1d.ticket.AcceptSeatSwapRequest\x1a\x1e.ticket.AcceptSeatSwapResponse\"\"\x82\xd3\xe4\x93\x02\x1c\"\x1a/v1/swaps/{swap_id}/accept\x12U\n" +
+	"\tBulkApply\x12\x18.ticket.BulkApplyRequest\x1a\x19.ticket.BulkApplyResponse\"\x13\x82\xd3\xe4\x93\x02\r:\x01*\"\b/v1/bulk\x12\x8e\x01\n" +
+	"\x13DecommissionSection\x12\".ticket.DecommissionSectionRequest\x1a#.ticket.DecommissionSectionResponse\".\x82\xd3\xe4\x93\x02(:\x01*\"#/v1/sections/{section}/decommission\x12\x7f\n" +
+	"\x10ReinstateSection\x12\x1f.ticket.ReinstateSectionRequest\x1a .ticket.ReinstateSectionResponse\"(\x82\xd3\xe4\x93\x02\"\" /v1/sections/{section}/reinstate\x12|\n" +
+	"\x13AdminPurchaseTicket\x12\".ticket.AdminPurchaseTicketRequest\x1a#.ticket.AdminPurchaseTicketResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/admin/tickets\x12a\n" +
+	"\x0eWatchOccupancy\x12\x1d.ticket.WatchOccupancyRequest\x1a\x17.ticket.OccupancyUpdate\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/occupancy0\x01\x12\x8f\x01\n" +
+	"\x18RequestPurchaseChallenge\x12'.ticket.RequestPurchaseChallengeRequest\x1a(.ticket.RequestPurchaseChallengeResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/tickets/challenge\x12\x86\x01\n" +
+	"\x14ListFlaggedPurchases\x12#.ticket.ListFlaggedPurchasesRequest\x1a$.ticket.ListFlaggedPurchasesResponse\"#\x82\xd3\xe4\x93\x02\x1d\x12\x1b/v1/admin/flagged-purchases\x12X\n" +
+	"\n" +
+	"GetSeatMap\x12\x19.ticket.GetSeatMapRequest\x1a\x1a.ticket.GetSeatMapResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/v1/seatmap\x12c\n" +
+	"\x0eExportManifest\x12\x1d.ticket.ExportManifestRequest\x1a\x14.google.api.HttpBody\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/admin/manifest0\x01\x12w\n" +
+	"\x0eImportBookings\x12\x1d.ticket.ImportBookingsRequest\x1a\x1e.ticket.ImportBookingsResponse\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/admin/bookings/import(\x01\x12h\n" +
+	"\x0fDownloadReceipt\x12\x1e.ticket.DownloadReceiptRequest\x1a\x14.google.api.HttpBody\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/v1/me/receipt/download\x12k\n" +
+	"\x0fSyncRevocations\x12\x1e.ticket.SyncRevocationsRequest\x1a\x1f.ticket.SyncRevocationsResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/revocationsB6Z4github.com/cloudbees/train-ticket-service/api/ticketb\x06proto3"
 
 var (
 	file_api_ticket_proto_rawDescOnce sync.Once
@@ -830,46 +4007,157 @@ func file_api_ticket_proto_rawDescGZIP() []byte {
 	return file_api_ticket_proto_rawDescData
 }
 
-var file_api_ticket_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_api_ticket_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_ticket_proto_msgTypes = make([]protoimpl.MessageInfo, 60)
 var file_api_ticket_proto_goTypes = []any{
-	(*PurchaseTicketRequest)(nil),       // 0: ticket.PurchaseTicketRequest
-	(*PurchaseTicketResponse)(nil),      // 1: ticket.PurchaseTicketResponse
-	(*ViewUserReceiptRequest)(nil),      // 2: ticket.ViewUserReceiptRequest
-	(*ViewUserReceiptResponse)(nil),     // 3: ticket.ViewUserReceiptResponse
-	(*ViewAllocationsRequest)(nil),      // 4: ticket.ViewAllocationsRequest
-	(*ViewAllocationsResponse)(nil),     // 5: ticket.ViewAllocationsResponse
-	(*Allocation)(nil),                  // 6: ticket.Allocation
-	(*RemoveUserFromTrainRequest)(nil),  // 7: ticket.RemoveUserFromTrainRequest
-	(*RemoveUserFromTrainResponse)(nil), // 8: ticket.RemoveUserFromTrainResponse
-	(*ModifyUserSeatRequest)(nil),       // 9: ticket.ModifyUserSeatRequest
-	(*ModifyUserSeatResponse)(nil),      // 10: ticket.ModifyUserSeatResponse
-	(*Receipt)(nil),                     // 11: ticket.Receipt
-	(*User)(nil),                        // 12: ticket.User
-	(*Seat)(nil),                        // 13: ticket.Seat
+	(ErrorReason)(0),                         // 0: ticket.ErrorReason
+	(*PurchaseTicketRequest)(nil),            // 1: ticket.PurchaseTicketRequest
+	(*PurchaseTicketResponse)(nil),           // 2: ticket.PurchaseTicketResponse
+	(*VerifyPurchaseRequest)(nil),            // 3: ticket.VerifyPurchaseRequest
+	(*VerifyPurchaseResponse)(nil),           // 4: ticket.VerifyPurchaseResponse
+	(*ViewUserReceiptRequest)(nil),           // 5: ticket.ViewUserReceiptRequest
+	(*ViewUserReceiptResponse)(nil),          // 6: ticket.ViewUserReceiptResponse
+	(*ViewAllocationsRequest)(nil),           // 7: ticket.ViewAllocationsRequest
+	(*ViewAllocationsResponse)(nil),          // 8: ticket.ViewAllocationsResponse
+	(*Allocation)(nil),                       // 9: ticket.Allocation
+	(*RemoveUserFromTrainRequest)(nil),       // 10: ticket.RemoveUserFromTrainRequest
+	(*RemoveUserFromTrainResponse)(nil),      // 11: ticket.RemoveUserFromTrainResponse
+	(*ModifyUserSeatRequest)(nil),            // 12: ticket.ModifyUserSeatRequest
+	(*ModifyUserSeatResponse)(nil),           // 13: ticket.ModifyUserSeatResponse
+	(*RequestSeatSwapRequest)(nil),           // 14: ticket.RequestSeatSwapRequest
+	(*RequestSeatSwapResponse)(nil),          // 15: ticket.RequestSeatSwapResponse
+	(*AcceptSeatSwapRequest)(nil),            // 16: ticket.AcceptSeatSwapRequest
+	(*AcceptSeatSwapResponse)(nil),           // 17: ticket.AcceptSeatSwapResponse
+	(*SeatSwap)(nil),                         // 18: ticket.SeatSwap
+	(*BulkApplyRequest)(nil),                 // 19: ticket.BulkApplyRequest
+	(*BulkOperation)(nil),                    // 20: ticket.BulkOperation
+	(*RemoveOperation)(nil),                  // 21: ticket.RemoveOperation
+	(*MoveOperation)(nil),                    // 22: ticket.MoveOperation
+	(*ReassignOperation)(nil),                // 23: ticket.ReassignOperation
+	(*BulkApplyResponse)(nil),                // 24: ticket.BulkApplyResponse
+	(*BulkResult)(nil),                       // 25: ticket.BulkResult
+	(*DecommissionSectionRequest)(nil),       // 26: ticket.DecommissionSectionRequest
+	(*DecommissionSectionResponse)(nil),      // 27: ticket.DecommissionSectionResponse
+	(*ReinstateSectionRequest)(nil),          // 28: ticket.ReinstateSectionRequest
+	(*ReinstateSectionResponse)(nil),         // 29: ticket.ReinstateSectionResponse
+	(*ReaccommodationReport)(nil),            // 30: ticket.ReaccommodationReport
+	(*SeatMove)(nil),                         // 31: ticket.SeatMove
+	(*DisplacedPassenger)(nil),               // 32: ticket.DisplacedPassenger
+	(*AdminPurchaseTicketRequest)(nil),       // 33: ticket.AdminPurchaseTicketRequest
+	(*AdminPurchaseTicketResponse)(nil),      // 34: ticket.AdminPurchaseTicketResponse
+	(*Receipt)(nil),                          // 35: ticket.Receipt
+	(*User)(nil),                             // 36: ticket.User
+	(*Seat)(nil),                             // 37: ticket.Seat
+	(*WatchOccupancyRequest)(nil),            // 38: ticket.WatchOccupancyRequest
+	(*OccupancyUpdate)(nil),                  // 39: ticket.OccupancyUpdate
+	(*SectionOccupancy)(nil),                 // 40: ticket.SectionOccupancy
+	(*RequestPurchaseChallengeRequest)(nil),  // 41: ticket.RequestPurchaseChallengeRequest
+	(*RequestPurchaseChallengeResponse)(nil), // 42: ticket.RequestPurchaseChallengeResponse
+	(*ListFlaggedPurchasesRequest)(nil),      // 43: ticket.ListFlaggedPurchasesRequest
+	(*ListFlaggedPurchasesResponse)(nil),     // 44: ticket.ListFlaggedPurchasesResponse
+	(*FlaggedPurchase)(nil),                  // 45: ticket.FlaggedPurchase
+	(*GetSeatMapRequest)(nil),                // 46: ticket.GetSeatMapRequest
+	(*GetSeatMapResponse)(nil),               // 47: ticket.GetSeatMapResponse
+	(*SeatMapSection)(nil),                   // 48: ticket.SeatMapSection
+	(*SeatMapSeat)(nil),                      // 49: ticket.SeatMapSeat
+	(*ExportManifestRequest)(nil),            // 50: ticket.ExportManifestRequest
+	(*ImportBookingsRequest)(nil),            // 51: ticket.ImportBookingsRequest
+	(*BookingRow)(nil),                       // 52: ticket.BookingRow
+	(*ImportBookingsResponse)(nil),           // 53: ticket.ImportBookingsResponse
+	(*ImportConflict)(nil),                   // 54: ticket.ImportConflict
+	(*DownloadReceiptRequest)(nil),           // 55: ticket.DownloadReceiptRequest
+	(*SyncRevocationsRequest)(nil),           // 56: ticket.SyncRevocationsRequest
+	(*SyncRevocationsResponse)(nil),          // 57: ticket.SyncRevocationsResponse
+	(*TicketKey)(nil),                        // 58: ticket.TicketKey
+	(*Revocation)(nil),                       // 59: ticket.Revocation
+	(*SeatSuggestions)(nil),                  // 60: ticket.SeatSuggestions
+	(*httpbody.HttpBody)(nil),                // 61: google.api.HttpBody
 }
 var file_api_ticket_proto_depIdxs = []int32{
-	11, // 0: ticket.PurchaseTicketResponse.receipt:type_name -> ticket.Receipt
-	11, // 1: ticket.ViewUserReceiptResponse.receipt:type_name -> ticket.Receipt
-	6,  // 2: ticket.ViewAllocationsResponse.allocations:type_name -> ticket.Allocation
-	12, // 3: ticket.Allocation.user:type_name -> ticket.User
-	11, // 4: ticket.ModifyUserSeatResponse.receipt:type_name -> ticket.Receipt
-	12, // 5: ticket.Receipt.user:type_name -> ticket.User
-	13, // 6: ticket.Receipt.seat:type_name -> ticket.Seat
-	0,  // 7: ticket.TicketService.PurchaseTicket:input_type -> ticket.PurchaseTicketRequest
-	2,  // 8: ticket.TicketService.ViewUserReceipt:input_type -> ticket.ViewUserReceiptRequest
-	4,  // 9: ticket.TicketService.ViewAllocations:input_type -> ticket.ViewAllocationsRequest
-	7,  // 10: ticket.TicketService.RemoveUserFromTrain:input_type -> ticket.RemoveUserFromTrainRequest
-	9,  // 11: ticket.TicketService.ModifyUserSeat:input_type -> ticket.ModifyUserSeatRequest
-	1,  // 12: ticket.TicketService.PurchaseTicket:output_type -> ticket.PurchaseTicketResponse
-	3,  // 13: ticket.TicketService.ViewUserReceipt:output_type -> ticket.ViewUserReceiptResponse
-	5,  // 14: ticket.TicketService.ViewAllocations:output_type -> ticket.ViewAllocationsResponse
-	8,  // 15: ticket.TicketService.RemoveUserFromTrain:output_type -> ticket.RemoveUserFromTrainResponse
-	10, // 16: ticket.TicketService.ModifyUserSeat:output_type -> ticket.ModifyUserSeatResponse
-	12, // [12:17] is the sub-list for method output_type
-	7,  // [7:12] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	35, // 0: ticket.PurchaseTicketResponse.receipt:type_name -> ticket.Receipt
+	35, // 1: ticket.VerifyPurchaseResponse.receipt:type_name -> ticket.Receipt
+	35, // 2: ticket.ViewUserReceiptResponse.receipt:type_name -> ticket.Receipt
+	9,  // 3: ticket.ViewAllocationsResponse.allocations:type_name -> ticket.Allocation
+	36, // 4: ticket.Allocation.user:type_name -> ticket.User
+	35, // 5: ticket.ModifyUserSeatResponse.receipt:type_name -> ticket.Receipt
+	18, // 6: ticket.RequestSeatSwapResponse.swap:type_name -> ticket.SeatSwap
+	18, // 7: ticket.AcceptSeatSwapResponse.swap:type_name -> ticket.SeatSwap
+	37, // 8: ticket.SeatSwap.requester_seat:type_name -> ticket.Seat
+	37, // 9: ticket.SeatSwap.counterparty_seat:type_name -> ticket.Seat
+	20, // 10: ticket.BulkApplyRequest.operations:type_name -> ticket.BulkOperation
+	21, // 11: ticket.BulkOperation.remove:type_name -> ticket.RemoveOperation
+	22, // 12: ticket.BulkOperation.move:type_name -> ticket.MoveOperation
+	23, // 13: ticket.BulkOperation.reassign:type_name -> ticket.ReassignOperation
+	25, // 14: ticket.BulkApplyResponse.results:type_name -> ticket.BulkResult
+	37, // 15: ticket.BulkResult.seat:type_name -> ticket.Seat
+	30, // 16: ticket.DecommissionSectionResponse.report:type_name -> ticket.ReaccommodationReport
+	31, // 17: ticket.ReinstateSectionResponse.placed:type_name -> ticket.SeatMove
+	31, // 18: ticket.ReaccommodationReport.moved:type_name -> ticket.SeatMove
+	32, // 19: ticket.ReaccommodationReport.waitlisted:type_name -> ticket.DisplacedPassenger
+	32, // 20: ticket.ReaccommodationReport.refunded:type_name -> ticket.DisplacedPassenger
+	36, // 21: ticket.SeatMove.user:type_name -> ticket.User
+	37, // 22: ticket.SeatMove.from:type_name -> ticket.Seat
+	37, // 23: ticket.SeatMove.to:type_name -> ticket.Seat
+	36, // 24: ticket.DisplacedPassenger.user:type_name -> ticket.User
+	37, // 25: ticket.DisplacedPassenger.from:type_name -> ticket.Seat
+	36, // 26: ticket.AdminPurchaseTicketRequest.passenger:type_name -> ticket.User
+	35, // 27: ticket.AdminPurchaseTicketResponse.receipt:type_name -> ticket.Receipt
+	36, // 28: ticket.Receipt.user:type_name -> ticket.User
+	37, // 29: ticket.Receipt.seat:type_name -> ticket.Seat
+	40, // 30: ticket.OccupancyUpdate.sections:type_name -> ticket.SectionOccupancy
+	45, // 31: ticket.ListFlaggedPurchasesResponse.purchases:type_name -> ticket.FlaggedPurchase
+	48, // 32: ticket.GetSeatMapResponse.sections:type_name -> ticket.SeatMapSection
+	49, // 33: ticket.SeatMapSection.seats:type_name -> ticket.SeatMapSeat
+	52, // 34: ticket.ImportBookingsRequest.rows:type_name -> ticket.BookingRow
+	54, // 35: ticket.ImportBookingsResponse.conflicts:type_name -> ticket.ImportConflict
+	58, // 36: ticket.SyncRevocationsResponse.keys:type_name -> ticket.TicketKey
+	59, // 37: ticket.SyncRevocationsResponse.revocations:type_name -> ticket.Revocation
+	37, // 38: ticket.SeatSuggestions.seats:type_name -> ticket.Seat
+	1,  // 39: ticket.TicketService.PurchaseTicket:input_type -> ticket.PurchaseTicketRequest
+	3,  // 40: ticket.TicketService.VerifyPurchase:input_type -> ticket.VerifyPurchaseRequest
+	5,  // 41: ticket.TicketService.ViewUserReceipt:input_type -> ticket.ViewUserReceiptRequest
+	7,  // 42: ticket.TicketService.ViewAllocations:input_type -> ticket.ViewAllocationsRequest
+	10, // 43: ticket.TicketService.RemoveUserFromTrain:input_type -> ticket.RemoveUserFromTrainRequest
+	12, // 44: ticket.TicketService.ModifyUserSeat:input_type -> ticket.ModifyUserSeatRequest
+	14, // 45: ticket.TicketService.RequestSeatSwap:input_type -> ticket.RequestSeatSwapRequest
+	16, // 46: ticket.TicketService.AcceptSeatSwap:input_type -> ticket.AcceptSeatSwapRequest
+	19, // 47: ticket.TicketService.BulkApply:input_type -> ticket.BulkApplyRequest
+	26, // 48: ticket.TicketService.DecommissionSection:input_type -> ticket.DecommissionSectionRequest
+	28, // 49: ticket.TicketService.ReinstateSection:input_type -> ticket.ReinstateSectionRequest
+	33, // 50: ticket.TicketService.AdminPurchaseTicket:input_type -> ticket.AdminPurchaseTicketRequest
+	38, // 51: ticket.TicketService.WatchOccupancy:input_type -> ticket.WatchOccupancyRequest
+	41, // 52: ticket.TicketService.RequestPurchaseChallenge:input_type -> ticket.RequestPurchaseChallengeRequest
+	43, // 53: ticket.TicketService.ListFlaggedPurchases:input_type -> ticket.ListFlaggedPurchasesRequest
+	46, // 54: ticket.TicketService.GetSeatMap:input_type -> ticket.GetSeatMapRequest
+	50, // 55: ticket.TicketService.ExportManifest:input_type -> ticket.ExportManifestRequest
+	51, // 56: ticket.TicketService.ImportBookings:input_type -> ticket.ImportBookingsRequest
+	55, // 57: ticket.TicketService.DownloadReceipt:input_type -> ticket.DownloadReceiptRequest
+	56, // 58: ticket.TicketService.SyncRevocations:input_type -> ticket.SyncRevocationsRequest
+	2,  // 59: ticket.TicketService.PurchaseTicket:output_type -> ticket.PurchaseTicketResponse
+	4,  // 60: ticket.TicketService.VerifyPurchase:output_type -> ticket.VerifyPurchaseResponse
+	6,  // 61: ticket.TicketService.ViewUserReceipt:output_type -> ticket.ViewUserReceiptResponse
+	8,  // 62: ticket.TicketService.ViewAllocations:output_type -> ticket.ViewAllocationsResponse
+	11, // 63: ticket.TicketService.RemoveUserFromTrain:output_type -> ticket.RemoveUserFromTrainResponse
+	13, // 64: ticket.TicketService.ModifyUserSeat:output_type -> ticket.ModifyUserSeatResponse
+	15, // 65: ticket.TicketService.RequestSeatSwap:output_type -> ticket.RequestSeatSwapResponse
+	17, // 66: ticket.TicketService.AcceptSeatSwap:output_type -> ticket.AcceptSeatSwapResponse
+	24, // 67: ticket.TicketService.BulkApply:output_type -> ticket.BulkApplyResponse
+	27, // 68: ticket.TicketService.DecommissionSection:output_type -> ticket.DecommissionSectionResponse
+	29, // 69: ticket.TicketService.ReinstateSection:output_type -> ticket.ReinstateSectionResponse
+	34, // 70: ticket.TicketService.AdminPurchaseTicket:output_type -> ticket.AdminPurchaseTicketResponse
+	39, // 71: ticket.TicketService.WatchOccupancy:output_type -> ticket.OccupancyUpdate
+	42, // 72: ticket.TicketService.RequestPurchaseChallenge:output_type -> ticket.RequestPurchaseChallengeResponse
+	44, // 73: ticket.TicketService.ListFlaggedPurchases:output_type -> ticket.ListFlaggedPurchasesResponse
+	47, // 74: ticket.TicketService.GetSeatMap:output_type -> ticket.GetSeatMapResponse
+	61, // 75: ticket.TicketService.ExportManifest:output_type -> google.api.HttpBody
+	53, // 76: ticket.TicketService.ImportBookings:output_type -> ticket.ImportBookingsResponse
+	61, // 77: ticket.TicketService.DownloadReceipt:output_type -> google.api.HttpBody
+	57, // 78: ticket.TicketService.SyncRevocations:output_type -> ticket.SyncRevocationsResponse
+	59, // [59:79] is the sub-list for method output_type
+	39, // [39:59] is the sub-list for method input_type
+	39, // [39:39] is the sub-list for extension type_name
+	39, // [39:39] is the sub-list for extension extendee
+	0,  // [0:39] is the sub-list for field type_name
 }
 
 func init() { file_api_ticket_proto_init() }
@@ -877,18 +4165,24 @@ func file_api_ticket_proto_init() {
 	if File_api_ticket_proto != nil {
 		return
 	}
+	file_api_ticket_proto_msgTypes[19].OneofWrappers = []any{
+		(*BulkOperation_Remove)(nil),
+		(*BulkOperation_Move)(nil),
+		(*BulkOperation_Reassign)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_ticket_proto_rawDesc), len(file_api_ticket_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   14,
+			NumEnums:      1,
+			NumMessages:   60,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_api_ticket_proto_goTypes,
 		DependencyIndexes: file_api_ticket_proto_depIdxs,
+		EnumInfos:         file_api_ticket_proto_enumTypes,
 		MessageInfos:      file_api_ticket_proto_msgTypes,
 	}.Build()
 	File_api_ticket_proto = out.File
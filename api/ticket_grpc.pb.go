@@ -8,6 +8,7 @@ package ticket
 
 import (
 	context "context"
+	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -19,11 +20,26 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TicketService_PurchaseTicket_FullMethodName      = "/ticket.TicketService/PurchaseTicket"
-	TicketService_ViewUserReceipt_FullMethodName     = "/ticket.TicketService/ViewUserReceipt"
-	TicketService_ViewAllocations_FullMethodName     = "/ticket.TicketService/ViewAllocations"
-	TicketService_RemoveUserFromTrain_FullMethodName = "/ticket.TicketService/RemoveUserFromTrain"
-	TicketService_ModifyUserSeat_FullMethodName      = "/ticket.TicketService/ModifyUserSeat"
+	TicketService_PurchaseTicket_FullMethodName           = "/ticket.TicketService/PurchaseTicket"
+	TicketService_VerifyPurchase_FullMethodName           = "/ticket.TicketService/VerifyPurchase"
+	TicketService_ViewUserReceipt_FullMethodName          = "/ticket.TicketService/ViewUserReceipt"
+	TicketService_ViewAllocations_FullMethodName          = "/ticket.TicketService/ViewAllocations"
+	TicketService_RemoveUserFromTrain_FullMethodName      = "/ticket.TicketService/RemoveUserFromTrain"
+	TicketService_ModifyUserSeat_FullMethodName           = "/ticket.TicketService/ModifyUserSeat"
+	TicketService_RequestSeatSwap_FullMethodName          = "/ticket.TicketService/RequestSeatSwap"
+	TicketService_AcceptSeatSwap_FullMethodName           = "/ticket.TicketService/AcceptSeatSwap"
+	TicketService_BulkApply_FullMethodName                = "/ticket.TicketService/BulkApply"
+	TicketService_DecommissionSection_FullMethodName      = "/ticket.TicketService/DecommissionSection"
+	TicketService_ReinstateSection_FullMethodName         = "/ticket.TicketService/ReinstateSection"
+	TicketService_AdminPurchaseTicket_FullMethodName      = "/ticket.TicketService/AdminPurchaseTicket"
+	TicketService_WatchOccupancy_FullMethodName           = "/ticket.TicketService/WatchOccupancy"
+	TicketService_RequestPurchaseChallenge_FullMethodName = "/ticket.TicketService/RequestPurchaseChallenge"
+	TicketService_ListFlaggedPurchases_FullMethodName     = "/ticket.TicketService/ListFlaggedPurchases"
+	TicketService_GetSeatMap_FullMethodName               = "/ticket.TicketService/GetSeatMap"
+	TicketService_ExportManifest_FullMethodName           = "/ticket.TicketService/ExportManifest"
+	TicketService_ImportBookings_FullMethodName           = "/ticket.TicketService/ImportBookings"
+	TicketService_DownloadReceipt_FullMethodName          = "/ticket.TicketService/DownloadReceipt"
+	TicketService_SyncRevocations_FullMethodName          = "/ticket.TicketService/SyncRevocations"
 )
 
 // TicketServiceClient is the client API for TicketService service.
@@ -34,7 +50,11 @@ const (
 type TicketServiceClient interface {
 	// PurchaseTicket - Public API to purchase a ticket
 	// Automatically assigns a seat and returns a receipt
+	// With a JWT the email must match the token; anonymous purchases are held until verified
 	PurchaseTicket(ctx context.Context, in *PurchaseTicketRequest, opts ...grpc.CallOption) (*PurchaseTicketResponse, error)
+	// VerifyPurchase - Public API to confirm an anonymous purchase
+	// Confirms the held ticket when the emailed verification code matches
+	VerifyPurchase(ctx context.Context, in *VerifyPurchaseRequest, opts ...grpc.CallOption) (*VerifyPurchaseResponse, error)
 	// ViewUserReceipt - Authenticated API to view user's own receipt
 	// Reads user info from JWT in metadata
 	ViewUserReceipt(ctx context.Context, in *ViewUserReceiptRequest, opts ...grpc.CallOption) (*ViewUserReceiptResponse, error)
@@ -47,6 +67,48 @@ type TicketServiceClient interface {
 	// ModifyUserSeat - Authenticated API to modify a user's seat assignment
 	// User can modify their own seat, admin can modify any user's seat
 	ModifyUserSeat(ctx context.Context, in *ModifyUserSeatRequest, opts ...grpc.CallOption) (*ModifyUserSeatResponse, error)
+	// RequestSeatSwap - Authenticated API to propose exchanging seats with another passenger
+	// The counterparty must accept; admin can force the swap immediately
+	RequestSeatSwap(ctx context.Context, in *RequestSeatSwapRequest, opts ...grpc.CallOption) (*RequestSeatSwapResponse, error)
+	// AcceptSeatSwap - Authenticated API for the counterparty to accept a pending swap
+	// Both seats are exchanged atomically
+	AcceptSeatSwap(ctx context.Context, in *AcceptSeatSwapRequest, opts ...grpc.CallOption) (*AcceptSeatSwapResponse, error)
+	// BulkApply - Admin API to apply many remove, move and reassign operations in one call
+	// Runs all-or-nothing (default) or best-effort and reports a result per operation
+	BulkApply(ctx context.Context, in *BulkApplyRequest, opts ...grpc.CallOption) (*BulkApplyResponse, error)
+	// DecommissionSection - Admin API to take a section out of service
+	// Passengers are reseated in free seats elsewhere; those who cannot be placed are waitlisted or refunded
+	DecommissionSection(ctx context.Context, in *DecommissionSectionRequest, opts ...grpc.CallOption) (*DecommissionSectionResponse, error)
+	// ReinstateSection - Admin API to return a section to service
+	// Waitlisted passengers are seated in the order they were displaced
+	ReinstateSection(ctx context.Context, in *ReinstateSectionRequest, opts ...grpc.CallOption) (*ReinstateSectionResponse, error)
+	// AdminPurchaseTicket - Admin API to purchase a ticket on behalf of a passenger
+	// Records both the acting admin and the passenger on the ticket
+	AdminPurchaseTicket(ctx context.Context, in *AdminPurchaseTicketRequest, opts ...grpc.CallOption) (*AdminPurchaseTicketResponse, error)
+	// WatchOccupancy - Public API streaming seat availability per section
+	// Sends the current occupancy, then an update whenever it changes; carries no passenger details
+	WatchOccupancy(ctx context.Context, in *WatchOccupancyRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OccupancyUpdate], error)
+	// RequestPurchaseChallenge - Public API returning a proof-of-work puzzle
+	// Needed by PurchaseTicket when many emails are bought for from one address
+	RequestPurchaseChallenge(ctx context.Context, in *RequestPurchaseChallengeRequest, opts ...grpc.CallOption) (*RequestPurchaseChallengeResponse, error)
+	// ListFlaggedPurchases - Admin API to review purchases that tripped abuse controls
+	// Lists the most recent first
+	ListFlaggedPurchases(ctx context.Context, in *ListFlaggedPurchasesRequest, opts ...grpc.CallOption) (*ListFlaggedPurchasesResponse, error)
+	// GetSeatMap - Public API listing every seat with its status and attributes
+	// Never names other passengers; with a JWT the caller's own seat is marked
+	GetSeatMap(ctx context.Context, in *GetSeatMapRequest, opts ...grpc.CallOption) (*GetSeatMapResponse, error)
+	// ExportManifest - Admin API streaming the passenger manifest as a CSV, JSON Lines or PDF file
+	// Confirmed passengers sorted by section and seat; the first chunk carries the content type
+	ExportManifest(ctx context.Context, in *ExportManifestRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[httpbody.HttpBody], error)
+	// ImportBookings - Admin API loading existing bookings, streamed in batches of rows
+	// A dry run reports per-row conflicts; a commit books every row or none
+	ImportBookings(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportBookingsRequest, ImportBookingsResponse], error)
+	// DownloadReceipt - Authenticated API returning the caller's e-ticket as an HTML page or PDF
+	// The e-ticket carries a QR code of the ticket's signed token
+	DownloadReceipt(ctx context.Context, in *DownloadReceiptRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+	// SyncRevocations - Public API for conductors to check ticket tokens offline
+	// Returns the token verification keys and the revocations after a cursor
+	SyncRevocations(ctx context.Context, in *SyncRevocationsRequest, opts ...grpc.CallOption) (*SyncRevocationsResponse, error)
 }
 
 type ticketServiceClient struct {
@@ -67,6 +129,16 @@ func (c *ticketServiceClient) PurchaseTicket(ctx context.Context, in *PurchaseTi
 	return out, nil
 }
 
+func (c *ticketServiceClient) VerifyPurchase(ctx context.Context, in *VerifyPurchaseRequest, opts ...grpc.CallOption) (*VerifyPurchaseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyPurchaseResponse)
+	err := c.cc.Invoke(ctx, TicketService_VerifyPurchase_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *ticketServiceClient) ViewUserReceipt(ctx context.Context, in *ViewUserReceiptRequest, opts ...grpc.CallOption) (*ViewUserReceiptResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ViewUserReceiptResponse)
@@ -107,6 +179,167 @@ func (c *ticketServiceClient) ModifyUserSeat(ctx context.Context, in *ModifyUser
 	return out, nil
 }
 
+func (c *ticketServiceClient) RequestSeatSwap(ctx context.Context, in *RequestSeatSwapRequest, opts ...grpc.CallOption) (*RequestSeatSwapResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestSeatSwapResponse)
+	err := c.cc.Invoke(ctx, TicketService_RequestSeatSwap_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketServiceClient) AcceptSeatSwap(ctx context.Context, in *AcceptSeatSwapRequest, opts ...grpc.CallOption) (*AcceptSeatSwapResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AcceptSeatSwapResponse)
+	err := c.cc.Invoke(ctx, TicketService_AcceptSeatSwap_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketServiceClient) BulkApply(ctx context.Context, in *BulkApplyRequest, opts ...grpc.CallOption) (*BulkApplyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BulkApplyResponse)
+	err := c.cc.Invoke(ctx, TicketService_BulkApply_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketServiceClient) DecommissionSection(ctx context.Context, in *DecommissionSectionRequest, opts ...grpc.CallOption) (*DecommissionSectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecommissionSectionResponse)
+	err := c.cc.Invoke(ctx, TicketService_DecommissionSection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketServiceClient) ReinstateSection(ctx context.Context, in *ReinstateSectionRequest, opts ...grpc.CallOption) (*ReinstateSectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReinstateSectionResponse)
+	err := c.cc.Invoke(ctx, TicketService_ReinstateSection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketServiceClient) AdminPurchaseTicket(ctx context.Context, in *AdminPurchaseTicketRequest, opts ...grpc.CallOption) (*AdminPurchaseTicketResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminPurchaseTicketResponse)
+	err := c.cc.Invoke(ctx, TicketService_AdminPurchaseTicket_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketServiceClient) WatchOccupancy(ctx context.Context, in *WatchOccupancyRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OccupancyUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TicketService_ServiceDesc.Streams[0], TicketService_WatchOccupancy_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchOccupancyRequest, OccupancyUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TicketService_WatchOccupancyClient = grpc.ServerStreamingClient[OccupancyUpdate]
+
+func (c *ticketServiceClient) RequestPurchaseChallenge(ctx context.Context, in *RequestPurchaseChallengeRequest, opts ...grpc.CallOption) (*RequestPurchaseChallengeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestPurchaseChallengeResponse)
+	err := c.cc.Invoke(ctx, TicketService_RequestPurchaseChallenge_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketServiceClient) ListFlaggedPurchases(ctx context.Context, in *ListFlaggedPurchasesRequest, opts ...grpc.CallOption) (*ListFlaggedPurchasesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFlaggedPurchasesResponse)
+	err := c.cc.Invoke(ctx, TicketService_ListFlaggedPurchases_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketServiceClient) GetSeatMap(ctx context.Context, in *GetSeatMapRequest, opts ...grpc.CallOption) (*GetSeatMapResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSeatMapResponse)
+	err := c.cc.Invoke(ctx, TicketService_GetSeatMap_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketServiceClient) ExportManifest(ctx context.Context, in *ExportManifestRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[httpbody.HttpBody], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TicketService_ServiceDesc.Streams[1], TicketService_ExportManifest_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportManifestRequest, httpbody.HttpBody]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TicketService_ExportManifestClient = grpc.ServerStreamingClient[httpbody.HttpBody]
+
+func (c *ticketServiceClient) ImportBookings(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportBookingsRequest, ImportBookingsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TicketService_ServiceDesc.Streams[2], TicketService_ImportBookings_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportBookingsRequest, ImportBookingsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TicketService_ImportBookingsClient = grpc.ClientStreamingClient[ImportBookingsRequest, ImportBookingsResponse]
+
+func (c *ticketServiceClient) DownloadReceipt(ctx context.Context, in *DownloadReceiptRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(httpbody.HttpBody)
+	err := c.cc.Invoke(ctx, TicketService_DownloadReceipt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ticketServiceClient) SyncRevocations(ctx context.Context, in *SyncRevocationsRequest, opts ...grpc.CallOption) (*SyncRevocationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyncRevocationsResponse)
+	err := c.cc.Invoke(ctx, TicketService_SyncRevocations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TicketServiceServer is the server API for TicketService service.
 // All implementations must embed UnimplementedTicketServiceServer
 // for forward compatibility.
@@ -115,7 +348,11 @@ func (c *ticketServiceClient) ModifyUserSeat(ctx context.Context, in *ModifyUser
 type TicketServiceServer interface {
 	// PurchaseTicket - Public API to purchase a ticket
 	// Automatically assigns a seat and returns a receipt
+	// With a JWT the email must match the token; anonymous purchases are held until verified
 	PurchaseTicket(context.Context, *PurchaseTicketRequest) (*PurchaseTicketResponse, error)
+	// VerifyPurchase - Public API to confirm an anonymous purchase
+	// Confirms the held ticket when the emailed verification code matches
+	VerifyPurchase(context.Context, *VerifyPurchaseRequest) (*VerifyPurchaseResponse, error)
 	// ViewUserReceipt - Authenticated API to view user's own receipt
 	// Reads user info from JWT in metadata
 	ViewUserReceipt(context.Context, *ViewUserReceiptRequest) (*ViewUserReceiptResponse, error)
@@ -128,6 +365,48 @@ type TicketServiceServer interface {
 	// ModifyUserSeat - Authenticated API to modify a user's seat assignment
 	// User can modify their own seat, admin can modify any user's seat
 	ModifyUserSeat(context.Context, *ModifyUserSeatRequest) (*ModifyUserSeatResponse, error)
+	// RequestSeatSwap - Authenticated API to propose exchanging seats with another passenger
+	// The counterparty must accept; admin can force the swap immediately
+	RequestSeatSwap(context.Context, *RequestSeatSwapRequest) (*RequestSeatSwapResponse, error)
+	// AcceptSeatSwap - Authenticated API for the counterparty to accept a pending swap
+	// Both seats are exchanged atomically
+	AcceptSeatSwap(context.Context, *AcceptSeatSwapRequest) (*AcceptSeatSwapResponse, error)
+	// BulkApply - Admin API to apply many remove, move and reassign operations in one call
+	// Runs all-or-nothing (default) or best-effort and reports a result per operation
+	BulkApply(context.Context, *BulkApplyRequest) (*BulkApplyResponse, error)
+	// DecommissionSection - Admin API to take a section out of service
+	// Passengers are reseated in free seats elsewhere; those who cannot be placed are waitlisted or refunded
+	DecommissionSection(context.Context, *DecommissionSectionRequest) (*DecommissionSectionResponse, error)
+	// ReinstateSection - Admin API to return a section to service
+	// Waitlisted passengers are seated in the order they were displaced
+	ReinstateSection(context.Context, *ReinstateSectionRequest) (*ReinstateSectionResponse, error)
+	// AdminPurchaseTicket - Admin API to purchase a ticket on behalf of a passenger
+	// Records both the acting admin and the passenger on the ticket
+	AdminPurchaseTicket(context.Context, *AdminPurchaseTicketRequest) (*AdminPurchaseTicketResponse, error)
+	// WatchOccupancy - Public API streaming seat availability per section
+	// Sends the current occupancy, then an update whenever it changes; carries no passenger details
+	WatchOccupancy(*WatchOccupancyRequest, grpc.ServerStreamingServer[OccupancyUpdate]) error
+	// RequestPurchaseChallenge - Public API returning a proof-of-work puzzle
+	// Needed by PurchaseTicket when many emails are bought for from one address
+	RequestPurchaseChallenge(context.Context, *RequestPurchaseChallengeRequest) (*RequestPurchaseChallengeResponse, error)
+	// ListFlaggedPurchases - Admin API to review purchases that tripped abuse controls
+	// Lists the most recent first
+	ListFlaggedPurchases(context.Context, *ListFlaggedPurchasesRequest) (*ListFlaggedPurchasesResponse, error)
+	// GetSeatMap - Public API listing every seat with its status and attributes
+	// Never names other passengers; with a JWT the caller's own seat is marked
+	GetSeatMap(context.Context, *GetSeatMapRequest) (*GetSeatMapResponse, error)
+	// ExportManifest - Admin API streaming the passenger manifest as a CSV, JSON Lines or PDF file
+	// Confirmed passengers sorted by section and seat; the first chunk carries the content type
+	ExportManifest(*ExportManifestRequest, grpc.ServerStreamingServer[httpbody.HttpBody]) error
+	// ImportBookings - Admin API loading existing bookings, streamed in batches of rows
+	// A dry run reports per-row conflicts; a commit books every row or none
+	ImportBookings(grpc.ClientStreamingServer[ImportBookingsRequest, ImportBookingsResponse]) error
+	// DownloadReceipt - Authenticated API returning the caller's e-ticket as an HTML page or PDF
+	// The e-ticket carries a QR code of the ticket's signed token
+	DownloadReceipt(context.Context, *DownloadReceiptRequest) (*httpbody.HttpBody, error)
+	// SyncRevocations - Public API for conductors to check ticket tokens offline
+	// Returns the token verification keys and the revocations after a cursor
+	SyncRevocations(context.Context, *SyncRevocationsRequest) (*SyncRevocationsResponse, error)
 	mustEmbedUnimplementedTicketServiceServer()
 }
 
@@ -141,6 +420,9 @@ type UnimplementedTicketServiceServer struct{}
 func (UnimplementedTicketServiceServer) PurchaseTicket(context.Context, *PurchaseTicketRequest) (*PurchaseTicketResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PurchaseTicket not implemented")
 }
+func (UnimplementedTicketServiceServer) VerifyPurchase(context.Context, *VerifyPurchaseRequest) (*VerifyPurchaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyPurchase not implemented")
+}
 func (UnimplementedTicketServiceServer) ViewUserReceipt(context.Context, *ViewUserReceiptRequest) (*ViewUserReceiptResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ViewUserReceipt not implemented")
 }
@@ -153,6 +435,48 @@ func (UnimplementedTicketServiceServer) RemoveUserFromTrain(context.Context, *Re
 func (UnimplementedTicketServiceServer) ModifyUserSeat(context.Context, *ModifyUserSeatRequest) (*ModifyUserSeatResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ModifyUserSeat not implemented")
 }
+func (UnimplementedTicketServiceServer) RequestSeatSwap(context.Context, *RequestSeatSwapRequest) (*RequestSeatSwapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestSeatSwap not implemented")
+}
+func (UnimplementedTicketServiceServer) AcceptSeatSwap(context.Context, *AcceptSeatSwapRequest) (*AcceptSeatSwapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptSeatSwap not implemented")
+}
+func (UnimplementedTicketServiceServer) BulkApply(context.Context, *BulkApplyRequest) (*BulkApplyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BulkApply not implemented")
+}
+func (UnimplementedTicketServiceServer) DecommissionSection(context.Context, *DecommissionSectionRequest) (*DecommissionSectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecommissionSection not implemented")
+}
+func (UnimplementedTicketServiceServer) ReinstateSection(context.Context, *ReinstateSectionRequest) (*ReinstateSectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReinstateSection not implemented")
+}
+func (UnimplementedTicketServiceServer) AdminPurchaseTicket(context.Context, *AdminPurchaseTicketRequest) (*AdminPurchaseTicketResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminPurchaseTicket not implemented")
+}
+func (UnimplementedTicketServiceServer) WatchOccupancy(*WatchOccupancyRequest, grpc.ServerStreamingServer[OccupancyUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchOccupancy not implemented")
+}
+func (UnimplementedTicketServiceServer) RequestPurchaseChallenge(context.Context, *RequestPurchaseChallengeRequest) (*RequestPurchaseChallengeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestPurchaseChallenge not implemented")
+}
+func (UnimplementedTicketServiceServer) ListFlaggedPurchases(context.Context, *ListFlaggedPurchasesRequest) (*ListFlaggedPurchasesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFlaggedPurchases not implemented")
+}
+func (UnimplementedTicketServiceServer) GetSeatMap(context.Context, *GetSeatMapRequest) (*GetSeatMapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSeatMap not implemented")
+}
+func (UnimplementedTicketServiceServer) ExportManifest(*ExportManifestRequest, grpc.ServerStreamingServer[httpbody.HttpBody]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportManifest not implemented")
+}
+func (UnimplementedTicketServiceServer) ImportBookings(grpc.ClientStreamingServer[ImportBookingsRequest, ImportBookingsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ImportBookings not implemented")
+}
+func (UnimplementedTicketServiceServer) DownloadReceipt(context.Context, *DownloadReceiptRequest) (*httpbody.HttpBody, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DownloadReceipt not implemented")
+}
+func (UnimplementedTicketServiceServer) SyncRevocations(context.Context, *SyncRevocationsRequest) (*SyncRevocationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncRevocations not implemented")
+}
 func (UnimplementedTicketServiceServer) mustEmbedUnimplementedTicketServiceServer() {}
 func (UnimplementedTicketServiceServer) testEmbeddedByValue()                       {}
 
@@ -192,6 +516,24 @@ func _TicketService_PurchaseTicket_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TicketService_VerifyPurchase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyPurchaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketServiceServer).VerifyPurchase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketService_VerifyPurchase_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketServiceServer).VerifyPurchase(ctx, req.(*VerifyPurchaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TicketService_ViewUserReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ViewUserReceiptRequest)
 	if err := dec(in); err != nil {
@@ -264,6 +606,233 @@ func _TicketService_ModifyUserSeat_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TicketService_RequestSeatSwap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestSeatSwapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketServiceServer).RequestSeatSwap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketService_RequestSeatSwap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketServiceServer).RequestSeatSwap(ctx, req.(*RequestSeatSwapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketService_AcceptSeatSwap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptSeatSwapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketServiceServer).AcceptSeatSwap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketService_AcceptSeatSwap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketServiceServer).AcceptSeatSwap(ctx, req.(*AcceptSeatSwapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketService_BulkApply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkApplyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketServiceServer).BulkApply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketService_BulkApply_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketServiceServer).BulkApply(ctx, req.(*BulkApplyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketService_DecommissionSection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecommissionSectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketServiceServer).DecommissionSection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketService_DecommissionSection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketServiceServer).DecommissionSection(ctx, req.(*DecommissionSectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketService_ReinstateSection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReinstateSectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketServiceServer).ReinstateSection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketService_ReinstateSection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketServiceServer).ReinstateSection(ctx, req.(*ReinstateSectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketService_AdminPurchaseTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminPurchaseTicketRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketServiceServer).AdminPurchaseTicket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketService_AdminPurchaseTicket_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketServiceServer).AdminPurchaseTicket(ctx, req.(*AdminPurchaseTicketRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketService_WatchOccupancy_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchOccupancyRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TicketServiceServer).WatchOccupancy(m, &grpc.GenericServerStream[WatchOccupancyRequest, OccupancyUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TicketService_WatchOccupancyServer = grpc.ServerStreamingServer[OccupancyUpdate]
+
+func _TicketService_RequestPurchaseChallenge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestPurchaseChallengeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketServiceServer).RequestPurchaseChallenge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketService_RequestPurchaseChallenge_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketServiceServer).RequestPurchaseChallenge(ctx, req.(*RequestPurchaseChallengeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketService_ListFlaggedPurchases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFlaggedPurchasesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketServiceServer).ListFlaggedPurchases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketService_ListFlaggedPurchases_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketServiceServer).ListFlaggedPurchases(ctx, req.(*ListFlaggedPurchasesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketService_GetSeatMap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSeatMapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketServiceServer).GetSeatMap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketService_GetSeatMap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketServiceServer).GetSeatMap(ctx, req.(*GetSeatMapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketService_ExportManifest_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportManifestRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TicketServiceServer).ExportManifest(m, &grpc.GenericServerStream[ExportManifestRequest, httpbody.HttpBody]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TicketService_ExportManifestServer = grpc.ServerStreamingServer[httpbody.HttpBody]
+
+func _TicketService_ImportBookings_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TicketServiceServer).ImportBookings(&grpc.GenericServerStream[ImportBookingsRequest, ImportBookingsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TicketService_ImportBookingsServer = grpc.ClientStreamingServer[ImportBookingsRequest, ImportBookingsResponse]
+
+func _TicketService_DownloadReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DownloadReceiptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketServiceServer).DownloadReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketService_DownloadReceipt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketServiceServer).DownloadReceipt(ctx, req.(*DownloadReceiptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TicketService_SyncRevocations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncRevocationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TicketServiceServer).SyncRevocations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TicketService_SyncRevocations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TicketServiceServer).SyncRevocations(ctx, req.(*SyncRevocationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TicketService_ServiceDesc is the grpc.ServiceDesc for TicketService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -275,6 +844,10 @@ var TicketService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PurchaseTicket",
 			Handler:    _TicketService_PurchaseTicket_Handler,
 		},
+		{
+			MethodName: "VerifyPurchase",
+			Handler:    _TicketService_VerifyPurchase_Handler,
+		},
 		{
 			MethodName: "ViewUserReceipt",
 			Handler:    _TicketService_ViewUserReceipt_Handler,
@@ -291,7 +864,67 @@ var TicketService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ModifyUserSeat",
 			Handler:    _TicketService_ModifyUserSeat_Handler,
 		},
+		{
+			MethodName: "RequestSeatSwap",
+			Handler:    _TicketService_RequestSeatSwap_Handler,
+		},
+		{
+			MethodName: "AcceptSeatSwap",
+			Handler:    _TicketService_AcceptSeatSwap_Handler,
+		},
+		{
+			MethodName: "BulkApply",
+			Handler:    _TicketService_BulkApply_Handler,
+		},
+		{
+			MethodName: "DecommissionSection",
+			Handler:    _TicketService_DecommissionSection_Handler,
+		},
+		{
+			MethodName: "ReinstateSection",
+			Handler:    _TicketService_ReinstateSection_Handler,
+		},
+		{
+			MethodName: "AdminPurchaseTicket",
+			Handler:    _TicketService_AdminPurchaseTicket_Handler,
+		},
+		{
+			MethodName: "RequestPurchaseChallenge",
+			Handler:    _TicketService_RequestPurchaseChallenge_Handler,
+		},
+		{
+			MethodName: "ListFlaggedPurchases",
+			Handler:    _TicketService_ListFlaggedPurchases_Handler,
+		},
+		{
+			MethodName: "GetSeatMap",
+			Handler:    _TicketService_GetSeatMap_Handler,
+		},
+		{
+			MethodName: "DownloadReceipt",
+			Handler:    _TicketService_DownloadReceipt_Handler,
+		},
+		{
+			MethodName: "SyncRevocations",
+			Handler:    _TicketService_SyncRevocations_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchOccupancy",
+			Handler:       _TicketService_WatchOccupancy_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportManifest",
+			Handler:       _TicketService_ExportManifest_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportBookings",
+			Handler:       _TicketService_ImportBookings_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/ticket.proto",
 }
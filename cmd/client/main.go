@@ -1,24 +1,66 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/abuse"
+	"github.com/cloudbees/train-ticket-service/internal/auth"
+	"github.com/cloudbees/train-ticket-service/internal/tickettoken"
+	"github.com/cloudbees/train-ticket-service/internal/tlsutil"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func main() {
-	if len(os.Args) < 2 {
+	addr := flag.String("addr", envOr("TICKET_SERVER_ADDR", "localhost:50051"), "server address (env TICKET_SERVER_ADDR)")
+	useTLS := flag.Bool("tls", false, "connect over TLS using the system roots unless -ca is set")
+	caFile := flag.String("ca", os.Getenv("TICKET_CA_FILE"), "PEM CA bundle to verify the server; implies -tls (env TICKET_CA_FILE)")
+	certFile := flag.String("cert", os.Getenv("TICKET_CLIENT_CERT_FILE"), "PEM client certificate for mTLS; implies -tls (env TICKET_CLIENT_CERT_FILE)")
+	keyFile := flag.String("key", os.Getenv("TICKET_CLIENT_KEY_FILE"), "PEM client key for mTLS (env TICKET_CLIENT_KEY_FILE)")
+	serverName := flag.String("server-name", "", "override the server name checked against the certificate")
+	flag.Usage = printUsage
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) < 1 {
 		printUsage()
 		os.Exit(1)
 	}
 
-	conn, err := grpc.NewClient("localhost:50051", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	creds := insecure.NewCredentials()
+	if *useTLS || *caFile != "" || *certFile != "" {
+		tlsConfig, err := tlsutil.ClientConfig(tlsutil.ClientOptions{
+			CAFile:     *caFile,
+			CertFile:   *certFile,
+			KeyFile:    *keyFile,
+			ServerName: *serverName,
+		})
+		if err != nil {
+			log.Fatalf("Failed to load TLS settings: %v", err)
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	conn, err := grpc.NewClient(*addr, grpc.WithTransportCredentials(creds))
 	if err != nil {
 		log.Fatalf("Failed to connect: %v", err)
 	}
@@ -27,19 +69,49 @@ func main() {
 	client := ticket.NewTicketServiceClient(conn)
 	ctx := context.Background()
 
-	command := os.Args[1]
+	command := args[0]
 
 	switch command {
 	case "purchase":
-		purchaseTicket(ctx, client, os.Args[2:])
+		purchaseTicket(ctx, client, args[1:])
+	case "verify-purchase":
+		verifyPurchase(ctx, client, args[1:])
 	case "receipt":
-		viewReceipt(ctx, client, os.Args[2:])
+		viewReceipt(ctx, client, args[1:])
+	case "download-receipt":
+		downloadReceipt(ctx, client, args[1:])
 	case "allocations":
-		viewAllocations(ctx, client, os.Args[2:])
+		viewAllocations(ctx, client, args[1:])
 	case "remove":
-		removeUser(ctx, client, os.Args[2:])
+		removeUser(ctx, client, args[1:])
 	case "modify":
-		modifySeat(ctx, client, os.Args[2:])
+		modifySeat(ctx, client, args[1:])
+	case "swap":
+		requestSeatSwap(ctx, client, args[1:])
+	case "accept-swap":
+		acceptSeatSwap(ctx, client, args[1:])
+	case "bulk":
+		bulkApply(ctx, client, args[1:])
+	case "decommission":
+		decommissionSection(ctx, client, args[1:])
+	case "reinstate":
+		reinstateSection(ctx, client, args[1:])
+	case "admin-purchase":
+		adminPurchaseTicket(ctx, client, args[1:])
+	case "watch":
+		watchOccupancy(ctx, client)
+	case "flagged":
+		listFlaggedPurchases(ctx, client, args[1:])
+	case "seatmap":
+		showSeatMap(ctx, client, args[1:])
+	case "export":
+		exportManifest(ctx, client, args[1:])
+	case "import":
+		importBookings(ctx, client, args[1:])
+	case "sync-revocations":
+		syncRevocations(ctx, client, args[1:])
+	case "verify":
+		verifyTicket(args[1:])
 	default:
 		fmt.Printf("Unknown command: %s\n", command)
 		printUsage()
@@ -47,21 +119,60 @@ func main() {
 	}
 }
 
+// authMetadata carries the JWT for a call. A token of "-" sends none, so the
+// server identifies the caller by its client certificate instead.
+func authMetadata(token string) metadata.MD {
+	if token == "-" {
+		return metadata.MD{}
+	}
+	return metadata.Pairs("authorization", "Bearer "+token)
+}
+
+func envOr(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return fallback
+}
+
 func printUsage() {
-	fmt.Println("Usage:")
-	fmt.Println("  purchase <first_name> <last_name> <email>")
-	fmt.Println("  receipt <jwt_token>")
-	fmt.Println("  allocations <jwt_token> [section]")
+	fmt.Println("Usage: client [-addr host:port] [-tls] [-ca ca.pem] [-cert client.pem -key client-key.pem] <command> ...")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  purchase <first_name> <last_name> <email> [jwt_token]")
+	fmt.Println("  verify-purchase <email> <code>")
+	fmt.Println("  receipt <jwt_token> [impersonate_email]")
+	fmt.Println("  download-receipt <jwt_token> <ticket.html|ticket.pdf> [impersonate_email]")
+	fmt.Println("  allocations <jwt_token> [section] [--search <text>] [--status <status>] [--order-by <field[ desc]>] [--page-size <n>]")
 	fmt.Println("  remove <jwt_token> [email]")
 	fmt.Println("  modify <jwt_token> <section> <seat_number> [email]")
+	fmt.Println("  swap <jwt_token> <counterparty_email> [--force <email>]")
+	fmt.Println("  accept-swap <jwt_token> <swap_id>")
+	fmt.Println("  bulk <admin_jwt_token> <operations_file> [--best-effort]")
+	fmt.Println("  decommission <admin_jwt_token> <section> [waitlist|refund]")
+	fmt.Println("  reinstate <admin_jwt_token> <section>")
+	fmt.Println("  admin-purchase <admin_jwt_token> <first_name> <last_name> <email>")
+	fmt.Println("  watch")
+	fmt.Println("  flagged <admin_jwt_token>")
+	fmt.Println("  seatmap [section] [--token <jwt_token>]")
+	fmt.Println("  export <admin_jwt_token> <output_file> [--format csv|jsonl|pdf] [--fields <a,b,...>] [--section <section>] [--mask-emails]")
+	fmt.Println("  import <admin_jwt_token> <csv_file> [--commit]")
+	fmt.Println("  sync-revocations <cache_file>")
+	fmt.Println("  verify <cache_file> <ticket_token>")
+	fmt.Println()
+	fmt.Println("Pass - as the jwt_token to authenticate with the -cert client certificate alone.")
 }
 
 func purchaseTicket(ctx context.Context, client ticket.TicketServiceClient, args []string) {
 	if len(args) < 3 {
-		fmt.Println("Usage: purchase <first_name> <last_name> <email>")
+		fmt.Println("Usage: purchase <first_name> <last_name> <email> [jwt_token]")
 		return
 	}
 
+	if len(args) > 3 {
+		ctx = metadata.NewOutgoingContext(ctx, authMetadata(args[3]))
+	}
+
 	req := &ticket.PurchaseTicketRequest{
 		FirstName: args[0],
 		LastName:  args[1],
@@ -69,8 +180,43 @@ func purchaseTicket(ctx context.Context, client ticket.TicketServiceClient, args
 	}
 
 	resp, err := client.PurchaseTicket(ctx, req)
+	if errorReason(err) == ticket.ErrorReason_CHALLENGE_REQUIRED.String() {
+		// Too many purchases from this address: prove some work and retry once
+		challenge, cerr := client.RequestPurchaseChallenge(ctx, &ticket.RequestPurchaseChallengeRequest{Email: req.Email})
+		if cerr != nil {
+			printError(cerr)
+			return
+		}
+		fmt.Printf("Solving purchase challenge (difficulty %d)...\n", challenge.Difficulty)
+		req.ChallengeToken = challenge.Token
+		req.ChallengeNonce = abuse.Solve(challenge.Token, int(challenge.Difficulty))
+		resp, err = client.PurchaseTicket(ctx, req)
+	}
 	if err != nil {
-		log.Printf("Error: %v", err)
+		printError(err)
+		return
+	}
+
+	printReceipt(resp.Receipt)
+	if resp.Receipt.Status == "pending_verification" {
+		fmt.Println("Check your email for a verification code, then run: verify-purchase <email> <code>")
+	}
+}
+
+func verifyPurchase(ctx context.Context, client ticket.TicketServiceClient, args []string) {
+	if len(args) < 2 {
+		fmt.Println("Usage: verify-purchase <email> <code>")
+		return
+	}
+
+	req := &ticket.VerifyPurchaseRequest{
+		Email: args[0],
+		Code:  args[1],
+	}
+
+	resp, err := client.VerifyPurchase(ctx, req)
+	if err != nil {
+		printError(err)
 		return
 	}
 
@@ -79,49 +225,118 @@ func purchaseTicket(ctx context.Context, client ticket.TicketServiceClient, args
 
 func viewReceipt(ctx context.Context, client ticket.TicketServiceClient, args []string) {
 	if len(args) < 1 {
-		fmt.Println("Usage: receipt <jwt_token>")
+		fmt.Println("Usage: receipt <jwt_token> [impersonate_email]")
 		return
 	}
 
-	ctx = metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{
-		"authorization": "Bearer " + args[0],
-	}))
+	md := authMetadata(args[0])
+	if len(args) > 1 {
+		md.Set(auth.ImpersonationHeader, args[1])
+	}
+	ctx = metadata.NewOutgoingContext(ctx, md)
 
 	req := &ticket.ViewUserReceiptRequest{}
 	resp, err := client.ViewUserReceipt(ctx, req)
 	if err != nil {
-		log.Printf("Error: %v", err)
+		printError(err)
 		return
 	}
 
 	printReceipt(resp.Receipt)
 }
 
-func viewAllocations(ctx context.Context, client ticket.TicketServiceClient, args []string) {
-	if len(args) < 1 {
-		fmt.Println("Usage: allocations <jwt_token> [section]")
+// downloadReceipt saves the e-ticket as HTML or PDF, following the file's extension.
+func downloadReceipt(ctx context.Context, client ticket.TicketServiceClient, args []string) {
+	if len(args) < 2 {
+		fmt.Println("Usage: download-receipt <jwt_token> <ticket.html|ticket.pdf> [impersonate_email]")
 		return
 	}
 
-	ctx = metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{
-		"authorization": "Bearer " + args[0],
-	}))
+	md := authMetadata(args[0])
+	if len(args) > 2 {
+		md.Set(auth.ImpersonationHeader, args[2])
+	}
+	ctx = metadata.NewOutgoingContext(ctx, md)
+	path := args[1]
 
-	req := &ticket.ViewAllocationsRequest{}
-	if len(args) > 1 {
-		req.Section = args[1]
+	req := &ticket.DownloadReceiptRequest{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".pdf":
+		req.Format = "pdf"
+	case ".html", ".htm":
+		req.Format = "html"
+	default:
+		log.Printf("Error: %s: the file must end in .html or .pdf", path)
+		return
 	}
 
-	resp, err := client.ViewAllocations(ctx, req)
+	body, err := client.DownloadReceipt(ctx, req)
 	if err != nil {
+		printError(err)
+		return
+	}
+	if err := os.WriteFile(path, body.Data, 0o644); err != nil {
 		log.Printf("Error: %v", err)
 		return
 	}
 
-	fmt.Printf("Total allocations: %d\n\n", len(resp.Allocations))
-	for _, alloc := range resp.Allocations {
-		fmt.Printf("Section: %s, Seat: %d\n", alloc.Section, alloc.SeatNumber)
-		fmt.Printf("  User: %s %s (%s)\n\n", alloc.User.FirstName, alloc.User.LastName, alloc.User.Email)
+	fmt.Printf("E-ticket written to %s (%d bytes)\n", path, len(body.Data))
+}
+
+func viewAllocations(ctx context.Context, client ticket.TicketServiceClient, args []string) {
+	const usage = "Usage: allocations <jwt_token> [section] [--search <text>] [--status <status>] [--order-by <field[ desc]>] [--page-size <n>]"
+	if len(args) < 1 {
+		fmt.Println(usage)
+		return
+	}
+
+	ctx = metadata.NewOutgoingContext(ctx, authMetadata(args[0]))
+
+	req := &ticket.ViewAllocationsRequest{}
+	for i := 1; i < len(args); i++ {
+		if !strings.HasPrefix(args[i], "--") {
+			req.Section = args[i]
+			continue
+		}
+		if i+1 >= len(args) {
+			fmt.Println(usage)
+			return
+		}
+		switch value := args[i+1]; args[i] {
+		case "--search":
+			req.Search = value
+		case "--status":
+			req.Status = value
+		case "--order-by":
+			req.OrderBy = value
+		case "--page-size":
+			if _, err := fmt.Sscanf(value, "%d", &req.PageSize); err != nil {
+				fmt.Println(usage)
+				return
+			}
+		default:
+			fmt.Println(usage)
+			return
+		}
+		i++
+	}
+
+	// Follow the page tokens so the listing is complete
+	for first := true; first || req.PageToken != ""; first = false {
+		resp, err := client.ViewAllocations(ctx, req)
+		if err != nil {
+			printError(err)
+			return
+		}
+
+		if first {
+			fmt.Printf("Total allocations: %d\n\n", resp.TotalSize)
+		}
+		for _, alloc := range resp.Allocations {
+			fmt.Printf("Section: %s, Seat: %d (%s)\n", alloc.Section, alloc.SeatNumber, alloc.Status)
+			fmt.Printf("  User: %s %s (%s)\n\n", alloc.User.FirstName, alloc.User.LastName, alloc.User.Email)
+		}
+		req.PageToken = resp.NextPageToken
 	}
 }
 
@@ -131,9 +346,7 @@ func removeUser(ctx context.Context, client ticket.TicketServiceClient, args []s
 		return
 	}
 
-	ctx = metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{
-		"authorization": "Bearer " + args[0],
-	}))
+	ctx = metadata.NewOutgoingContext(ctx, authMetadata(args[0]))
 
 	req := &ticket.RemoveUserFromTrainRequest{}
 	if len(args) > 1 {
@@ -142,7 +355,7 @@ func removeUser(ctx context.Context, client ticket.TicketServiceClient, args []s
 
 	resp, err := client.RemoveUserFromTrain(ctx, req)
 	if err != nil {
-		log.Printf("Error: %v", err)
+		printError(err)
 		return
 	}
 
@@ -155,9 +368,7 @@ func modifySeat(ctx context.Context, client ticket.TicketServiceClient, args []s
 		return
 	}
 
-	ctx = metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{
-		"authorization": "Bearer " + args[0],
-	}))
+	ctx = metadata.NewOutgoingContext(ctx, authMetadata(args[0]))
 
 	var seatNumber int32
 	fmt.Sscanf(args[2], "%d", &seatNumber)
@@ -172,7 +383,7 @@ func modifySeat(ctx context.Context, client ticket.TicketServiceClient, args []s
 
 	resp, err := client.ModifyUserSeat(ctx, req)
 	if err != nil {
-		log.Printf("Error: %v", err)
+		printError(err)
 		return
 	}
 
@@ -180,6 +391,662 @@ func modifySeat(ctx context.Context, client ticket.TicketServiceClient, args []s
 	printReceipt(resp.Receipt)
 }
 
+func adminPurchaseTicket(ctx context.Context, client ticket.TicketServiceClient, args []string) {
+	if len(args) < 4 {
+		fmt.Println("Usage: admin-purchase <admin_jwt_token> <first_name> <last_name> <email>")
+		return
+	}
+
+	ctx = metadata.NewOutgoingContext(ctx, authMetadata(args[0]))
+
+	req := &ticket.AdminPurchaseTicketRequest{
+		Passenger: &ticket.User{
+			FirstName: args[1],
+			LastName:  args[2],
+			Email:     args[3],
+		},
+	}
+
+	resp, err := client.AdminPurchaseTicket(ctx, req)
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	printReceipt(resp.Receipt)
+}
+
+func requestSeatSwap(ctx context.Context, client ticket.TicketServiceClient, args []string) {
+	if len(args) < 2 {
+		fmt.Println("Usage: swap <jwt_token> <counterparty_email> [--force <email>]")
+		return
+	}
+
+	ctx = metadata.NewOutgoingContext(ctx, authMetadata(args[0]))
+
+	req := &ticket.RequestSeatSwapRequest{
+		CounterpartyEmail: args[1],
+	}
+	if len(args) > 3 && args[2] == "--force" {
+		req.Force = true
+		req.Email = args[3]
+	}
+
+	resp, err := client.RequestSeatSwap(ctx, req)
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	printSeatSwap(resp.Swap)
+}
+
+func acceptSeatSwap(ctx context.Context, client ticket.TicketServiceClient, args []string) {
+	if len(args) < 2 {
+		fmt.Println("Usage: accept-swap <jwt_token> <swap_id>")
+		return
+	}
+
+	ctx = metadata.NewOutgoingContext(ctx, authMetadata(args[0]))
+
+	resp, err := client.AcceptSeatSwap(ctx, &ticket.AcceptSeatSwapRequest{SwapId: args[1]})
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	printSeatSwap(resp.Swap)
+}
+
+// bulkApply reads one operation per line from a file:
+//
+//	remove <email>
+//	move <email> <section> <seat_number>
+//	reassign <email> [section]
+func bulkApply(ctx context.Context, client ticket.TicketServiceClient, args []string) {
+	if len(args) < 2 {
+		fmt.Println("Usage: bulk <admin_jwt_token> <operations_file> [--best-effort]")
+		return
+	}
+
+	ctx = metadata.NewOutgoingContext(ctx, authMetadata(args[0]))
+
+	f, err := os.Open(args[1])
+	if err != nil {
+		printError(err)
+		return
+	}
+	defer f.Close()
+
+	req := &ticket.BulkApplyRequest{
+		BestEffort: len(args) > 2 && args[2] == "--best-effort",
+	}
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		op, err := parseBulkOperation(fields)
+		if err != nil {
+			log.Printf("Error: line %d: %v", line, err)
+			return
+		}
+		req.Operations = append(req.Operations, op)
+	}
+	if err := scanner.Err(); err != nil {
+		printError(err)
+		return
+	}
+
+	resp, err := client.BulkApply(ctx, req)
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	fmt.Printf("Applied: %t\n", resp.Applied)
+	for _, r := range resp.Results {
+		switch {
+		case r.Success && r.Seat != nil:
+			fmt.Printf("  #%d ok -> %s-%d\n", r.Index, r.Seat.Section, r.Seat.SeatNumber)
+		case r.Success:
+			fmt.Printf("  #%d ok\n", r.Index)
+		default:
+			code := r.Code
+			if r.Reason != "" {
+				code += " " + r.Reason
+			}
+			fmt.Printf("  #%d %s: %s\n", r.Index, code, r.Message)
+		}
+	}
+}
+
+func parseBulkOperation(fields []string) (*ticket.BulkOperation, error) {
+	switch {
+	case fields[0] == "remove" && len(fields) == 2:
+		return &ticket.BulkOperation{Operation: &ticket.BulkOperation_Remove{
+			Remove: &ticket.RemoveOperation{Email: fields[1]},
+		}}, nil
+	case fields[0] == "move" && len(fields) == 4:
+		var seatNumber int32
+		if _, err := fmt.Sscanf(fields[3], "%d", &seatNumber); err != nil {
+			return nil, fmt.Errorf("invalid seat number %q", fields[3])
+		}
+		return &ticket.BulkOperation{Operation: &ticket.BulkOperation_Move{
+			Move: &ticket.MoveOperation{Email: fields[1], Section: fields[2], SeatNumber: seatNumber},
+		}}, nil
+	case fields[0] == "reassign" && (len(fields) == 2 || len(fields) == 3):
+		op := &ticket.ReassignOperation{Email: fields[1]}
+		if len(fields) == 3 {
+			op.Section = fields[2]
+		}
+		return &ticket.BulkOperation{Operation: &ticket.BulkOperation_Reassign{Reassign: op}}, nil
+	default:
+		return nil, fmt.Errorf("unrecognised operation %q", strings.Join(fields, " "))
+	}
+}
+
+func decommissionSection(ctx context.Context, client ticket.TicketServiceClient, args []string) {
+	if len(args) < 2 {
+		fmt.Println("Usage: decommission <admin_jwt_token> <section> [waitlist|refund]")
+		return
+	}
+
+	ctx = metadata.NewOutgoingContext(ctx, authMetadata(args[0]))
+
+	req := &ticket.DecommissionSectionRequest{Section: args[1]}
+	if len(args) > 2 {
+		req.OverflowPolicy = args[2]
+	}
+
+	resp, err := client.DecommissionSection(ctx, req)
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	report := resp.Report
+	fmt.Printf("Section %s is out of service\n", report.Section)
+	fmt.Printf("Moved: %d\n", len(report.Moved))
+	printSeatMoves(report.Moved)
+	fmt.Printf("Waitlisted: %d\n", len(report.Waitlisted))
+	for _, p := range report.Waitlisted {
+		fmt.Printf("  %s (was %s-%d)\n", p.User.Email, p.From.Section, p.From.SeatNumber)
+	}
+	fmt.Printf("Refunded: %d\n", len(report.Refunded))
+	for _, p := range report.Refunded {
+		fmt.Printf("  %s (was %s-%d) $%.2f\n", p.User.Email, p.From.Section, p.From.SeatNumber, float64(p.RefundCents)/100)
+	}
+}
+
+func reinstateSection(ctx context.Context, client ticket.TicketServiceClient, args []string) {
+	if len(args) < 2 {
+		fmt.Println("Usage: reinstate <admin_jwt_token> <section>")
+		return
+	}
+
+	ctx = metadata.NewOutgoingContext(ctx, authMetadata(args[0]))
+
+	resp, err := client.ReinstateSection(ctx, &ticket.ReinstateSectionRequest{Section: args[1]})
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	fmt.Printf("Section %s is back in service\n", args[1])
+	fmt.Printf("Seated from waitlist: %d\n", len(resp.Placed))
+	printSeatMoves(resp.Placed)
+}
+
+func watchOccupancy(ctx context.Context, client ticket.TicketServiceClient) {
+	stream, err := client.WatchOccupancy(ctx, &ticket.WatchOccupancyRequest{})
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	for {
+		update, err := stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			printError(err)
+			return
+		}
+
+		fmt.Printf("[%s]", time.Now().Format(time.TimeOnly))
+		for _, section := range update.Sections {
+			state := ""
+			if !section.InService {
+				state = " out of service"
+			}
+			fmt.Printf(" %s: %d/%d%s", section.Section, section.Occupied, section.Capacity, state)
+		}
+		fmt.Printf(", holds: %d, waitlist: %d\n", update.Holds, update.WaitlistLength)
+	}
+}
+
+func listFlaggedPurchases(ctx context.Context, client ticket.TicketServiceClient, args []string) {
+	if len(args) < 1 {
+		fmt.Println("Usage: flagged <admin_jwt_token>")
+		return
+	}
+
+	ctx = metadata.NewOutgoingContext(ctx, authMetadata(args[0]))
+
+	resp, err := client.ListFlaggedPurchases(ctx, &ticket.ListFlaggedPurchasesRequest{})
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	fmt.Printf("Flagged purchases: %d\n", len(resp.Purchases))
+	for _, p := range resp.Purchases {
+		fmt.Printf("  %s %s from %s: %s (%s)\n", p.FlaggedAt, p.Email, p.Ip, p.Reason, p.Action)
+	}
+}
+
+func showSeatMap(ctx context.Context, client ticket.TicketServiceClient, args []string) {
+	req := &ticket.GetSeatMapRequest{}
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--token" && i+1 < len(args):
+			ctx = metadata.NewOutgoingContext(ctx, authMetadata(args[i+1]))
+			i++
+		case req.Section == "":
+			req.Section = args[i]
+		default:
+			fmt.Println("Usage: seatmap [section] [--token <jwt_token>]")
+			return
+		}
+	}
+
+	resp, err := client.GetSeatMap(ctx, req)
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	for _, section := range resp.Sections {
+		printSeatGrid(section, resp.SeatsPerRow, resp.AisleAfter)
+	}
+	fmt.Println("Legend: [n ] free  [nH] held  [nX] occupied  [n#] blocked  [n*] yours  | aisle")
+}
+
+func exportManifest(ctx context.Context, client ticket.TicketServiceClient, args []string) {
+	const usage = "Usage: export <admin_jwt_token> <output_file> [--format csv|jsonl|pdf] [--fields <a,b,...>] [--section <section>] [--mask-emails]"
+	if len(args) < 2 {
+		fmt.Println(usage)
+		return
+	}
+
+	ctx = metadata.NewOutgoingContext(ctx, authMetadata(args[0]))
+	path := args[1]
+
+	req := &ticket.ExportManifestRequest{}
+	// The format defaults to the file's extension
+	switch ext := strings.TrimPrefix(filepath.Ext(path), "."); ext {
+	case "csv", "jsonl", "pdf":
+		req.Format = ext
+	}
+	for i := 2; i < len(args); i++ {
+		if args[i] == "--mask-emails" {
+			req.MaskEmails = true
+			continue
+		}
+		if i+1 >= len(args) {
+			fmt.Println(usage)
+			return
+		}
+		switch value := args[i+1]; args[i] {
+		case "--format":
+			req.Format = value
+		case "--fields":
+			req.Fields = strings.Split(value, ",")
+		case "--section":
+			req.Section = value
+		default:
+			fmt.Println(usage)
+			return
+		}
+		i++
+	}
+
+	stream, err := client.ExportManifest(ctx, req)
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return
+	}
+	// Don't leave a partial manifest behind that looks complete
+	fail := func() {
+		f.Close()
+		os.Remove(path)
+	}
+	size := 0
+	for {
+		body, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			fail()
+			printError(err)
+			return
+		}
+		if _, err := f.Write(body.Data); err != nil {
+			fail()
+			log.Printf("Error: %v", err)
+			return
+		}
+		size += len(body.Data)
+	}
+	if err := f.Close(); err != nil {
+		log.Printf("Error: %v", err)
+		return
+	}
+
+	fmt.Printf("Manifest written to %s (%d bytes)\n", path, size)
+}
+
+// importColumns are the CSV columns a bookings import needs, in any order.
+var importColumns = []string{"first_name", "last_name", "email", "section", "seat_number"}
+
+// importBatchSize is how many rows go in each message of an import.
+const importBatchSize = 500
+
+// importBookings reads a CSV file whose header names the importColumns. Rows
+// are numbered by their line in the file, so conflicts can be found there.
+func importBookings(ctx context.Context, client ticket.TicketServiceClient, args []string) {
+	if len(args) < 2 {
+		fmt.Println("Usage: import <admin_jwt_token> <csv_file> [--commit]")
+		return
+	}
+
+	ctx = metadata.NewOutgoingContext(ctx, authMetadata(args[0]))
+	commit := len(args) > 2 && args[2] == "--commit"
+
+	f, err := os.Open(args[1])
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.TrimLeadingSpace = true
+	header, err := r.Read()
+	if err != nil {
+		log.Printf("Error: reading header: %v", err)
+		return
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range importColumns {
+		if _, ok := columns[name]; !ok {
+			log.Printf("Error: the header has no %s column", name)
+			return
+		}
+	}
+
+	var rows []*ticket.BookingRow
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Printf("Error: %v", err)
+			return
+		}
+		line, _ := r.FieldPos(0)
+		row := &ticket.BookingRow{
+			Row:       int32(line),
+			FirstName: record[columns["first_name"]],
+			LastName:  record[columns["last_name"]],
+			Email:     record[columns["email"]],
+			Section:   record[columns["section"]],
+		}
+		if _, err := fmt.Sscanf(record[columns["seat_number"]], "%d", &row.SeatNumber); err != nil {
+			log.Printf("Error: line %d: invalid seat number %q", line, record[columns["seat_number"]])
+			return
+		}
+		rows = append(rows, row)
+	}
+
+	stream, err := client.ImportBookings(ctx)
+	if err != nil {
+		printError(err)
+		return
+	}
+	req := &ticket.ImportBookingsRequest{Commit: commit}
+	for start := 0; start == 0 || start < len(rows); start += importBatchSize {
+		req.Rows = rows[start:min(start+importBatchSize, len(rows))]
+		// A failed send is reported by CloseAndRecv
+		if err := stream.Send(req); err != nil {
+			break
+		}
+		req = &ticket.ImportBookingsRequest{}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	fmt.Printf("Rows: %d, valid: %d, committed: %t\n", resp.TotalRows, resp.ValidRows, resp.Committed)
+	for _, c := range resp.Conflicts {
+		reason := ""
+		if c.Reason != "" {
+			reason = " " + c.Reason
+		}
+		fmt.Printf("  line %d %s%s: %s\n", c.Row, c.Field, reason, c.Description)
+	}
+	if !resp.Committed && commit {
+		fmt.Println("Nothing was imported; fix the conflicts and try again.")
+	}
+}
+
+// seatMarks is how each seat status is drawn in the seat map grid.
+var seatMarks = map[string]string{"free": " ", "held": "H", "occupied": "X", "blocked": "#"}
+
+// printSeatGrid draws one section as rows of seats with the aisle between them.
+// syncRevocations brings the conductor's cache up to date: the signing keys
+// and the revocations since the last sync.
+func syncRevocations(ctx context.Context, client ticket.TicketServiceClient, args []string) {
+	if len(args) < 1 {
+		fmt.Println("Usage: sync-revocations <cache_file>")
+		return
+	}
+	path := args[0]
+
+	verifier, err := loadVerifier(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Error: %v", err)
+		return
+	}
+
+	resp, err := client.SyncRevocations(ctx, &ticket.SyncRevocationsRequest{
+		Since: verifier.Revocations.Cursor,
+		Epoch: verifier.Revocations.Epoch,
+	})
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	verifier.Train = resp.Train
+	for _, key := range resp.Keys {
+		verifier.AddKey(key.PublicKey)
+	}
+	for _, r := range resp.Revocations {
+		verifier.Revocations.Add(r.TicketId, r.Revision, r.Reason)
+	}
+	verifier.Revocations.Cursor = resp.Cursor
+	verifier.Revocations.Epoch = resp.Epoch
+	verifier.SyncedAt = time.Now()
+
+	data, err := json.MarshalIndent(verifier, "", "  ")
+	if err == nil {
+		err = os.WriteFile(path, data, 0o644)
+	}
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return
+	}
+
+	fmt.Printf("Synced %d new revocations for %s (%d revoked tickets, %d keys)\n",
+		len(resp.Revocations), resp.Train, len(verifier.Revocations.Tickets), len(verifier.Keys))
+}
+
+// verifyTicket checks a ticket token against the cache from sync-revocations,
+// without contacting the server. It exits non-zero for a ticket to refuse.
+func verifyTicket(args []string) {
+	if len(args) < 2 {
+		fmt.Println("Usage: verify <cache_file> <ticket_token>")
+		return
+	}
+
+	verifier, err := loadVerifier(args[0])
+	if err != nil {
+		log.Printf("Error: %v (run sync-revocations first)", err)
+		os.Exit(1)
+	}
+
+	claims, err := verifier.VerifyTicket(strings.TrimSpace(args[1]))
+	if claims != nil {
+		fmt.Printf("Passenger: %s\n", claims.Name)
+		fmt.Printf("Seat: %s-%d\n", claims.Section, claims.SeatNumber)
+		fmt.Printf("Train: %s\n", claims.Train)
+		fmt.Printf("Ticket: %s (revision %d)\n", claims.ID, claims.Revision)
+	}
+	fmt.Printf("Revocations as of %s\n", verifier.SyncedAt.Local().Format(time.DateTime))
+	if err != nil {
+		fmt.Printf("INVALID: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("VALID")
+}
+
+func loadVerifier(path string) (*tickettoken.Verifier, error) {
+	verifier := &tickettoken.Verifier{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return verifier, err
+	}
+	if err := json.Unmarshal(data, verifier); err != nil {
+		return verifier, fmt.Errorf("%s: %w", path, err)
+	}
+	return verifier, nil
+}
+
+func printSeatGrid(section *ticket.SeatMapSection, seatsPerRow, aisleAfter int32) {
+	state := ""
+	if !section.InService {
+		state = " (out of service)"
+	}
+	fmt.Printf("Section %s%s\n", section.Section, state)
+
+	width := 1
+	if n := len(section.Seats); n > 0 {
+		width = len(fmt.Sprint(section.Seats[n-1].SeatNumber))
+	}
+
+	var row int32
+	for i, seat := range section.Seats {
+		if seat.Row != row {
+			if row != 0 {
+				fmt.Println()
+			}
+			row = seat.Row
+			fmt.Printf("  row %2d ", row)
+		}
+		mark, ok := seatMarks[seat.Status]
+		if !ok {
+			mark = "?"
+		}
+		if seat.Yours {
+			mark = "*"
+		}
+		fmt.Printf("[%*d%s]", width, seat.SeatNumber, mark)
+		if pos := (seat.SeatNumber-1)%seatsPerRow + 1; pos == aisleAfter && i < len(section.Seats)-1 {
+			fmt.Print(" | ")
+		}
+	}
+	fmt.Println()
+}
+
+// errorReason returns the ErrorInfo reason of a failed call, or "".
+func errorReason(err error) string {
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+// printError prints a failed call with the details the server attached: the
+// error reason and its metadata, invalid fields and seats to try instead.
+func printError(err error) {
+	st := status.Convert(err)
+	log.Printf("Error: %s: %s", st.Code(), st.Message())
+
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			var meta []string
+			for _, k := range slices.Sorted(maps.Keys(d.Metadata)) {
+				meta = append(meta, k+"="+d.Metadata[k])
+			}
+			if len(meta) > 0 {
+				log.Printf("  reason: %s (%s)", d.Reason, strings.Join(meta, ", "))
+			} else {
+				log.Printf("  reason: %s", d.Reason)
+			}
+		case *errdetails.BadRequest:
+			for _, fv := range d.FieldViolations {
+				log.Printf("  invalid %s: %s", fv.Field, fv.Description)
+			}
+		case *ticket.SeatSuggestions:
+			seats := make([]string, len(d.Seats))
+			for i, seat := range d.Seats {
+				seats[i] = fmt.Sprintf("%s-%d", seat.Section, seat.SeatNumber)
+			}
+			if len(seats) > 0 {
+				log.Printf("  free seats nearby: %s", strings.Join(seats, ", "))
+			}
+		}
+	}
+}
+
+func printSeatMoves(moves []*ticket.SeatMove) {
+	for _, m := range moves {
+		fmt.Printf("  %s: %s-%d -> %s-%d\n", m.User.Email, m.From.Section, m.From.SeatNumber, m.To.Section, m.To.SeatNumber)
+	}
+}
+
+func printSeatSwap(swap *ticket.SeatSwap) {
+	fmt.Printf("Swap %s (%s)\n", swap.Id, swap.Status)
+	fmt.Printf("  %s: %s-%d\n", swap.RequesterEmail, swap.RequesterSeat.Section, swap.RequesterSeat.SeatNumber)
+	if swap.CounterpartySeat == nil {
+		fmt.Printf("  %s: seat shown once accepted\n", swap.CounterpartyEmail)
+		return
+	}
+	fmt.Printf("  %s: %s-%d\n", swap.CounterpartyEmail, swap.CounterpartySeat.Section, swap.CounterpartySeat.SeatNumber)
+}
+
 func printReceipt(receipt *ticket.Receipt) {
 	fmt.Println("=== Receipt ===")
 	fmt.Printf("From: %s\n", receipt.From)
@@ -187,6 +1054,17 @@ func printReceipt(receipt *ticket.Receipt) {
 	fmt.Printf("User: %s %s (%s)\n", receipt.User.FirstName, receipt.User.LastName, receipt.User.Email)
 	fmt.Printf("Price: $%.2f\n", float64(receipt.PricePaid)/100)
 	fmt.Printf("Seat: %s-%d\n", receipt.Seat.Section, receipt.Seat.SeatNumber)
+	if receipt.Status != "" {
+		fmt.Printf("Status: %s\n", receipt.Status)
+	}
+	if receipt.PurchasedBy != "" {
+		fmt.Printf("Booked by: %s\n", receipt.PurchasedBy)
+	}
+	if receipt.TicketId != "" {
+		fmt.Printf("Ticket: %s\n", receipt.TicketId)
+	}
+	if receipt.TicketToken != "" {
+		fmt.Printf("Token: %s\n", receipt.TicketToken)
+	}
 	fmt.Println("==============")
 }
-
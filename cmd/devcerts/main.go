@@ -0,0 +1,27 @@
+// Command devcerts writes a throwaway CA, server and client certificate for
+// running the service over TLS locally. Do not use the output in production.
+package main
+
+import (
+	"flag"
+	"log"
+	"strings"
+
+	"github.com/cloudbees/train-ticket-service/internal/tlsutil"
+)
+
+func main() {
+	out := flag.String("out", "certs", "directory to write the PEM files to")
+	hosts := flag.String("hosts", "localhost,127.0.0.1,::1", "comma-separated DNS names and IPs for the server certificate")
+	clientCN := flag.String("client-cn", "ticket-client", "common name of the client certificate")
+	flag.Parse()
+
+	certs, err := tlsutil.GenerateDevCerts(*out, strings.Split(*hosts, ","), *clientCN)
+	if err != nil {
+		log.Fatalf("Failed to generate certificates: %v", err)
+	}
+
+	log.Printf("CA:     %s", certs.CAFile)
+	log.Printf("Server: %s %s", certs.ServerCertFile, certs.ServerKeyFile)
+	log.Printf("Client: %s %s (subject CN=%s)", certs.ClientCertFile, certs.ClientKeyFile, *clientCN)
+}
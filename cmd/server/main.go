@@ -1,37 +1,322 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
 	"log"
+	"log/slog"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
 
 	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/abuse"
+	"github.com/cloudbees/train-ticket-service/internal/audit"
+	"github.com/cloudbees/train-ticket-service/internal/auth"
+	"github.com/cloudbees/train-ticket-service/internal/config"
+	"github.com/cloudbees/train-ticket-service/internal/gateway"
+	"github.com/cloudbees/train-ticket-service/internal/grpcweb"
+	"github.com/cloudbees/train-ticket-service/internal/logging"
+	"github.com/cloudbees/train-ticket-service/internal/metrics"
+	"github.com/cloudbees/train-ticket-service/internal/ratelimit"
+	"github.com/cloudbees/train-ticket-service/internal/receipt"
+	"github.com/cloudbees/train-ticket-service/internal/server"
 	"github.com/cloudbees/train-ticket-service/internal/service"
 	"github.com/cloudbees/train-ticket-service/internal/store"
+	"github.com/cloudbees/train-ticket-service/internal/tickettoken"
+	"github.com/cloudbees/train-ticket-service/internal/tlsutil"
+	"github.com/cloudbees/train-ticket-service/internal/tracing"
+	"github.com/cloudbees/train-ticket-service/internal/verification"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
 )
 
 func main() {
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	configPath := fs.String("config", os.Getenv("TICKET_CONFIG"), "path to a YAML or TOML config file (env TICKET_CONFIG)")
+	printConfig := fs.Bool("print-config", false, "print the effective configuration and exit")
+	overrides := config.RegisterFlags(fs)
+	fs.Parse(os.Args[1:])
+
+	// Load configuration: defaults, then file, then env, then flags
+	cfg, err := config.Load(*configPath, os.LookupEnv, overrides)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	if *printConfig {
+		cfg.WriteYAML(os.Stdout)
+		return
+	}
+
+	// Structured JSON logs; the standard log package is routed through the same handler
+	logger, err := logging.NewLogger(os.Stderr, cfg.Logging.Level)
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	slog.SetDefault(logger)
+
+	redactor, err := logging.NewRedactor(cfg.Logging.Redaction)
+	if err != nil {
+		fatal("Failed to create redactor", err)
+	}
+
+	var effective bytes.Buffer
+	cfg.WriteYAML(&effective)
+	slog.Info("effective configuration", "config", effective.String())
+
+	// Set up tracing before anything starts spans
+	exporter, err := tracing.NewExporter(cfg.Tracing.Exporter, os.Stdout)
+	if err != nil {
+		fatal("Failed to create trace exporter", err)
+	}
+	tracerProvider := tracing.NewProvider(exporter, cfg.Tracing.SampleRatio)
+	tracing.Install(tracerProvider)
+
 	// Create store
-	s := store.NewStore()
+	s := store.NewStoreWithLayout(cfg.SeatLayout())
 
 	// Create service
-	ticketService := service.NewTicketService(s)
+	auditLog := audit.NewLog(logger, redactor, os.Stderr)
+	m := metrics.New()
+	m.WatchStore(cfg.TrainName(), s)
+	serviceOpts := []service.Option{
+		service.WithConfig(cfg),
+		service.WithAuditRecorder(auditLog),
+		service.WithMetrics(m),
+		service.WithVerificationSender(verification.LogSender{
+			Logger:    logger,
+			Redactor:  redactor,
+			ShowCodes: cfg.Auth.LogVerificationCodes,
+		}),
+	}
+	var tokens *auth.Verifier
+	if cfg.Auth.JWTKeyFile != "" {
+		tokens, err = auth.LoadVerifier(cfg.Auth.JWTKeyFile, cfg.Auth.JWTAlgorithms)
+		if err != nil {
+			fatal("Failed to load JWT verification key", err)
+		}
+		serviceOpts = append(serviceOpts, service.WithTokenVerifier(tokens))
+	} else {
+		slog.Warn("no auth.jwt_key_file set; every JWT is rejected")
+	}
+	if cfg.Tickets.SigningKeyFile != "" {
+		signer, err := tickettoken.LoadSigner(cfg.Tickets.SigningKeyFile)
+		if err != nil {
+			fatal("Failed to load ticket signing key", err)
+		}
+		serviceOpts = append(serviceOpts, service.WithTicketSigner(signer))
+	} else {
+		slog.Warn("no tickets.signing_key_file set; ticket tokens are signed with a temporary key and stop verifying on restart")
+	}
+	receipts, err := receipt.New(cfg.Receipt.HTMLTemplate, cfg.Receipt.PDFTemplate)
+	if err != nil {
+		fatal("Failed to load receipt templates", err)
+	}
+	serviceOpts = append(serviceOpts, service.WithReceiptRenderer(receipts))
+	if cfg.Abuse.Enabled {
+		guard, err := abuse.NewGuard(abuseGuardConfig(cfg.Abuse))
+		if err != nil {
+			fatal("Failed to create abuse guard", err)
+		}
+		serviceOpts = append(serviceOpts, service.WithAbuseGuard(guard))
+	}
+	ticketService := service.NewTicketService(s, serviceOpts...)
 
 	// Create gRPC server
-	grpcServer := grpc.NewServer()
+	unary := []grpc.UnaryServerInterceptor{
+		logging.UnaryServerInterceptor(logger, redactor),
+		tracing.UnaryServerInterceptor(),
+		m.UnaryServerInterceptor(),
+	}
+	stream := []grpc.StreamServerInterceptor{
+		logging.StreamServerInterceptor(logger),
+		tracing.StreamServerInterceptor(),
+		m.StreamServerInterceptor(),
+	}
+	if cfg.RateLimit.Enabled {
+		rules, err := rateLimitRules(cfg.RateLimit)
+		if err != nil {
+			fatal("Invalid rate limits", err)
+		}
+		// Innermost, so rejected calls are still logged, traced and counted
+		limiter := ratelimit.New(ratelimit.NewMemoryStore(), rules, tokens)
+		unary = append(unary, limiter.UnaryServerInterceptor())
+		stream = append(stream, limiter.StreamServerInterceptor())
+	}
+	interceptors := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+	opts := slices.Clip(interceptors)
+	var tlsConfig *tls.Config
+	if cfg.TLS.Enabled {
+		// The certificate is re-read from disk when it changes, so rotation needs no restart
+		tlsConfig, err = tlsutil.ServerConfig(serverTLSOptions(cfg.TLS))
+		if err != nil {
+			fatal("Failed to load TLS credentials", err)
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	grpcServer := grpc.NewServer(opts...)
 
 	// Register service
 	ticket.RegisterTicketServiceServer(grpcServer, ticketService)
 
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	if cfg.Server.Reflection {
+		reflection.Register(grpcServer)
+	}
+
 	// Start listening
-	lis, err := net.Listen("tcp", ":50051")
+	lis, err := net.Listen("tcp", cfg.Server.ListenAddr)
 	if err != nil {
-		log.Fatalf("Failed to listen: %v", err)
+		fatal("Failed to listen", err)
+	}
+
+	hooks := []server.Hook{
+		{Name: "audit log", Run: func(context.Context) error { return auditLog.Flush() }},
+		{Name: "store", Run: func(context.Context) error { return s.Close() }},
+		{Name: "tracing", Run: tracerProvider.Shutdown},
+	}
+
+	// Stop on SIGINT or SIGTERM; a second signal exits immediately
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	if cfg.Gateway.ListenAddr != "" || cfg.GRPCWeb.ListenAddr != "" {
+		// HTTP callers reach the service through in-process gRPC servers with the
+		// same interceptors, so auth, logging and metrics apply to them unchanged
+		var inproc []*grpc.Server
+		var waits []func() error
+		if cfg.Gateway.ListenAddr != "" {
+			// Only the gateway dials this server, so it may trust the client
+			// certificate subject the gateway passes on
+			gatewayServer := grpc.NewServer(append([]grpc.ServerOption{
+				grpc.ChainUnaryInterceptor(auth.TrustedSubjectUnaryInterceptor()),
+				grpc.ChainStreamInterceptor(auth.TrustedSubjectStreamInterceptor()),
+			}, interceptors...)...)
+			ticket.RegisterTicketServiceServer(gatewayServer, ticketService)
+			inproc = append(inproc, gatewayServer)
+
+			conn, err := dialInProcess(gatewayServer)
+			if err != nil {
+				fatal("Failed to start gateway", err)
+			}
+			defer conn.Close()
+			handler := gateway.New(ticket.NewTicketServiceClient(conn))
+			waits = append(waits, serveHTTP(ctx, "HTTP gateway", cfg.Gateway.ListenAddr, handler, tlsConfig, cfg.Server.ShutdownTimeout))
+		}
+		if cfg.GRPCWeb.ListenAddr != "" {
+			// Calls are served over HTTP, so the peer carries the client certificate itself
+			grpcWebServer := grpc.NewServer(interceptors...)
+			ticket.RegisterTicketServiceServer(grpcWebServer, ticketService)
+			inproc = append(inproc, grpcWebServer)
+
+			handler := grpcweb.New(grpcWebServer, cfg.GRPCWeb.AllowedOrigins)
+			waits = append(waits, serveHTTP(ctx, "gRPC-Web", cfg.GRPCWeb.ListenAddr, handler, tlsConfig, cfg.Server.ShutdownTimeout))
+		}
+
+		hooks = append([]server.Hook{{Name: "http", Run: func(context.Context) error {
+			var errs []error
+			for _, wait := range waits {
+				errs = append(errs, wait())
+			}
+			for _, srv := range inproc {
+				srv.Stop()
+			}
+			return errors.Join(errs...)
+		}}}, hooks...)
 	}
 
-	log.Println("gRPC server starting on :50051")
-	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatalf("Failed to serve: %v", err)
+	if cfg.Metrics.ListenAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", m.Handler())
+		metricsServer := &http.Server{Addr: cfg.Metrics.ListenAddr, Handler: mux}
+		go func() {
+			slog.Info("metrics server starting", "addr", cfg.Metrics.ListenAddr, "path", "/metrics")
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("metrics server failed", "error", err)
+			}
+		}()
+		hooks = append(hooks, server.Hook{Name: "metrics", Run: metricsServer.Shutdown})
 	}
+
+	go func() {
+		<-ctx.Done()
+		stop()
+		// Report NOT_SERVING while draining so no new traffic is routed here
+		healthServer.Shutdown()
+		// Watch streams never finish on their own, so end them for the drain
+		ticketService.Close()
+	}()
+
+	// Readiness follows the store: serving while it accepts writes
+	go server.WatchReadiness(ctx, healthServer, config.HealthCheckInterval, s.Ready, ticket.TicketService_ServiceDesc.ServiceName)
+
+	slog.Info("gRPC server starting", "addr", cfg.Server.ListenAddr, "tls", cfg.TLS.Enabled)
+	if err := server.Serve(ctx, grpcServer, lis, cfg.Server.ShutdownTimeout, hooks...); err != nil {
+		fatal("Failed to serve", err)
+	}
+	slog.Info("server stopped")
+}
+
+// dialInProcess connects to srv over an in-memory listener.
+func dialInProcess(srv *grpc.Server) (*grpc.ClientConn, error) {
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+
+	return grpc.NewClient("passthrough:///inprocess",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+}
+
+// serveHTTP serves handler on addr until ctx is done, then drains it
+// alongside the gRPC server. The returned func waits for the drain. With
+// tlsConfig it serves HTTPS, requiring client certificates as gRPC does.
+func serveHTTP(ctx context.Context, name, addr string, handler http.Handler, tlsConfig *tls.Config, timeout time.Duration) func() error {
+	srv := &http.Server{Addr: addr, Handler: handler, TLSConfig: tlsConfig}
+	go func() {
+		slog.Info(name+" starting", "addr", addr, "tls", tlsConfig != nil)
+		var err error
+		if tlsConfig != nil {
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error(name+" failed", "error", err)
+		}
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		done <- srv.Shutdown(shutdownCtx)
+	}()
+
+	return func() error { return <-done }
 }
 
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/abuse"
+	"github.com/cloudbees/train-ticket-service/internal/config"
+	"github.com/cloudbees/train-ticket-service/internal/ratelimit"
+	"github.com/cloudbees/train-ticket-service/internal/tlsutil"
+)
+
+// rateLimitRules returns the limits for ratelimit.New, rejecting limits for
+// methods the service does not have.
+func rateLimitRules(c config.RateLimitConfig) (ratelimit.Rules, error) {
+	convert := func(r config.RateLimitRule) ratelimit.Rule {
+		return ratelimit.Rule{
+			PerUser: ratelimit.Limit{PerMinute: r.PerUser.PerMinute, Burst: r.PerUser.Burst},
+			PerIP:   ratelimit.Limit{PerMinute: r.PerIP.PerMinute, Burst: r.PerIP.Burst},
+		}
+	}
+
+	rules := ratelimit.Rules{
+		Default: convert(c.Default),
+		Methods: make(map[string]ratelimit.Rule, len(c.Methods)),
+	}
+	for name, rule := range c.Methods {
+		if !isTicketMethod(name) {
+			return ratelimit.Rules{}, fmt.Errorf("rate_limit.methods: unknown RPC %q", name)
+		}
+		rules.Methods[name] = convert(rule)
+	}
+	return rules, nil
+}
+
+func isTicketMethod(name string) bool {
+	for _, m := range ticket.TicketService_ServiceDesc.Methods {
+		if m.MethodName == name {
+			return true
+		}
+	}
+	for _, s := range ticket.TicketService_ServiceDesc.Streams {
+		if s.StreamName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// abuseGuardConfig returns the settings for abuse.NewGuard.
+func abuseGuardConfig(c config.AbuseConfig) abuse.Config {
+	exempt := make([]string, len(c.ExemptDomains))
+	for i, d := range c.ExemptDomains {
+		exempt[i] = strings.ToLower(d)
+	}
+	return abuse.Config{
+		MaxPerEmailDomain: c.MaxTicketsPerEmailDomain,
+		ExemptDomains:     exempt,
+		Window:            c.IPWindow,
+		ChallengeAfter:    c.ChallengeAfter,
+		BlockAfter:        c.BlockAfter,
+		Difficulty:        c.ChallengeDifficulty,
+		ChallengeTTL:      c.ChallengeTTL,
+	}
+}
+
+// serverTLSOptions returns the options for tlsutil.ServerConfig.
+func serverTLSOptions(c config.TLSConfig) tlsutil.ServerOptions {
+	return tlsutil.ServerOptions{
+		CertFile:     c.CertFile,
+		KeyFile:      c.KeyFile,
+		ClientCAFile: c.ClientCAFile,
+		ClientAuth:   c.ClientAuth,
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cloudbees/train-ticket-service/internal/config"
+)
+
+func TestRateLimitRules(t *testing.T) {
+	cfg := config.Default()
+	rules, err := rateLimitRules(cfg.RateLimit)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if rules.Methods["PurchaseTicket"].PerIP.Burst != cfg.RateLimit.Methods["PurchaseTicket"].PerIP.Burst {
+		t.Errorf("Expected the PurchaseTicket limits to carry over, got %+v", rules.Methods["PurchaseTicket"])
+	}
+
+	cfg.RateLimit.Methods["Purchase"] = config.RateLimitRule{}
+	if _, err := rateLimitRules(cfg.RateLimit); err == nil || !strings.Contains(err.Error(), `unknown RPC "Purchase"`) {
+		t.Errorf("Expected error mentioning the unknown RPC, got: %v", err)
+	}
+}
@@ -0,0 +1,284 @@
+package abuse
+
+import (
+	"crypto/rand"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrDomainCapReached  = errors.New("email domain has reached its ticket limit")
+	ErrTooManyEmails     = errors.New("too many purchases for different emails from this address")
+	ErrChallengeRequired = errors.New("proof of work required, request a purchase challenge")
+	ErrChallengeInvalid  = errors.New("purchase challenge is invalid")
+	ErrChallengeExpired  = errors.New("purchase challenge expired")
+	ErrChallengeUsed     = errors.New("purchase challenge was already used")
+	ErrChallengeUnsolved = errors.New("nonce does not solve the purchase challenge")
+)
+
+// Flag reasons and the action taken on the purchase.
+const (
+	ReasonEmailDomainCap = "email_domain_cap"
+	ReasonIPVelocity     = "ip_velocity"
+
+	ActionBlocked    = "blocked"
+	ActionChallenged = "challenged"
+	// ActionSolved marks a purchase that went ahead after a solved challenge.
+	ActionSolved = "solved"
+)
+
+// maxFlags bounds the flagged purchase history kept for admins.
+const maxFlags = 1000
+
+type Config struct {
+	// MaxPerEmailDomain caps live tickets per email domain; 0 disables the cap.
+	MaxPerEmailDomain int
+	// ExemptDomains are shared providers, e.g. gmail.com, that the cap does not apply to.
+	ExemptDomains []string
+	// Window is how long purchases from an IP are remembered.
+	Window time.Duration
+	// ChallengeAfter and BlockAfter count other emails bought for from the
+	// same IP within Window; 0 disables the step.
+	ChallengeAfter int
+	BlockAfter     int
+	Difficulty     int
+	ChallengeTTL   time.Duration
+}
+
+// Flag is a public purchase that tripped an abuse control.
+type Flag struct {
+	Email  string
+	IP     string
+	Reason string
+	Action string
+	At     time.Time
+}
+
+type sighting struct {
+	email string
+	at    time.Time
+}
+
+// Reservation is what a passing Check holds for a purchase: its per-IP and
+// per-domain slots and the challenge it solved. End it with Commit once the
+// purchase is stored, or Release if it failed.
+type Reservation struct {
+	email  string
+	ip     string
+	domain string // set when a domain slot is held
+	token  string // set when a challenge was spent
+}
+
+// Guard applies abuse controls to public purchases. All state is in memory,
+// and challenges are signed with a key generated at startup, so it works
+// offline and needs no third party.
+type Guard struct {
+	mu        sync.Mutex
+	cfg       Config
+	key       []byte
+	now       func() time.Time
+	purchases map[string][]sighting
+	reserved  map[string]int
+	used      map[string]time.Time
+	flags     []Flag
+}
+
+func NewGuard(cfg Config) (*Guard, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return &Guard{
+		cfg:       cfg,
+		key:       key,
+		now:       time.Now,
+		purchases: make(map[string][]sighting),
+		reserved:  make(map[string]int),
+		used:      make(map[string]time.Time),
+	}, nil
+}
+
+// Domain returns the lowercased part of email after the last @.
+func Domain(email string) string {
+	i := strings.LastIndexByte(email, '@')
+	return strings.ToLower(strings.TrimSpace(email[i+1:]))
+}
+
+// IssueChallenge returns a puzzle that lets email purchase from ip once.
+func (g *Guard) IssueChallenge(email, ip string) (Challenge, error) {
+	expiresAt := g.now().Add(g.cfg.ChallengeTTL)
+	token, err := newToken(g.key, email, ip, expiresAt)
+	if err != nil {
+		return Challenge{}, err
+	}
+	return Challenge{Token: token, Difficulty: g.cfg.Difficulty, ExpiresAt: expiresAt}, nil
+}
+
+// Check decides whether email may buy a ticket from ip. domainTickets is how
+// many live tickets the email's domain already holds. token and nonce are the
+// solved challenge, if the client sent one. A purchase that passes holds its
+// IP and domain slots straight away, so concurrent purchases cannot all slip
+// under the limits. Until it is committed a stored purchase counts twice
+// against its domain, which errs on the side of the cap.
+func (g *Guard) Check(email, ip string, domainTickets int, token, nonce string) (*Reservation, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	r := &Reservation{email: strings.ToLower(email), ip: ip}
+	domain := Domain(email)
+	if g.cfg.MaxPerEmailDomain > 0 && !slices.Contains(g.cfg.ExemptDomains, domain) {
+		if domainTickets+g.reserved[domain] >= g.cfg.MaxPerEmailDomain {
+			g.flagLocked(email, ip, ReasonEmailDomainCap, ActionBlocked, now)
+			return nil, ErrDomainCapReached
+		}
+		r.domain = domain
+	}
+
+	if ip != "" {
+		g.pruneLocked(now)
+		others := g.otherEmailsLocked(ip, email)
+		if g.cfg.BlockAfter > 0 && others >= g.cfg.BlockAfter {
+			g.flagLocked(email, ip, ReasonIPVelocity, ActionBlocked, now)
+			return nil, ErrTooManyEmails
+		}
+		if g.cfg.ChallengeAfter > 0 && others >= g.cfg.ChallengeAfter {
+			if token == "" {
+				g.flagLocked(email, ip, ReasonIPVelocity, ActionChallenged, now)
+				return nil, ErrChallengeRequired
+			}
+			if err := g.verifyLocked(email, ip, token, nonce, now); err != nil {
+				return nil, err
+			}
+			r.token = token
+			g.flagLocked(email, ip, ReasonIPVelocity, ActionSolved, now)
+		}
+		g.purchases[ip] = append(g.purchases[ip], sighting{email: r.email, at: now})
+	}
+
+	if r.domain != "" {
+		g.reserved[r.domain]++
+	}
+	return r, nil
+}
+
+func (g *Guard) verifyLocked(email, ip, token, nonce string, now time.Time) error {
+	expiresAt, err := parseToken(g.key, token, email, ip)
+	if err != nil {
+		return err
+	}
+	if !now.Before(expiresAt) {
+		return ErrChallengeExpired
+	}
+
+	for t, exp := range g.used {
+		if !now.Before(exp) {
+			delete(g.used, t)
+		}
+	}
+	if _, ok := g.used[token]; ok {
+		return ErrChallengeUsed
+	}
+	if !Solved(token, nonce, g.cfg.Difficulty) {
+		return ErrChallengeUnsolved
+	}
+
+	g.used[token] = expiresAt
+	return nil
+}
+
+// Commit ends r for a purchase that is now stored, and so counted by the
+// caller's domainTickets from here on. The purchase keeps counting against its
+// IP and its challenge stays spent.
+func (g *Guard) Commit(r *Reservation) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.unreserveLocked(r)
+}
+
+// Release ends r for a purchase that failed after passing its checks. The
+// purchase no longer counts against its IP or domain, and the challenge it
+// solved can be used again.
+func (g *Guard) Release(r *Reservation) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.unreserveLocked(r)
+	if r.token != "" {
+		delete(g.used, r.token)
+	}
+	if r.ip == "" {
+		return
+	}
+
+	sightings := g.purchases[r.ip]
+	for i := len(sightings) - 1; i >= 0; i-- {
+		if sightings[i].email == r.email {
+			sightings = slices.Delete(sightings, i, i+1)
+			break
+		}
+	}
+	if len(sightings) == 0 {
+		delete(g.purchases, r.ip)
+		return
+	}
+	g.purchases[r.ip] = sightings
+}
+
+func (g *Guard) unreserveLocked(r *Reservation) {
+	if r.domain == "" {
+		return
+	}
+	if g.reserved[r.domain]--; g.reserved[r.domain] <= 0 {
+		delete(g.reserved, r.domain)
+	}
+}
+
+// pruneLocked forgets purchases older than the window, and addresses left
+// with none.
+func (g *Guard) pruneLocked(now time.Time) {
+	cutoff := now.Add(-g.cfg.Window)
+	for ip, sightings := range g.purchases {
+		recent := slices.DeleteFunc(sightings, func(s sighting) bool { return s.at.Before(cutoff) })
+		if len(recent) == 0 {
+			delete(g.purchases, ip)
+			continue
+		}
+		g.purchases[ip] = recent
+	}
+}
+
+// otherEmailsLocked counts distinct emails other than email bought for from
+// ip within the window.
+func (g *Guard) otherEmailsLocked(ip, email string) int {
+	seen := map[string]bool{strings.ToLower(email): true}
+	others := 0
+	for _, s := range g.purchases[ip] {
+		if !seen[s.email] {
+			seen[s.email] = true
+			others++
+		}
+	}
+	return others
+}
+
+func (g *Guard) flagLocked(email, ip, reason, action string, now time.Time) {
+	if len(g.flags) == maxFlags {
+		g.flags = g.flags[1:]
+	}
+	g.flags = append(g.flags, Flag{Email: email, IP: ip, Reason: reason, Action: action, At: now})
+}
+
+// Flags returns the flagged purchases, newest first.
+func (g *Guard) Flags() []Flag {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	flags := slices.Clone(g.flags)
+	slices.Reverse(flags)
+	return flags
+}
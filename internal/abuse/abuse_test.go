@@ -0,0 +1,263 @@
+package abuse
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// testConfig caps every domain but gmail.com at 2 tickets. Tests of the IP
+// controls turn the cap off, as they never commit their purchases.
+func testConfig() Config {
+	return Config{
+		MaxPerEmailDomain: 2,
+		ExemptDomains:     []string{"gmail.com"},
+		Window:            time.Hour,
+		ChallengeAfter:    2,
+		BlockAfter:        4,
+		Difficulty:        8,
+		ChallengeTTL:      time.Minute,
+	}
+}
+
+func newTestGuard(t *testing.T) *Guard {
+	t.Helper()
+	g, err := NewGuard(testConfig())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	return g
+}
+
+func TestDomainCap(t *testing.T) {
+	g := newTestGuard(t)
+
+	if _, err := g.Check("c@corp.example", "", 1, "", ""); err != nil {
+		t.Errorf("Expected no error below the cap, got: %v", err)
+	}
+	if _, err := g.Check("c@CORP.example", "", 2, "", ""); err != ErrDomainCapReached {
+		t.Errorf("Expected ErrDomainCapReached, got: %v", err)
+	}
+	if _, err := g.Check("c@gmail.com", "", 50, "", ""); err != nil {
+		t.Errorf("Expected exempt domain to pass, got: %v", err)
+	}
+
+	flags := g.Flags()
+	if len(flags) != 1 || flags[0].Reason != ReasonEmailDomainCap || flags[0].Action != ActionBlocked {
+		t.Errorf("Expected one blocked domain cap flag, got %+v", flags)
+	}
+}
+
+func TestIPVelocity(t *testing.T) {
+	now := time.Now()
+	g := newTestGuard(t)
+	g.cfg.MaxPerEmailDomain = 0
+	g.now = func() time.Time { return now }
+
+	const ip = "203.0.113.7"
+	for i := 0; i < 2; i++ {
+		email := fmt.Sprintf("user%d@example.com", i)
+		if _, err := g.Check(email, ip, 0, "", ""); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	// Buying again for an email already seen does not count against it
+	if _, err := g.Check("user0@example.com", ip, 0, "", ""); err != nil {
+		t.Errorf("Expected repeat email to pass, got: %v", err)
+	}
+	if _, err := g.Check("user2@example.com", ip, 0, "", ""); err != ErrChallengeRequired {
+		t.Fatalf("Expected ErrChallengeRequired, got: %v", err)
+	}
+	if _, err := g.Check("user2@example.com", "198.51.100.1", 0, "", ""); err != nil {
+		t.Errorf("Expected another IP to pass, got: %v", err)
+	}
+
+	c, err := g.IssueChallenge("user2@example.com", ip)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	nonce := Solve(c.Token, c.Difficulty)
+	if _, err := g.Check("user2@example.com", ip, 0, c.Token, nonce); err != nil {
+		t.Fatalf("Expected solved challenge to pass, got: %v", err)
+	}
+	c, _ = g.IssueChallenge("user3@example.com", ip)
+	nonce = Solve(c.Token, c.Difficulty)
+	if _, err := g.Check("user3@example.com", ip, 0, c.Token, nonce); err != nil {
+		t.Fatalf("Expected solved challenge to pass, got: %v", err)
+	}
+
+	if _, err := g.Check("user4@example.com", ip, 0, c.Token, nonce); err != ErrTooManyEmails {
+		t.Errorf("Expected ErrTooManyEmails, got: %v", err)
+	}
+
+	// The window slides past the earlier purchases
+	now = now.Add(2 * time.Hour)
+	if _, err := g.Check("user4@example.com", ip, 0, "", ""); err != nil {
+		t.Errorf("Expected no error after the window, got: %v", err)
+	}
+
+	flags := g.Flags()
+	want := []string{ActionBlocked, ActionSolved, ActionSolved, ActionChallenged}
+	if len(flags) != len(want) {
+		t.Fatalf("Expected %d flags, got %+v", len(want), flags)
+	}
+	for i, action := range want {
+		if flags[i].Action != action || flags[i].IP != ip {
+			t.Errorf("Expected flag %d to be %s from %s, got %+v", i, action, ip, flags[i])
+		}
+	}
+}
+
+func TestRelease(t *testing.T) {
+	now := time.Now()
+	g := newTestGuard(t)
+	g.cfg.MaxPerEmailDomain = 0
+	g.now = func() time.Time { return now }
+
+	const ip = "203.0.113.7"
+	var reservations []*Reservation
+	for _, email := range []string{"a@example.com", "B@example.com"} {
+		r, err := g.Check(email, ip, 0, "", "")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		reservations = append(reservations, r)
+	}
+
+	// A failed purchase does not count towards the challenge threshold
+	g.Release(reservations[1])
+	c, err := g.Check("c@example.com", ip, 0, "", "")
+	if err != nil {
+		t.Errorf("Expected released purchase not to count, got: %v", err)
+	}
+
+	g.Release(reservations[0])
+	g.Release(c)
+	if _, ok := g.purchases[ip]; ok {
+		t.Error("Expected an address with no purchases to be forgotten")
+	}
+}
+
+func TestDomainCap_Reserved(t *testing.T) {
+	g := newTestGuard(t)
+
+	// Two purchases in flight fill the cap before either is stored
+	first, err := g.Check("a@corp.example", "", 0, "", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := g.Check("b@corp.example", "", 0, "", ""); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := g.Check("c@corp.example", "", 0, "", ""); err != ErrDomainCapReached {
+		t.Errorf("Expected ErrDomainCapReached while purchases are in flight, got: %v", err)
+	}
+
+	// A failed purchase gives its slot back; a stored one is counted by the caller
+	g.Release(first)
+	r, err := g.Check("c@corp.example", "", 0, "", "")
+	if err != nil {
+		t.Fatalf("Expected the released slot to be free, got: %v", err)
+	}
+	g.Commit(r)
+	if _, err := g.Check("d@corp.example", "", 1, "", ""); err != ErrDomainCapReached {
+		t.Errorf("Expected ErrDomainCapReached, got: %v", err)
+	}
+}
+
+func TestChallenge_ReleasedOnFailure(t *testing.T) {
+	g := newTestGuard(t)
+	g.cfg.MaxPerEmailDomain = 0
+
+	const ip = "203.0.113.7"
+	g.Check("a@example.com", ip, 0, "", "")
+	g.Check("b@example.com", ip, 0, "", "")
+
+	c, _ := g.IssueChallenge("jane@example.com", ip)
+	nonce := Solve(c.Token, c.Difficulty)
+	r, err := g.Check("jane@example.com", ip, 0, c.Token, nonce)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Spent while the purchase is in flight, given back when it fails
+	if _, err := g.Check("jane@example.com", ip, 0, c.Token, nonce); err != ErrChallengeUsed {
+		t.Errorf("Expected ErrChallengeUsed, got: %v", err)
+	}
+	g.Release(r)
+	r, err = g.Check("jane@example.com", ip, 0, c.Token, nonce)
+	if err != nil {
+		t.Fatalf("Expected the challenge to be usable after a failed purchase, got: %v", err)
+	}
+
+	g.Commit(r)
+	if _, err := g.Check("jane@example.com", ip, 0, c.Token, nonce); err != ErrChallengeUsed {
+		t.Errorf("Expected ErrChallengeUsed after a successful purchase, got: %v", err)
+	}
+}
+
+func TestStaleAddressesForgotten(t *testing.T) {
+	now := time.Now()
+	g := newTestGuard(t)
+	g.cfg.MaxPerEmailDomain = 0
+	g.now = func() time.Time { return now }
+
+	g.Check("a@example.com", "203.0.113.7", 0, "", "")
+	now = now.Add(2 * time.Hour)
+	g.Check("b@example.com", "198.51.100.1", 0, "", "")
+
+	if len(g.purchases) != 1 {
+		t.Errorf("Expected purchases outside the window to be dropped, got %d addresses", len(g.purchases))
+	}
+}
+
+func TestChallenge(t *testing.T) {
+	now := time.Now()
+	g := newTestGuard(t)
+	g.cfg.MaxPerEmailDomain = 0
+	g.now = func() time.Time { return now }
+
+	const ip = "203.0.113.7"
+	g.Check("a@example.com", ip, 0, "", "")
+	g.Check("b@example.com", ip, 0, "", "")
+
+	c, err := g.IssueChallenge("jane@example.com", ip)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	nonce := Solve(c.Token, c.Difficulty)
+	if !Solved(c.Token, nonce, c.Difficulty) {
+		t.Fatalf("Expected Solve to find a valid nonce")
+	}
+
+	tests := []struct {
+		name  string
+		email string
+		token string
+		nonce string
+		want  error
+	}{
+		{"other email", "john@example.com", c.Token, nonce, ErrChallengeInvalid},
+		{"tampered token", "jane@example.com", c.Token + "x", nonce, ErrChallengeInvalid},
+		{"wrong nonce", "jane@example.com", c.Token, "not-a-solution", ErrChallengeUnsolved},
+		{"solved", "jane@example.com", c.Token, nonce, nil},
+		{"reused", "jane@example.com", c.Token, nonce, ErrChallengeUsed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.name == "wrong nonce" && Solved(tt.token, tt.nonce, c.Difficulty) {
+				t.Skip("nonce happens to solve the challenge")
+			}
+			if _, err := g.Check(tt.email, ip, 0, tt.token, tt.nonce); err != tt.want {
+				t.Errorf("Expected %v, got: %v", tt.want, err)
+			}
+		})
+	}
+
+	expired, _ := g.IssueChallenge("jane@example.com", ip)
+	now = now.Add(2 * time.Minute)
+	if _, err := g.Check("jane@example.com", ip, 0, expired.Token, Solve(expired.Token, expired.Difficulty)); err != ErrChallengeExpired {
+		t.Errorf("Expected ErrChallengeExpired, got: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package abuse
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Challenge is a proof-of-work puzzle bound to one email and client IP. The
+// client must find a nonce such that sha256(token + ":" + nonce) starts with
+// Difficulty zero bits, then send both with the purchase.
+type Challenge struct {
+	Token      string
+	Difficulty int
+	ExpiresAt  time.Time
+}
+
+// Solve finds a nonce for token. Each extra bit of difficulty doubles the
+// expected work; 20 bits takes well under a second.
+func Solve(token string, difficulty int) string {
+	for n := uint64(0); ; n++ {
+		nonce := strconv.FormatUint(n, 10)
+		if Solved(token, nonce, difficulty) {
+			return nonce
+		}
+	}
+}
+
+// Solved reports whether nonce solves token at difficulty.
+func Solved(token, nonce string, difficulty int) bool {
+	sum := sha256.Sum256([]byte(token + ":" + nonce))
+	zeros := 0
+	for i := 0; i < len(sum); i += 8 {
+		word := binary.BigEndian.Uint64(sum[i:])
+		zeros += bits.LeadingZeros64(word)
+		if word != 0 {
+			break
+		}
+	}
+	return zeros >= difficulty
+}
+
+// newToken returns "<expiry>.<random>.<mac>", where the MAC binds the token
+// to email and ip so it cannot be reused for anyone else.
+func newToken(key []byte, email, ip string, expiresAt time.Time) (string, error) {
+	random := make([]byte, 12)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	payload := strconv.FormatInt(expiresAt.Unix(), 10) + "." + base64.RawURLEncoding.EncodeToString(random)
+	return payload + "." + tokenMAC(key, payload, email, ip), nil
+}
+
+// parseToken checks the MAC and returns the token's expiry.
+func parseToken(key []byte, token, email, ip string) (time.Time, error) {
+	i := strings.LastIndexByte(token, '.')
+	if i < 0 {
+		return time.Time{}, ErrChallengeInvalid
+	}
+	payload, mac := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(mac), []byte(tokenMAC(key, payload, email, ip))) {
+		return time.Time{}, ErrChallengeInvalid
+	}
+
+	exp, _, _ := strings.Cut(payload, ".")
+	unix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrChallengeInvalid
+	}
+	return time.Unix(unix, 0), nil
+}
+
+func tokenMAC(key []byte, payload, email, ip string) string {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(payload + "|" + strings.ToLower(email) + "|" + ip))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
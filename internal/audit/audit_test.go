@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/cloudbees/train-ticket-service/internal/logging"
+)
+
+func TestLogRecord(t *testing.T) {
+	var buf bytes.Buffer
+	redactor, _ := logging.NewRedactor(logging.RedactionRedact)
+	l := NewLog(slog.New(slog.NewJSONHandler(&buf, nil)), redactor, &buf)
+
+	l.Record(Entry{
+		Actor:     "support@example.com",
+		ActorRole: "support",
+		Subject:   "jane@example.com",
+		Method:    "/ticket.TicketService/ViewUserReceipt",
+		Action:    "impersonate",
+		Allowed:   true,
+	})
+
+	entries := l.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+
+	if entries[0].Time.IsZero() {
+		t.Error("Expected entry time to be set")
+	}
+	if entries[0].Subject != "jane@example.com" {
+		t.Errorf("Expected the stored entry to keep the email, got %q", entries[0].Subject)
+	}
+
+	logged := buf.String()
+	if !strings.Contains(logged, `"action":"impersonate"`) || !strings.Contains(logged, `"subject":"[REDACTED]"`) {
+		t.Errorf("Expected entry to be logged with the subject redacted, got %q", logged)
+	}
+	if strings.Contains(logged, "@example.com") {
+		t.Errorf("Expected no emails in the log, got %q", logged)
+	}
+	if err := l.Flush(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
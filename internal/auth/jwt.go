@@ -1,62 +1,183 @@
 package auth
 
 import (
+	"bytes"
 	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
+	"fmt"
+	"os"
+	"slices"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
 	"google.golang.org/grpc/metadata"
 )
 
+const (
+	RoleUser    = "user"
+	RoleAdmin   = "admin"
+	RoleSupport = "support"
+)
+
+// ImpersonationHeader names the metadata key a privileged caller sets to act as another user.
+const ImpersonationHeader = "x-impersonate-user"
+
 var (
-	ErrNoMetadata       = errors.New("no metadata provided")
-	ErrNoAuthHeader     = errors.New("no authorization header")
-	ErrInvalidToken     = errors.New("invalid token")
+	ErrNoMetadata         = errors.New("no metadata provided")
+	ErrNoAuthHeader       = errors.New("no authorization header")
+	ErrInvalidToken       = errors.New("invalid token")
 	ErrInvalidTokenFormat = errors.New("invalid token format")
+	ErrNoVerifier         = errors.New("bearer tokens are not accepted: no verification key is configured")
 )
 
+// Algorithms are the JWT signing algorithms a Verifier can accept.
+var Algorithms = []string{"HS256", "HS384", "HS512", "RS256", "RS384", "RS512", "PS256", "ES256", "ES384", "EdDSA"}
+
+// minSecretLen is the shortest HMAC secret LoadVerifier accepts, the size of a SHA-256 key.
+const minSecretLen = 32
+
 type UserClaims struct {
 	Email     string
 	FirstName string
 	LastName  string
-	Role      string // "admin" or "user"
+	Role      string // "admin", "support" or "user"
+
+	// ImpersonatedBy is the real caller when these claims were produced by impersonation.
+	ImpersonatedBy *UserClaims
 }
 
 func (u *UserClaims) IsAdmin() bool {
-	return u.Role == "admin"
+	return u.Role == RoleAdmin
 }
 
-func ExtractUserFromContext(ctx context.Context) (*UserClaims, error) {
+func (u *UserClaims) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		if u.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Impersonate returns claims for the user with the given email, acting on behalf of u.
+func (u *UserClaims) Impersonate(email string) *UserClaims {
+	return &UserClaims{
+		Email:          email,
+		Role:           RoleUser,
+		ImpersonatedBy: u,
+	}
+}
+
+// Verifier checks the signature of a caller's JWT before any of its claims
+// are trusted. The key type must suit the algorithms: a []byte secret for
+// HS*, or a public key for the others.
+type Verifier struct {
+	key    any
+	parser *jwt.Parser
+}
+
+func NewVerifier(key any, algorithms []string) *Verifier {
+	return &Verifier{
+		key:    key,
+		parser: jwt.NewParser(jwt.WithValidMethods(algorithms)),
+	}
+}
+
+// LoadVerifier reads the key tokens are verified with from path: a PEM
+// PUBLIC KEY (RSA, ECDSA or Ed25519), or otherwise an HMAC secret of at
+// least 32 bytes.
+func LoadVerifier(path string, algorithms []string) (*Verifier, error) {
+	if len(algorithms) == 0 {
+		return nil, errors.New("at least one JWT algorithm is required")
+	}
+	for _, alg := range algorithms {
+		if !slices.Contains(Algorithms, alg) {
+			return nil, fmt.Errorf("unsupported JWT algorithm %q", alg)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if block, _ := pem.Decode(data); block != nil {
+		if block.Type != "PUBLIC KEY" {
+			return nil, fmt.Errorf("%s: expected a PEM PUBLIC KEY block, got %s", path, block.Type)
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return NewVerifier(key, algorithms), nil
+	}
+
+	secret := bytes.TrimSpace(data)
+	if len(secret) < minSecretLen {
+		return nil, fmt.Errorf("%s: HMAC secret must be at least %d bytes", path, minSecretLen)
+	}
+	return NewVerifier(secret, algorithms), nil
+}
+
+// ExtractUser returns the claims of the JWT in ctx once its signature checks
+// out. A nil Verifier accepts no tokens, but still reports ErrNoMetadata and
+// ErrNoAuthHeader so callers can tell anonymous calls apart.
+func (v *Verifier) ExtractUser(ctx context.Context) (*UserClaims, error) {
+	tokenString, err := bearerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, ErrNoVerifier
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(tokenString, claims, func(*jwt.Token) (any, error) {
+		return v.key, nil
+	}); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	return userClaims(claims)
+}
+
+func bearerToken(ctx context.Context) (string, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return nil, ErrNoMetadata
+		return "", ErrNoMetadata
 	}
 
 	authHeaders := md.Get("authorization")
 	if len(authHeaders) == 0 {
-		return nil, ErrNoAuthHeader
+		return "", ErrNoAuthHeader
 	}
 
-	authHeader := authHeaders[0]
-
-	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	tokenString := strings.TrimPrefix(authHeaders[0], "Bearer ")
 	tokenString = strings.TrimSpace(tokenString)
-
 	if tokenString == "" {
-		return nil, ErrInvalidTokenFormat
+		return "", ErrInvalidTokenFormat
 	}
+	return tokenString, nil
+}
 
-	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
-	if err != nil {
-		return nil, ErrInvalidToken
+// ImpersonatedEmail returns the email requested via ImpersonationHeader, or "" if none.
+func ImpersonatedEmail(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return nil, ErrInvalidToken
+	values := md.Get(ImpersonationHeader)
+	if len(values) == 0 {
+		return ""
 	}
 
+	return strings.TrimSpace(values[0])
+}
+
+func userClaims(claims jwt.MapClaims) (*UserClaims, error) {
 	userClaims := &UserClaims{}
 
 	if email, ok := claims["email"].(string); ok {
@@ -74,7 +195,7 @@ func ExtractUserFromContext(ctx context.Context) (*UserClaims, error) {
 	if role, ok := claims["role"].(string); ok {
 		userClaims.Role = role
 	} else {
-		userClaims.Role = "user"
+		userClaims.Role = RoleUser
 	}
 
 	if userClaims.Email == "" {
@@ -83,4 +204,3 @@ func ExtractUserFromContext(ctx context.Context) (*UserClaims, error) {
 
 	return userClaims, nil
 }
-
@@ -2,16 +2,22 @@ package auth
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/golang-jwt/jwt/v5"
 	"google.golang.org/grpc/metadata"
 )
 
-func TestExtractUserFromContext(t *testing.T) {
-	// Create a simple JWT token (header.payload.signature format)
-	// For testing, we'll create a minimal valid JWT structure
-	// Note: ParseUnverified doesn't validate signature, so we just need valid structure
+var testVerifier = NewVerifier([]byte("test-secret"), []string{"HS256"})
+
+func TestExtractUser(t *testing.T) {
 	claims := jwt.MapClaims{
 		"email":      "test@example.com",
 		"first_name": "John",
@@ -20,7 +26,6 @@ func TestExtractUserFromContext(t *testing.T) {
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	// For testing, we'll use a dummy secret to sign
 	tokenString, err := token.SignedString([]byte("test-secret"))
 	if err != nil {
 		t.Fatalf("Failed to create token: %v", err)
@@ -33,7 +38,7 @@ func TestExtractUserFromContext(t *testing.T) {
 	ctx := metadata.NewIncomingContext(context.Background(), md)
 
 	// Test extraction
-	userClaims, err := ExtractUserFromContext(ctx)
+	userClaims, err := testVerifier.ExtractUser(ctx)
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
@@ -51,7 +56,7 @@ func TestExtractUserFromContext(t *testing.T) {
 	}
 }
 
-func TestExtractUserFromContext_Admin(t *testing.T) {
+func TestExtractUser_Admin(t *testing.T) {
 	claims := jwt.MapClaims{
 		"email":      "admin@example.com",
 		"first_name": "Admin",
@@ -70,7 +75,7 @@ func TestExtractUserFromContext_Admin(t *testing.T) {
 	})
 	ctx := metadata.NewIncomingContext(context.Background(), md)
 
-	userClaims, err := ExtractUserFromContext(ctx)
+	userClaims, err := testVerifier.ExtractUser(ctx)
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
@@ -80,26 +85,26 @@ func TestExtractUserFromContext_Admin(t *testing.T) {
 	}
 }
 
-func TestExtractUserFromContext_NoMetadata(t *testing.T) {
+func TestExtractUser_NoMetadata(t *testing.T) {
 	ctx := context.Background()
 
-	_, err := ExtractUserFromContext(ctx)
+	_, err := testVerifier.ExtractUser(ctx)
 	if err != ErrNoMetadata {
 		t.Errorf("Expected ErrNoMetadata, got: %v", err)
 	}
 }
 
-func TestExtractUserFromContext_NoAuthHeader(t *testing.T) {
+func TestExtractUser_NoAuthHeader(t *testing.T) {
 	md := metadata.New(map[string]string{})
 	ctx := metadata.NewIncomingContext(context.Background(), md)
 
-	_, err := ExtractUserFromContext(ctx)
+	_, err := testVerifier.ExtractUser(ctx)
 	if err != ErrNoAuthHeader {
 		t.Errorf("Expected ErrNoAuthHeader, got: %v", err)
 	}
 }
 
-func TestExtractUserFromContext_DefaultRole(t *testing.T) {
+func TestExtractUser_DefaultRole(t *testing.T) {
 	claims := jwt.MapClaims{
 		"email": "test@example.com",
 		// No role specified
@@ -116,7 +121,7 @@ func TestExtractUserFromContext_DefaultRole(t *testing.T) {
 	})
 	ctx := metadata.NewIncomingContext(context.Background(), md)
 
-	userClaims, err := ExtractUserFromContext(ctx)
+	userClaims, err := testVerifier.ExtractUser(ctx)
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
@@ -126,3 +131,127 @@ func TestExtractUserFromContext_DefaultRole(t *testing.T) {
 	}
 }
 
+func TestImpersonatedEmail(t *testing.T) {
+	md := metadata.New(map[string]string{
+		ImpersonationHeader: " jane@example.com ",
+	})
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	if got := ImpersonatedEmail(ctx); got != "jane@example.com" {
+		t.Errorf("Expected jane@example.com, got %q", got)
+	}
+
+	if got := ImpersonatedEmail(context.Background()); got != "" {
+		t.Errorf("Expected empty email without metadata, got %q", got)
+	}
+}
+
+func TestImpersonate(t *testing.T) {
+	support := &UserClaims{Email: "support@example.com", Role: RoleSupport}
+
+	if !support.HasRole(RoleAdmin, RoleSupport) {
+		t.Error("Expected support to match role list")
+	}
+
+	target := support.Impersonate("jane@example.com")
+	if target.Email != "jane@example.com" || target.Role != RoleUser {
+		t.Errorf("Unexpected impersonated claims: %+v", target)
+	}
+
+	if target.ImpersonatedBy != support {
+		t.Error("Expected impersonated claims to reference the real caller")
+	}
+}
+
+func bearerContext(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestVerifier_ExtractUser(t *testing.T) {
+	v := NewVerifier([]byte("test-secret"), []string{"HS256"})
+	claims := jwt.MapClaims{"email": "admin@example.com", "role": RoleAdmin}
+
+	signed, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+	user, err := v.ExtractUser(bearerContext(signed))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if user.Email != "admin@example.com" || !user.IsAdmin() {
+		t.Errorf("Expected admin claims, got %+v", user)
+	}
+
+	forged, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("attacker-secret"))
+	unsigned, _ := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	otherAlg, _ := jwt.NewWithClaims(jwt.SigningMethodHS512, claims).SignedString([]byte("test-secret"))
+	expired, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"email": "a@example.com", "exp": 1}).SignedString([]byte("test-secret"))
+
+	tests := map[string]string{
+		"forged signature":     forged,
+		"unsigned":             unsigned,
+		"algorithm not listed": otherAlg,
+		"expired":              expired,
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := v.ExtractUser(bearerContext(token)); err != ErrInvalidToken {
+				t.Errorf("Expected ErrInvalidToken, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifier_Nil(t *testing.T) {
+	var v *Verifier
+	signed, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"email": "a@example.com"}).SignedString([]byte("test-secret"))
+
+	if _, err := v.ExtractUser(bearerContext(signed)); err != ErrNoVerifier {
+		t.Errorf("Expected ErrNoVerifier, got: %v", err)
+	}
+	if _, err := v.ExtractUser(context.Background()); err != ErrNoMetadata {
+		t.Errorf("Expected ErrNoMetadata, got: %v", err)
+	}
+}
+
+func TestLoadVerifier(t *testing.T) {
+	dir := t.TempDir()
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ := x509.MarshalPKIXPublicKey(pub)
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	keyFile := filepath.Join(dir, "jwt.pem")
+	os.WriteFile(keyFile, pemData, 0o600)
+
+	v, err := LoadVerifier(keyFile, []string{"HS256", "EdDSA"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	claims := jwt.MapClaims{"email": "a@example.com"}
+	signed, _ := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims).SignedString(priv)
+	if _, err := v.ExtractUser(bearerContext(signed)); err != nil {
+		t.Errorf("Expected EdDSA token to verify, got: %v", err)
+	}
+
+	// The public key is no HMAC secret, even with HS256 allowed
+	confused, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(pemData)
+	if _, err := v.ExtractUser(bearerContext(confused)); err != ErrInvalidToken {
+		t.Errorf("Expected ErrInvalidToken, got: %v", err)
+	}
+
+	secretFile := filepath.Join(dir, "secret")
+	os.WriteFile(secretFile, []byte("too-short\n"), 0o600)
+	if _, err := LoadVerifier(secretFile, []string{"HS256"}); err == nil || !strings.Contains(err.Error(), "at least 32 bytes") {
+		t.Errorf("Expected short secret to be refused, got: %v", err)
+	}
+	if _, err := LoadVerifier(keyFile, []string{"none"}); err == nil {
+		t.Error("Expected algorithm none to be refused")
+	}
+
+	os.WriteFile(secretFile, []byte(strings.Repeat("s", 32)+"\n"), 0o600)
+	v, err = LoadVerifier(secretFile, []string{"HS256"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	signed, _ = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(strings.Repeat("s", 32)))
+	if _, err := v.ExtractUser(bearerContext(signed)); err != nil {
+		t.Errorf("Expected HS256 token to verify, got: %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// ClientSubjectHeader carries the subject of a client certificate the HTTP
+// gateway verified, as its in-process calls have no TLS peer of their own.
+const ClientSubjectHeader = "x-client-cert-subject"
+
+type clientSubjectKey struct{}
+
+// ServiceIdentity is who a caller authenticated by client certificate acts as.
+type ServiceIdentity struct {
+	Email string
+	Role  string
+}
+
+// TrustedSubjectUnaryInterceptor takes ClientSubjectHeader as the subject of
+// the caller's verified client certificate. Only install it on a server that
+// the gateway alone can reach; everywhere else the header is ignored.
+func TrustedSubjectUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withTrustedSubject(ctx), req)
+	}
+}
+
+// TrustedSubjectStreamInterceptor is the streaming counterpart of
+// TrustedSubjectUnaryInterceptor.
+func TrustedSubjectStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &subjectStream{ServerStream: ss, ctx: withTrustedSubject(ss.Context())})
+	}
+}
+
+func withTrustedSubject(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	values := md.Get(ClientSubjectHeader)
+	if len(values) == 0 || values[0] == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, clientSubjectKey{}, values[0])
+}
+
+type subjectStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *subjectStream) Context() context.Context {
+	return s.ctx
+}
+
+// ExtractServiceIdentity maps the subject of the caller's verified client
+// certificate to a service identity. Unverified certificates are ignored.
+func ExtractServiceIdentity(ctx context.Context, identities map[string]ServiceIdentity) (*UserClaims, bool) {
+	if len(identities) == 0 {
+		return nil, false
+	}
+
+	subject, ok := verifiedSubject(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	id, ok := identities[subject]
+	if !ok {
+		return nil, false
+	}
+
+	role := id.Role
+	if role == "" {
+		role = RoleUser
+	}
+
+	return &UserClaims{Email: id.Email, Role: role}, true
+}
+
+// verifiedSubject returns the subject of the peer's verified client
+// certificate, or the one a trusted gateway passed on.
+func verifiedSubject(ctx context.Context) (string, bool) {
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.VerifiedChains) > 0 && len(tlsInfo.State.VerifiedChains[0]) > 0 {
+			return tlsInfo.State.VerifiedChains[0][0].Subject.String(), true
+		}
+	}
+
+	subject, ok := ctx.Value(clientSubjectKey{}).(string)
+	return subject, ok
+}
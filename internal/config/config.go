@@ -1,10 +1,74 @@
 package config
 
+import (
+	"time"
+
+	"github.com/cloudbees/train-ticket-service/internal/auth"
+)
+
+// Defaults used when no configuration file, environment variable or flag
+// overrides them. See Default.
 const (
-	RouteFrom = "London"
-	RouteTo = "France"
+	RouteFrom        = "London"
+	RouteTo          = "France"
 	TicketPriceCents = 2000
-	SeatsPerSection = 10
-	TotalSections = 2
+	SeatsPerSection  = 10
+	SeatsPerRow      = 4
+	TotalSections    = 2
+
+	ListenAddr          = ":50051"
+	ShutdownTimeout     = 30 * time.Second
+	HealthCheckInterval = 5 * time.Second
+	MetricsListenAddr   = ":9090"
+	LogLevel            = "info"
+	StoreBackend        = StoreBackendMemory
+)
+
+// Purchase authentication modes for the public PurchaseTicket API.
+const (
+	// PurchaseAuthRequired rejects purchases without a JWT whose email matches the request.
+	PurchaseAuthRequired = "required"
+	// PurchaseAuthAnonymous also accepts purchases without a JWT, holding the seat until the email is verified.
+	PurchaseAuthAnonymous = "anonymous"
+)
+
+const StoreBackendMemory = "memory"
+
+// Client certificate policies for tls.client_auth, as tlsutil understands them.
+const (
+	ClientAuthNone     = "none"
+	ClientAuthOptional = "optional"
+	ClientAuthRequire  = "require"
+)
+
+// RateLimitStoreMemory keeps rate limit buckets in process memory.
+const RateLimitStoreMemory = "memory"
+
+// Span exporters for tracing.exporter.
+const (
+	TraceExporterNone   = "none"
+	TraceExporterStdout = "stdout"
+)
+
+// Redaction modes for logging.redaction.
+const (
+	RedactionNone   = "none"
+	RedactionRedact = "redact"
+	RedactionHash   = "hash"
 )
 
+const (
+	MaxBulkOperations = 500
+
+	DefaultPurchaseAuthMode = PurchaseAuthAnonymous
+	VerificationHoldTTL     = 15 * time.Minute
+)
+
+// ExemptEmailDomains are shared mail providers that the per-domain purchase cap skips.
+var ExemptEmailDomains = []string{"gmail.com", "outlook.com", "hotmail.com", "yahoo.com", "icloud.com", "proton.me"}
+
+// ImpersonationRoles lists the roles allowed to act as another user via the impersonation header.
+var ImpersonationRoles = []string{auth.RoleAdmin, auth.RoleSupport}
+
+// JWTAlgorithms are the signing algorithms accepted on caller JWTs; the key type narrows them further.
+var JWTAlgorithms = []string{"HS256", "RS256", "ES256", "EdDSA"}
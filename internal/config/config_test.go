@@ -0,0 +1,205 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func noEnv(string) (string, bool) { return "", false }
+
+func TestDefaultIsValid(t *testing.T) {
+	c := Default()
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Expected default config to be valid, got: %v", err)
+	}
+
+	if c.Pricing.TicketPriceCents != TicketPriceCents || c.Route.From != RouteFrom {
+		t.Errorf("Expected defaults from constants, got %+v", c)
+	}
+
+	layout := c.SeatLayout()
+	if len(layout.Sections) != TotalSections || layout.Sections[1] != "B" {
+		t.Errorf("Expected default sections A and B, got %v", layout.Sections)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"config.yaml", `
+server:
+  listen_addr: ":6000"
+auth:
+  purchase_mode: required
+  verification_hold_ttl: 5m
+  jwt_key_file: jwt.pem
+layout:
+  sections: [A, B, C]
+  seats_per_section: 4
+pricing:
+  ticket_price_cents: 2500
+`},
+		{"config.toml", `
+[server]
+listen_addr = ":6000"
+
+[auth]
+purchase_mode = "required"
+verification_hold_ttl = "5m"
+jwt_key_file = "jwt.pem"
+
+[layout]
+sections = ["A", "B", "C"]
+seats_per_section = 4
+
+[pricing]
+ticket_price_cents = 2500
+`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Load(writeFile(t, tt.name, tt.content), noEnv, nil)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if c.Server.ListenAddr != ":6000" || c.Auth.PurchaseMode != PurchaseAuthRequired {
+				t.Errorf("Expected file settings to apply, got %+v", c)
+			}
+
+			if c.Auth.VerificationHoldTTL != 5*time.Minute {
+				t.Errorf("Expected 5m hold, got %v", c.Auth.VerificationHoldTTL)
+			}
+
+			if len(c.Layout.Sections) != 3 || c.Layout.SeatsPerSection != 4 || c.Pricing.TicketPriceCents != 2500 {
+				t.Errorf("Expected layout and pricing from file, got %+v %+v", c.Layout, c.Pricing)
+			}
+
+			// Settings absent from the file keep their defaults
+			if c.Route.From != RouteFrom {
+				t.Errorf("Expected default route, got %s", c.Route.From)
+			}
+		})
+	}
+}
+
+func TestLoadFile_UnknownKey(t *testing.T) {
+	for name, content := range map[string]string{
+		"typo.yaml": "server:\n  listen_adr: \":6000\"\n",
+		"typo.toml": "[server]\nlisten_adr = \":6000\"\n",
+	} {
+		if _, err := Load(writeFile(t, name, content), noEnv, nil); err == nil {
+			t.Errorf("%s: expected unknown key to be rejected", name)
+		}
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, "config.yaml", "server:\n  listen_addr: \":6000\"\npricing:\n  ticket_price_cents: 2500\n")
+
+	env := map[string]string{
+		"TICKET_LISTEN_ADDR": ":7000",
+		"TICKET_PRICE_CENTS": "3000",
+	}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := RegisterFlags(fs)
+	if err := fs.Parse([]string{"-listen-addr", ":8000"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	c, err := Load(path, lookup, flags)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if c.Server.ListenAddr != ":8000" {
+		t.Errorf("Expected flag to win, got %s", c.Server.ListenAddr)
+	}
+
+	if c.Pricing.TicketPriceCents != 3000 {
+		t.Errorf("Expected env to override file, got %d", c.Pricing.TicketPriceCents)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(c *Config)
+		want   string
+	}{
+		{"no shutdown timeout", func(c *Config) { c.Server.ShutdownTimeout = 0 }, "shutdown_timeout"},
+		{"tls without cert", func(c *Config) { c.TLS.Enabled = true }, "tls.cert_file"},
+		{"client auth without tls", func(c *Config) { c.TLS.ClientAuth = "require"; c.TLS.ClientCAFile = "ca.pem" }, "requires tls"},
+		{"client auth without CA", func(c *Config) {
+			c.TLS = TLSConfig{Enabled: true, CertFile: "s.pem", KeyFile: "k.pem", ClientAuth: "optional"}
+		}, "tls.client_ca_file"},
+		{"unknown client auth", func(c *Config) { c.TLS.ClientAuth = "maybe" }, "tls.client_auth"},
+		{"unknown purchase mode", func(c *Config) { c.Auth.PurchaseMode = "open" }, "auth.purchase_mode"},
+		{"required purchases without JWT key", func(c *Config) { c.Auth.PurchaseMode = PurchaseAuthRequired }, "auth.jwt_key_file"},
+		{"no JWT algorithms", func(c *Config) { c.Auth.JWTAlgorithms = nil }, "auth.jwt_algorithms"},
+		{"unsupported backend", func(c *Config) { c.Store.Backend = "redis" }, "store.backend"},
+		{"duplicate section", func(c *Config) { c.Layout.Sections = []string{"A", "A"} }, "duplicate section"},
+		{"no seats", func(c *Config) { c.Layout.SeatsPerSection = 0 }, "seats_per_section"},
+		{"negative seats per row", func(c *Config) { c.Layout.SeatsPerRow = -1 }, "seats_per_row"},
+		{"unknown trace exporter", func(c *Config) { c.Tracing.Exporter = "jaeger" }, "tracing.exporter"},
+		{"sample ratio out of range", func(c *Config) { c.Tracing.SampleRatio = 2 }, "sample_ratio"},
+		{"unknown log level", func(c *Config) { c.Logging.Level = "verbose" }, "logging.level"},
+		{"unknown redaction", func(c *Config) { c.Logging.Redaction = "mask" }, "logging.redaction"},
+		{"negative price", func(c *Config) { c.Pricing.TicketPriceCents = -1 }, "ticket_price_cents"},
+		{"unsupported rate limit store", func(c *Config) { c.RateLimit.Store = "redis" }, "rate_limit.store"},
+		{"rate limit without burst", func(c *Config) { c.RateLimit.Default.PerIP.Burst = 0 }, "rate_limit.default.per_ip.burst"},
+		{"block before challenge", func(c *Config) { c.Abuse.BlockAfter = c.Abuse.ChallengeAfter }, "abuse.block_after"},
+		{"challenge too hard", func(c *Config) { c.Abuse.ChallengeDifficulty = 40 }, "abuse.challenge_difficulty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Default()
+			tt.mutate(c)
+
+			err := c.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error mentioning %q, got: %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestWriteYAML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Default().WriteYAML(&buf); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// The printed config must load back to the same settings
+	c, err := Load(writeFile(t, "effective.yaml", buf.String()), noEnv, nil)
+	if err != nil {
+		t.Fatalf("Expected printed config to load, got: %v", err)
+	}
+
+	if c.Auth.VerificationHoldTTL != VerificationHoldTTL {
+		t.Errorf("Expected hold TTL to round-trip, got %v", c.Auth.VerificationHoldTTL)
+	}
+}
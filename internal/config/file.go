@@ -0,0 +1,480 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/cloudbees/train-ticket-service/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the effective server configuration. It is built from Default,
+// then a YAML or TOML file, then environment variables, then flags.
+type Config struct {
+	Server    ServerConfig    `yaml:"server" toml:"server"`
+	TLS       TLSConfig       `yaml:"tls" toml:"tls"`
+	Auth      AuthConfig      `yaml:"auth" toml:"auth"`
+	Store     StoreConfig     `yaml:"store" toml:"store"`
+	Route     RouteConfig     `yaml:"route" toml:"route"`
+	Layout    LayoutConfig    `yaml:"layout" toml:"layout"`
+	Pricing   PricingConfig   `yaml:"pricing" toml:"pricing"`
+	Tickets   TicketsConfig   `yaml:"tickets" toml:"tickets"`
+	Receipt   ReceiptConfig   `yaml:"receipt" toml:"receipt"`
+	Gateway   GatewayConfig   `yaml:"gateway" toml:"gateway"`
+	GRPCWeb   GRPCWebConfig   `yaml:"grpc_web" toml:"grpc_web"`
+	RateLimit RateLimitConfig `yaml:"rate_limit" toml:"rate_limit"`
+	Abuse     AbuseConfig     `yaml:"abuse" toml:"abuse"`
+	Metrics   MetricsConfig   `yaml:"metrics" toml:"metrics"`
+	Tracing   TracingConfig   `yaml:"tracing" toml:"tracing"`
+	Logging   LoggingConfig   `yaml:"logging" toml:"logging"`
+}
+
+type ServerConfig struct {
+	ListenAddr string `yaml:"listen_addr" toml:"listen_addr"`
+	// ShutdownTimeout is how long in-flight calls may run after SIGINT or SIGTERM.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	// Reflection exposes the gRPC reflection service so tools like grpcurl can discover the API.
+	Reflection bool `yaml:"reflection" toml:"reflection"`
+}
+
+type TLSConfig struct {
+	Enabled  bool   `yaml:"enabled" toml:"enabled"`
+	CertFile string `yaml:"cert_file" toml:"cert_file"`
+	KeyFile  string `yaml:"key_file" toml:"key_file"`
+
+	// ClientAuth is none, optional or require; client certificates are verified against ClientCAFile.
+	ClientAuth   string `yaml:"client_auth" toml:"client_auth"`
+	ClientCAFile string `yaml:"client_ca_file" toml:"client_ca_file"`
+	// ClientIdentities maps a verified client certificate subject, e.g.
+	// "CN=billing,O=Example", to the identity the caller acts as when it sends no JWT.
+	ClientIdentities map[string]ServiceIdentity `yaml:"client_identities,omitempty" toml:"client_identities,omitempty"`
+}
+
+type ServiceIdentity struct {
+	Email string `yaml:"email" toml:"email"`
+	Role  string `yaml:"role" toml:"role"`
+}
+
+type AuthConfig struct {
+	PurchaseMode        string        `yaml:"purchase_mode" toml:"purchase_mode"`
+	VerificationHoldTTL time.Duration `yaml:"verification_hold_ttl" toml:"verification_hold_ttl"`
+	ImpersonationRoles  []string      `yaml:"impersonation_roles" toml:"impersonation_roles"`
+	// JWTKeyFile verifies caller JWTs: a PEM public key, or a file holding an
+	// HMAC secret. Empty rejects every JWT, leaving anonymous purchases and
+	// client certificates.
+	JWTKeyFile    string   `yaml:"jwt_key_file" toml:"jwt_key_file"`
+	JWTAlgorithms []string `yaml:"jwt_algorithms" toml:"jwt_algorithms"`
+	// LogVerificationCodes prints anonymous purchase codes in the log, for
+	// development without an email sender. Never enable it in production.
+	LogVerificationCodes bool `yaml:"log_verification_codes" toml:"log_verification_codes"`
+}
+
+type StoreConfig struct {
+	Backend string `yaml:"backend" toml:"backend"`
+}
+
+type RouteConfig struct {
+	From string `yaml:"from" toml:"from"`
+	To   string `yaml:"to" toml:"to"`
+}
+
+type LayoutConfig struct {
+	Sections        []string `yaml:"sections" toml:"sections"`
+	SeatsPerSection int32    `yaml:"seats_per_section" toml:"seats_per_section"`
+	// SeatsPerRow splits each section into rows with an aisle down the middle.
+	SeatsPerRow int32 `yaml:"seats_per_row" toml:"seats_per_row"`
+}
+
+type PricingConfig struct {
+	TicketPriceCents int32 `yaml:"ticket_price_cents" toml:"ticket_price_cents"`
+}
+
+type TicketsConfig struct {
+	// SigningKeyFile is a PEM Ed25519 private key that signs ticket tokens.
+	// Empty uses a key generated at startup, so tokens stop verifying on restart.
+	SigningKeyFile string `yaml:"signing_key_file" toml:"signing_key_file"`
+}
+
+// ReceiptConfig replaces the built-in e-ticket templates; an empty path keeps the built-in one.
+type ReceiptConfig struct {
+	// HTMLTemplate is an html/template file.
+	HTMLTemplate string `yaml:"html_template" toml:"html_template"`
+	// PDFTemplate is a text/template file whose output lines are set on the page.
+	PDFTemplate string `yaml:"pdf_template" toml:"pdf_template"`
+}
+
+type GatewayConfig struct {
+	// ListenAddr serves the HTTP/JSON API, e.g. ":8080"; empty disables it.
+	// It uses the server's TLS settings, client auth included.
+	ListenAddr string `yaml:"listen_addr" toml:"listen_addr"`
+}
+
+type GRPCWebConfig struct {
+	// ListenAddr serves gRPC-Web for browser clients, e.g. ":8081"; empty
+	// disables it. It uses the server's TLS settings, client auth included.
+	ListenAddr string `yaml:"listen_addr" toml:"listen_addr"`
+	// AllowedOrigins may call cross-origin, e.g. https://seats.example.com; "*" allows any.
+	AllowedOrigins []string `yaml:"allowed_origins" toml:"allowed_origins"`
+}
+
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// Store holds the token buckets; only memory is available, which limits each replica separately.
+	Store   string        `yaml:"store" toml:"store"`
+	Default RateLimitRule `yaml:"default" toml:"default"`
+	// Methods replaces Default for an RPC by name, e.g. PurchaseTicket.
+	Methods map[string]RateLimitRule `yaml:"methods,omitempty" toml:"methods,omitempty"`
+}
+
+// RateLimitRule limits each authenticated user and each client IP separately.
+type RateLimitRule struct {
+	PerUser RateLimit `yaml:"per_user" toml:"per_user"`
+	PerIP   RateLimit `yaml:"per_ip" toml:"per_ip"`
+}
+
+// RateLimit allows Burst calls at once, refilled at PerMinute. Zero disables the limit.
+type RateLimit struct {
+	PerMinute float64 `yaml:"per_minute" toml:"per_minute"`
+	Burst     int     `yaml:"burst" toml:"burst"`
+}
+
+// AbuseConfig guards the public purchase path against bots and scalpers.
+type AbuseConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// MaxTicketsPerEmailDomain caps live tickets per email domain; 0 disables the cap.
+	MaxTicketsPerEmailDomain int `yaml:"max_tickets_per_email_domain" toml:"max_tickets_per_email_domain"`
+	// ExemptDomains are shared mail providers the domain cap does not apply to.
+	ExemptDomains []string `yaml:"exempt_domains" toml:"exempt_domains"`
+	// IPWindow is how long purchases from a client IP count towards ChallengeAfter and BlockAfter.
+	IPWindow time.Duration `yaml:"ip_window" toml:"ip_window"`
+	// ChallengeAfter and BlockAfter count the other emails bought for from one
+	// IP; past them a proof-of-work challenge is required, or the purchase is refused.
+	ChallengeAfter      int           `yaml:"challenge_after" toml:"challenge_after"`
+	BlockAfter          int           `yaml:"block_after" toml:"block_after"`
+	ChallengeDifficulty int           `yaml:"challenge_difficulty" toml:"challenge_difficulty"`
+	ChallengeTTL        time.Duration `yaml:"challenge_ttl" toml:"challenge_ttl"`
+}
+
+type MetricsConfig struct {
+	// ListenAddr serves Prometheus metrics on /metrics; empty disables the endpoint.
+	ListenAddr string `yaml:"listen_addr" toml:"listen_addr"`
+}
+
+type TracingConfig struct {
+	// Exporter is none or stdout.
+	Exporter string `yaml:"exporter" toml:"exporter"`
+	// SampleRatio is the fraction of new traces recorded; propagated traces follow the caller.
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio"`
+}
+
+type LoggingConfig struct {
+	Level string `yaml:"level" toml:"level"`
+	// Redaction is none, redact or hash, applied to emails and names in request logs.
+	Redaction string `yaml:"redaction" toml:"redaction"`
+}
+
+func Default() *Config {
+	sections := make([]string, TotalSections)
+	for i := range sections {
+		sections[i] = string(rune('A' + i))
+	}
+
+	return &Config{
+		Server: ServerConfig{
+			ListenAddr:      ListenAddr,
+			ShutdownTimeout: ShutdownTimeout,
+		},
+		TLS: TLSConfig{
+			ClientAuth: ClientAuthNone,
+		},
+		Auth: AuthConfig{
+			PurchaseMode:        DefaultPurchaseAuthMode,
+			VerificationHoldTTL: VerificationHoldTTL,
+			ImpersonationRoles:  append([]string(nil), ImpersonationRoles...),
+			JWTAlgorithms:       append([]string(nil), JWTAlgorithms...),
+		},
+		Store: StoreConfig{
+			Backend: StoreBackend,
+		},
+		Route: RouteConfig{
+			From: RouteFrom,
+			To:   RouteTo,
+		},
+		Layout: LayoutConfig{
+			Sections:        sections,
+			SeatsPerSection: SeatsPerSection,
+			SeatsPerRow:     SeatsPerRow,
+		},
+		Pricing: PricingConfig{
+			TicketPriceCents: TicketPriceCents,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Store:   RateLimitStoreMemory,
+			Default: RateLimitRule{
+				PerUser: RateLimit{PerMinute: 120, Burst: 30},
+				PerIP:   RateLimit{PerMinute: 300, Burst: 60},
+			},
+			// The public purchase path is where scripts grab seats and probe emails
+			Methods: map[string]RateLimitRule{
+				"PurchaseTicket": {
+					PerUser: RateLimit{PerMinute: 5, Burst: 3},
+					PerIP:   RateLimit{PerMinute: 10, Burst: 5},
+				},
+				"VerifyPurchase": {
+					PerIP: RateLimit{PerMinute: 10, Burst: 5},
+				},
+			},
+		},
+		Abuse: AbuseConfig{
+			Enabled:                  true,
+			MaxTicketsPerEmailDomain: 10,
+			ExemptDomains:            append([]string(nil), ExemptEmailDomains...),
+			IPWindow:                 time.Hour,
+			ChallengeAfter:           3,
+			BlockAfter:               10,
+			ChallengeDifficulty:      20,
+			ChallengeTTL:             5 * time.Minute,
+		},
+		Metrics: MetricsConfig{
+			ListenAddr: MetricsListenAddr,
+		},
+		Tracing: TracingConfig{
+			Exporter:    TraceExporterNone,
+			SampleRatio: 1,
+		},
+		Logging: LoggingConfig{
+			Level:     LogLevel,
+			Redaction: RedactionRedact,
+		},
+	}
+}
+
+// LoadFile overlays the settings in path onto c. The format is chosen by
+// extension: .yaml, .yml or .toml. Unknown keys are rejected so typos do not
+// silently fall back to defaults.
+func (c *Config) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+	case ".toml":
+		md, err := toml.Decode(string(data), c)
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("parse %s: unknown key %s", path, undecoded[0])
+		}
+	default:
+		return fmt.Errorf("unsupported config format %q, use .yaml, .yml or .toml", filepath.Ext(path))
+	}
+
+	return nil
+}
+
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Server.ListenAddr == "" {
+		errs = append(errs, errors.New("server.listen_addr is required"))
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("server.shutdown_timeout must be positive"))
+	}
+
+	if c.TLS.Enabled && (c.TLS.CertFile == "" || c.TLS.KeyFile == "") {
+		errs = append(errs, errors.New("tls.cert_file and tls.key_file are required when tls is enabled"))
+	}
+	switch c.TLS.ClientAuth {
+	case ClientAuthNone:
+	case ClientAuthOptional, ClientAuthRequire:
+		if !c.TLS.Enabled {
+			errs = append(errs, errors.New("tls.client_auth requires tls to be enabled"))
+		}
+		if c.TLS.ClientCAFile == "" {
+			errs = append(errs, fmt.Errorf("tls.client_ca_file is required when tls.client_auth is %q", c.TLS.ClientAuth))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("tls.client_auth must be %q, %q or %q, got %q",
+			ClientAuthNone, ClientAuthOptional, ClientAuthRequire, c.TLS.ClientAuth))
+	}
+	for subject, id := range c.TLS.ClientIdentities {
+		if id.Email == "" {
+			errs = append(errs, fmt.Errorf("tls.client_identities[%q]: email is required", subject))
+		}
+	}
+
+	switch c.Auth.PurchaseMode {
+	case PurchaseAuthRequired, PurchaseAuthAnonymous:
+	default:
+		errs = append(errs, fmt.Errorf("auth.purchase_mode must be %q or %q, got %q", PurchaseAuthRequired, PurchaseAuthAnonymous, c.Auth.PurchaseMode))
+	}
+	if c.Auth.VerificationHoldTTL <= 0 {
+		errs = append(errs, errors.New("auth.verification_hold_ttl must be positive"))
+	}
+	if c.Auth.PurchaseMode == PurchaseAuthRequired && c.Auth.JWTKeyFile == "" {
+		errs = append(errs, fmt.Errorf("auth.jwt_key_file is required when auth.purchase_mode is %q", PurchaseAuthRequired))
+	}
+	if len(c.Auth.JWTAlgorithms) == 0 {
+		errs = append(errs, errors.New("auth.jwt_algorithms must list at least one algorithm"))
+	}
+
+	if c.Store.Backend != StoreBackendMemory {
+		errs = append(errs, fmt.Errorf("store.backend %q is not supported, only %q is available", c.Store.Backend, StoreBackendMemory))
+	}
+
+	if c.Route.From == "" || c.Route.To == "" {
+		errs = append(errs, errors.New("route.from and route.to are required"))
+	}
+
+	if len(c.Layout.Sections) == 0 {
+		errs = append(errs, errors.New("layout.sections must list at least one section"))
+	}
+	seen := make(map[string]bool)
+	for _, section := range c.Layout.Sections {
+		if section == "" || strings.ContainsAny(section, " -,") {
+			errs = append(errs, fmt.Errorf("layout.sections: invalid section name %q", section))
+		}
+		if seen[section] {
+			errs = append(errs, fmt.Errorf("layout.sections: duplicate section %q", section))
+		}
+		seen[section] = true
+	}
+	if c.Layout.SeatsPerSection < 1 {
+		errs = append(errs, errors.New("layout.seats_per_section must be at least 1"))
+	}
+	if c.Layout.SeatsPerRow < 0 {
+		errs = append(errs, errors.New("layout.seats_per_row must not be negative"))
+	}
+
+	if c.Pricing.TicketPriceCents < 0 {
+		errs = append(errs, errors.New("pricing.ticket_price_cents must not be negative"))
+	}
+
+	if c.RateLimit.Store != RateLimitStoreMemory {
+		errs = append(errs, fmt.Errorf("rate_limit.store %q is not supported, only %q is available", c.RateLimit.Store, RateLimitStoreMemory))
+	}
+	errs = append(errs, c.RateLimit.Default.validate("rate_limit.default")...)
+	for name, rule := range c.RateLimit.Methods {
+		errs = append(errs, rule.validate("rate_limit.methods."+name)...)
+	}
+
+	if c.Abuse.MaxTicketsPerEmailDomain < 0 || c.Abuse.ChallengeAfter < 0 || c.Abuse.BlockAfter < 0 {
+		errs = append(errs, errors.New("abuse limits must not be negative"))
+	}
+	if c.Abuse.ChallengeAfter > 0 && c.Abuse.BlockAfter > 0 && c.Abuse.BlockAfter <= c.Abuse.ChallengeAfter {
+		errs = append(errs, errors.New("abuse.block_after must be greater than abuse.challenge_after"))
+	}
+	if c.Abuse.IPWindow <= 0 {
+		errs = append(errs, errors.New("abuse.ip_window must be positive"))
+	}
+	if c.Abuse.ChallengeDifficulty < 1 || c.Abuse.ChallengeDifficulty > 32 {
+		errs = append(errs, errors.New("abuse.challenge_difficulty must be between 1 and 32"))
+	}
+	if c.Abuse.ChallengeTTL <= 0 {
+		errs = append(errs, errors.New("abuse.challenge_ttl must be positive"))
+	}
+
+	switch c.Tracing.Exporter {
+	case TraceExporterNone, TraceExporterStdout:
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter must be %q or %q, got %q", TraceExporterNone, TraceExporterStdout, c.Tracing.Exporter))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, errors.New("tracing.sample_ratio must be between 0 and 1"))
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Logging.Level)); err != nil {
+		errs = append(errs, fmt.Errorf("logging.level must be debug, info, warn or error, got %q", c.Logging.Level))
+	}
+	switch c.Logging.Redaction {
+	case RedactionNone, RedactionRedact, RedactionHash:
+	default:
+		errs = append(errs, fmt.Errorf("logging.redaction must be %q, %q or %q, got %q",
+			RedactionNone, RedactionRedact, RedactionHash, c.Logging.Redaction))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r RateLimitRule) validate(field string) []error {
+	var errs []error
+	for name, l := range map[string]RateLimit{"per_user": r.PerUser, "per_ip": r.PerIP} {
+		if l.PerMinute < 0 || l.Burst < 0 {
+			errs = append(errs, fmt.Errorf("%s.%s must not be negative", field, name))
+		}
+		if l.PerMinute > 0 && l.Burst == 0 {
+			errs = append(errs, fmt.Errorf("%s.%s.burst must be at least 1", field, name))
+		}
+	}
+	return errs
+}
+
+// TrainName labels this service's train in metrics and logs.
+func (c *Config) TrainName() string {
+	return c.Route.From + "-" + c.Route.To
+}
+
+func (c *Config) SeatLayout() model.Layout {
+	return model.Layout{
+		Sections:        append([]string(nil), c.Layout.Sections...),
+		SeatsPerSection: c.Layout.SeatsPerSection,
+		SeatsPerRow:     c.Layout.SeatsPerRow,
+	}
+}
+
+// WriteYAML prints the effective configuration.
+func (c *Config) WriteYAML(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// Load builds the effective configuration: defaults, then the file at path
+// (if any), then environment variables, then explicitly set flags.
+func Load(path string, lookupEnv func(string) (string, bool), flags *Flags) (*Config, error) {
+	c := Default()
+
+	if path != "" {
+		if err := c.LoadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.ApplyEnv(lookupEnv); err != nil {
+		return nil, err
+	}
+
+	if flags != nil {
+		if err := flags.Apply(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,251 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// override is a single setting that can be set from the environment or a flag.
+type override struct {
+	flag  string
+	env   string
+	usage string
+	set   func(c *Config, value string) error
+}
+
+var overrides = []override{
+	{"listen-addr", "TICKET_LISTEN_ADDR", "address the gRPC server listens on", func(c *Config, v string) error {
+		c.Server.ListenAddr = v
+		return nil
+	}},
+	{"shutdown-timeout", "TICKET_SHUTDOWN_TIMEOUT", "how long in-flight calls may finish after a shutdown signal", func(c *Config, v string) error {
+		return setDuration(&c.Server.ShutdownTimeout, v)
+	}},
+	{"reflection", "TICKET_REFLECTION", "register the gRPC reflection service", func(c *Config, v string) error {
+		return setBool(&c.Server.Reflection, v)
+	}},
+	{"gateway-addr", "TICKET_GATEWAY_ADDR", "address for the HTTP/JSON gateway, empty to disable", func(c *Config, v string) error {
+		c.Gateway.ListenAddr = v
+		return nil
+	}},
+	{"grpc-web-addr", "TICKET_GRPC_WEB_ADDR", "address for gRPC-Web browser clients, empty to disable", func(c *Config, v string) error {
+		c.GRPCWeb.ListenAddr = v
+		return nil
+	}},
+	{"grpc-web-allowed-origins", "TICKET_GRPC_WEB_ALLOWED_ORIGINS", "comma-separated origins allowed to call gRPC-Web cross-origin, * for any", func(c *Config, v string) error {
+		c.GRPCWeb.AllowedOrigins = splitList(v)
+		return nil
+	}},
+	{"rate-limit-enabled", "TICKET_RATE_LIMIT_ENABLED", "limit calls per user and per client IP", func(c *Config, v string) error {
+		return setBool(&c.RateLimit.Enabled, v)
+	}},
+	{"rate-limit-store", "TICKET_RATE_LIMIT_STORE", "where rate limit buckets are kept", func(c *Config, v string) error {
+		c.RateLimit.Store = v
+		return nil
+	}},
+	{"abuse-enabled", "TICKET_ABUSE_ENABLED", "guard public purchases against bots and scalpers", func(c *Config, v string) error {
+		return setBool(&c.Abuse.Enabled, v)
+	}},
+	{"abuse-max-tickets-per-email-domain", "TICKET_ABUSE_MAX_TICKETS_PER_EMAIL_DOMAIN", "live tickets allowed per email domain, 0 for no cap", func(c *Config, v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		c.Abuse.MaxTicketsPerEmailDomain = n
+		return nil
+	}},
+	{"abuse-exempt-domains", "TICKET_ABUSE_EXEMPT_DOMAINS", "comma-separated email domains the per-domain cap skips", func(c *Config, v string) error {
+		c.Abuse.ExemptDomains = splitList(v)
+		return nil
+	}},
+	{"metrics-addr", "TICKET_METRICS_ADDR", "address for the Prometheus /metrics endpoint, empty to disable", func(c *Config, v string) error {
+		c.Metrics.ListenAddr = v
+		return nil
+	}},
+	{"tracing-exporter", "TICKET_TRACING_EXPORTER", "trace exporter: none or stdout", func(c *Config, v string) error {
+		c.Tracing.Exporter = v
+		return nil
+	}},
+	{"tracing-sample-ratio", "TICKET_TRACING_SAMPLE_RATIO", "fraction of new traces to record, 0 to 1", func(c *Config, v string) error {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err
+		}
+		c.Tracing.SampleRatio = f
+		return nil
+	}},
+	{"log-level", "TICKET_LOG_LEVEL", "log level: debug, info, warn or error", func(c *Config, v string) error {
+		c.Logging.Level = v
+		return nil
+	}},
+	{"log-redaction", "TICKET_LOG_REDACTION", "how emails and names appear in request logs: none, redact or hash", func(c *Config, v string) error {
+		c.Logging.Redaction = v
+		return nil
+	}},
+	{"tls-enabled", "TICKET_TLS_ENABLED", "serve gRPC over TLS", func(c *Config, v string) error {
+		return setBool(&c.TLS.Enabled, v)
+	}},
+	{"tls-cert-file", "TICKET_TLS_CERT_FILE", "PEM certificate for TLS", func(c *Config, v string) error {
+		c.TLS.CertFile = v
+		return nil
+	}},
+	{"tls-key-file", "TICKET_TLS_KEY_FILE", "PEM private key for TLS", func(c *Config, v string) error {
+		c.TLS.KeyFile = v
+		return nil
+	}},
+	{"tls-client-auth", "TICKET_TLS_CLIENT_AUTH", "client certificates: none, optional or require", func(c *Config, v string) error {
+		c.TLS.ClientAuth = v
+		return nil
+	}},
+	{"tls-client-ca-file", "TICKET_TLS_CLIENT_CA_FILE", "PEM CA bundle that signs client certificates", func(c *Config, v string) error {
+		c.TLS.ClientCAFile = v
+		return nil
+	}},
+	{"purchase-auth-mode", "TICKET_PURCHASE_AUTH_MODE", "PurchaseTicket authentication: required or anonymous", func(c *Config, v string) error {
+		c.Auth.PurchaseMode = v
+		return nil
+	}},
+	{"verification-hold-ttl", "TICKET_VERIFICATION_HOLD_TTL", "how long an unverified purchase holds its seat", func(c *Config, v string) error {
+		return setDuration(&c.Auth.VerificationHoldTTL, v)
+	}},
+	{"impersonation-roles", "TICKET_IMPERSONATION_ROLES", "comma-separated roles allowed to impersonate users", func(c *Config, v string) error {
+		c.Auth.ImpersonationRoles = splitList(v)
+		return nil
+	}},
+	{"jwt-key-file", "TICKET_JWT_KEY_FILE", "PEM public key or HMAC secret file that verifies JWTs", func(c *Config, v string) error {
+		c.Auth.JWTKeyFile = v
+		return nil
+	}},
+	{"jwt-algorithms", "TICKET_JWT_ALGORITHMS", "comma-separated JWT signing algorithms to accept", func(c *Config, v string) error {
+		c.Auth.JWTAlgorithms = splitList(v)
+		return nil
+	}},
+	{"log-verification-codes", "TICKET_LOG_VERIFICATION_CODES", "print verification codes in the log (development only)", func(c *Config, v string) error {
+		return setBool(&c.Auth.LogVerificationCodes, v)
+	}},
+	{"store-backend", "TICKET_STORE_BACKEND", "storage backend", func(c *Config, v string) error {
+		c.Store.Backend = v
+		return nil
+	}},
+	{"route-from", "TICKET_ROUTE_FROM", "departure station", func(c *Config, v string) error {
+		c.Route.From = v
+		return nil
+	}},
+	{"route-to", "TICKET_ROUTE_TO", "arrival station", func(c *Config, v string) error {
+		c.Route.To = v
+		return nil
+	}},
+	{"layout-sections", "TICKET_LAYOUT_SECTIONS", "comma-separated section names", func(c *Config, v string) error {
+		c.Layout.Sections = splitList(v)
+		return nil
+	}},
+	{"layout-seats-per-section", "TICKET_LAYOUT_SEATS_PER_SECTION", "number of seats in each section", func(c *Config, v string) error {
+		return setInt32(&c.Layout.SeatsPerSection, v)
+	}},
+	{"layout-seats-per-row", "TICKET_LAYOUT_SEATS_PER_ROW", "seats in each row, split by a central aisle", func(c *Config, v string) error {
+		return setInt32(&c.Layout.SeatsPerRow, v)
+	}},
+	{"ticket-price-cents", "TICKET_PRICE_CENTS", "ticket price in cents", func(c *Config, v string) error {
+		return setInt32(&c.Pricing.TicketPriceCents, v)
+	}},
+	{"ticket-signing-key-file", "TICKET_SIGNING_KEY_FILE", "PEM Ed25519 private key that signs ticket tokens, empty for a key generated at startup", func(c *Config, v string) error {
+		c.Tickets.SigningKeyFile = v
+		return nil
+	}},
+	{"receipt-html-template", "TICKET_RECEIPT_HTML_TEMPLATE", "html/template file for e-tickets, empty for the built-in one", func(c *Config, v string) error {
+		c.Receipt.HTMLTemplate = v
+		return nil
+	}},
+	{"receipt-pdf-template", "TICKET_RECEIPT_PDF_TEMPLATE", "text/template file for PDF e-tickets, empty for the built-in one", func(c *Config, v string) error {
+		c.Receipt.PDFTemplate = v
+		return nil
+	}},
+}
+
+// ApplyEnv overlays any settings present in the environment onto c.
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	for _, o := range overrides {
+		v, ok := lookup(o.env)
+		if !ok {
+			continue
+		}
+		if err := o.set(c, v); err != nil {
+			return fmt.Errorf("%s: %w", o.env, err)
+		}
+	}
+	return nil
+}
+
+// Flags holds command-line overrides until they are applied with Apply.
+type Flags struct {
+	fs     *flag.FlagSet
+	values map[string]*string
+}
+
+// RegisterFlags defines one flag per setting on fs.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{fs: fs, values: make(map[string]*string)}
+	for _, o := range overrides {
+		f.values[o.flag] = fs.String(o.flag, "", fmt.Sprintf("%s (env %s)", o.usage, o.env))
+	}
+	return f
+}
+
+// Apply overlays the flags that were set explicitly onto c.
+func (f *Flags) Apply(c *Config) error {
+	var err error
+	f.fs.Visit(func(fl *flag.Flag) {
+		if err != nil {
+			return
+		}
+		for _, o := range overrides {
+			if o.flag == fl.Name {
+				if setErr := o.set(c, *f.values[o.flag]); setErr != nil {
+					err = fmt.Errorf("-%s: %w", o.flag, setErr)
+				}
+				return
+			}
+		}
+	})
+	return err
+}
+
+func setBool(dst *bool, v string) error {
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return err
+	}
+	*dst = b
+	return nil
+}
+
+func setDuration(dst *time.Duration, v string) error {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return err
+	}
+	*dst = d
+	return nil
+}
+
+func setInt32(dst *int32, v string) error {
+	n, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		return err
+	}
+	*dst = int32(n)
+	return nil
+}
+
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
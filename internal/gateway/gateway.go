@@ -0,0 +1,438 @@
+package gateway
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/auth"
+	"github.com/cloudbees/train-ticket-service/internal/logging"
+	"github.com/cloudbees/train-ticket-service/internal/manifest"
+	"github.com/cloudbees/train-ticket-service/internal/openapi"
+	"github.com/cloudbees/train-ticket-service/internal/ratelimit"
+	"github.com/cloudbees/train-ticket-service/internal/receipt"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// forwardedHeaders are copied from the HTTP request into gRPC metadata, so
+// the gateway goes through exactly the same auth, logging and tracing as a
+// native gRPC caller.
+var forwardedHeaders = []string{
+	"authorization",
+	auth.ImpersonationHeader,
+	logging.RequestIDHeader,
+	"traceparent",
+	"tracestate",
+	"baggage",
+}
+
+var (
+	unmarshal = protojson.UnmarshalOptions{}
+	marshal   = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+)
+
+// New returns an HTTP/JSON API that forwards every TicketService RPC to client.
+// Routes follow the google.api.http annotations in api/ticket.proto, and the
+// generated OpenAPI document is served at /openapi.json.
+func New(client ticket.TicketServiceClient) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /v1/tickets", func(w http.ResponseWriter, r *http.Request) {
+		req := &ticket.PurchaseTicketRequest{}
+		serve(w, r, req, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.PurchaseTicket(ctx, req, opts...)
+		})
+	})
+	mux.HandleFunc("POST /v1/tickets/verify", func(w http.ResponseWriter, r *http.Request) {
+		req := &ticket.VerifyPurchaseRequest{}
+		serve(w, r, req, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.VerifyPurchase(ctx, req, opts...)
+		})
+	})
+	mux.HandleFunc("GET /v1/me/receipt", func(w http.ResponseWriter, r *http.Request) {
+		req := &ticket.ViewUserReceiptRequest{}
+		serve(w, r, nil, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.ViewUserReceipt(ctx, req, opts...)
+		})
+	})
+	mux.HandleFunc("GET /v1/me/receipt/download", func(w http.ResponseWriter, r *http.Request) {
+		req := &ticket.DownloadReceiptRequest{Format: r.URL.Query().Get("format")}
+		var header metadata.MD
+		body, err := client.DownloadReceipt(outgoingContext(r), req, grpc.Header(&header))
+		// A unary response is a stream of one
+		sent := false
+		serveBody(w, &header, "ticket."+cmp.Or(req.Format, receipt.FormatHTML), func() (*httpbody.HttpBody, error) {
+			if sent {
+				return nil, io.EOF
+			}
+			sent = true
+			return body, err
+		})
+	})
+	mux.HandleFunc("GET /v1/allocations", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		req := &ticket.ViewAllocationsRequest{
+			Section:   query.Get("section"),
+			PageToken: query.Get("page_token"),
+			OrderBy:   query.Get("order_by"),
+			Search:    query.Get("search"),
+			Status:    query.Get("status"),
+		}
+		if v := query.Get("page_size"); v != "" {
+			n, err := strconv.ParseInt(v, 10, 32)
+			if err != nil {
+				writeError(w, status.Errorf(codes.InvalidArgument, "page_size: %v", err))
+				return
+			}
+			req.PageSize = int32(n)
+		}
+		serve(w, r, nil, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.ViewAllocations(ctx, req, opts...)
+		})
+	})
+	mux.HandleFunc("DELETE /v1/me/ticket", func(w http.ResponseWriter, r *http.Request) {
+		req := &ticket.RemoveUserFromTrainRequest{}
+		serve(w, r, nil, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.RemoveUserFromTrain(ctx, req, opts...)
+		})
+	})
+	mux.HandleFunc("DELETE /v1/tickets/{email}", func(w http.ResponseWriter, r *http.Request) {
+		req := &ticket.RemoveUserFromTrainRequest{Email: r.PathValue("email")}
+		serve(w, r, nil, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.RemoveUserFromTrain(ctx, req, opts...)
+		})
+	})
+	mux.HandleFunc("PATCH /v1/me/seat", func(w http.ResponseWriter, r *http.Request) {
+		req := &ticket.ModifyUserSeatRequest{}
+		serve(w, r, req, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			req.Email = ""
+			return client.ModifyUserSeat(ctx, req, opts...)
+		})
+	})
+	mux.HandleFunc("PATCH /v1/tickets/{email}/seat", func(w http.ResponseWriter, r *http.Request) {
+		req := &ticket.ModifyUserSeatRequest{}
+		serve(w, r, req, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			req.Email = r.PathValue("email")
+			return client.ModifyUserSeat(ctx, req, opts...)
+		})
+	})
+	mux.HandleFunc("POST /v1/swaps", func(w http.ResponseWriter, r *http.Request) {
+		req := &ticket.RequestSeatSwapRequest{}
+		serve(w, r, req, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.RequestSeatSwap(ctx, req, opts...)
+		})
+	})
+	mux.HandleFunc("POST /v1/swaps/{swap_id}/accept", func(w http.ResponseWriter, r *http.Request) {
+		req := &ticket.AcceptSeatSwapRequest{SwapId: r.PathValue("swap_id")}
+		serve(w, r, nil, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.AcceptSeatSwap(ctx, req, opts...)
+		})
+	})
+	mux.HandleFunc("POST /v1/bulk", func(w http.ResponseWriter, r *http.Request) {
+		req := &ticket.BulkApplyRequest{}
+		serve(w, r, req, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.BulkApply(ctx, req, opts...)
+		})
+	})
+	mux.HandleFunc("POST /v1/sections/{section}/decommission", func(w http.ResponseWriter, r *http.Request) {
+		req := &ticket.DecommissionSectionRequest{}
+		serve(w, r, req, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			req.Section = r.PathValue("section")
+			return client.DecommissionSection(ctx, req, opts...)
+		})
+	})
+	mux.HandleFunc("POST /v1/sections/{section}/reinstate", func(w http.ResponseWriter, r *http.Request) {
+		req := &ticket.ReinstateSectionRequest{Section: r.PathValue("section")}
+		serve(w, r, nil, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.ReinstateSection(ctx, req, opts...)
+		})
+	})
+	mux.HandleFunc("POST /v1/admin/tickets", func(w http.ResponseWriter, r *http.Request) {
+		req := &ticket.AdminPurchaseTicketRequest{}
+		serve(w, r, req, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.AdminPurchaseTicket(ctx, req, opts...)
+		})
+	})
+	mux.HandleFunc("POST /v1/tickets/challenge", func(w http.ResponseWriter, r *http.Request) {
+		req := &ticket.RequestPurchaseChallengeRequest{}
+		serve(w, r, req, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.RequestPurchaseChallenge(ctx, req, opts...)
+		})
+	})
+	mux.HandleFunc("GET /v1/admin/flagged-purchases", func(w http.ResponseWriter, r *http.Request) {
+		req := &ticket.ListFlaggedPurchasesRequest{}
+		serve(w, r, nil, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.ListFlaggedPurchases(ctx, req, opts...)
+		})
+	})
+	mux.HandleFunc("GET /v1/seatmap", func(w http.ResponseWriter, r *http.Request) {
+		req := &ticket.GetSeatMapRequest{Section: r.URL.Query().Get("section")}
+		serve(w, r, nil, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.GetSeatMap(ctx, req, opts...)
+		})
+	})
+	mux.HandleFunc("GET /v1/revocations", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		req := &ticket.SyncRevocationsRequest{Epoch: query.Get("epoch")}
+		if v := query.Get("since"); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				writeError(w, status.Errorf(codes.InvalidArgument, "since: %v", err))
+				return
+			}
+			req.Since = n
+		}
+		serve(w, r, nil, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			return client.SyncRevocations(ctx, req, opts...)
+		})
+	})
+
+	mux.HandleFunc("GET /v1/admin/manifest", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		req := &ticket.ExportManifestRequest{Format: query.Get("format"), Section: query.Get("section")}
+		// Accept fields=a,b as well as fields=a&fields=b
+		for _, v := range query["fields"] {
+			for _, f := range strings.Split(v, ",") {
+				if f = strings.TrimSpace(f); f != "" {
+					req.Fields = append(req.Fields, f)
+				}
+			}
+		}
+		if v := query.Get("mask_emails"); v != "" {
+			mask, err := strconv.ParseBool(v)
+			if err != nil {
+				writeError(w, status.Errorf(codes.InvalidArgument, "mask_emails: %v", err))
+				return
+			}
+			req.MaskEmails = mask
+		}
+
+		var header metadata.MD
+		stream, err := client.ExportManifest(outgoingContext(r), req, grpc.Header(&header))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		format := cmp.Or(req.Format, manifest.FormatCSV)
+		serveBody(w, &header, "manifest."+format, stream.Recv)
+	})
+	mux.HandleFunc("POST /v1/admin/bookings/import", func(w http.ResponseWriter, r *http.Request) {
+		req := &ticket.ImportBookingsRequest{}
+		serve(w, r, req, func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error) {
+			// The whole import arrives in one body, so it is sent as one message
+			stream, err := client.ImportBookings(ctx, opts...)
+			if err != nil {
+				return nil, err
+			}
+			if err := stream.Send(req); err != nil && err != io.EOF {
+				return nil, err
+			}
+			return stream.CloseAndRecv()
+		})
+	})
+
+	mux.HandleFunc("GET /v1/occupancy", func(w http.ResponseWriter, r *http.Request) {
+		var header metadata.MD
+		stream, err := client.WatchOccupancy(outgoingContext(r), &ticket.WatchOccupancyRequest{}, grpc.Header(&header))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		serveStream(w, &header, func() (proto.Message, error) { return stream.Recv() })
+	})
+
+	spec, specErr := openapi.Spec()
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		if specErr != nil {
+			writeError(w, status.Errorf(codes.Internal, "failed to generate OpenAPI spec: %v", specErr))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(spec)
+	})
+
+	return mux
+}
+
+type call func(ctx context.Context, opts ...grpc.CallOption) (proto.Message, error)
+
+// serve decodes the JSON body into body (when not nil), invokes the RPC with
+// the forwarded headers and writes the response or the mapped error.
+func serve(w http.ResponseWriter, r *http.Request, body proto.Message, rpc call) {
+	if body != nil {
+		data, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			writeError(w, status.Error(codes.InvalidArgument, "failed to read request body"))
+			return
+		}
+		if len(data) > 0 {
+			if err := unmarshal.Unmarshal(data, body); err != nil {
+				writeError(w, status.Errorf(codes.InvalidArgument, "invalid JSON body: %v", err))
+				return
+			}
+		}
+	}
+
+	var header metadata.MD
+	resp, err := rpc(outgoingContext(r), grpc.Header(&header))
+	setResponseHeaders(w, header)
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	data, err := marshal.Marshal(resp)
+	if err != nil {
+		writeError(w, status.Error(codes.Internal, "failed to encode response"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// serveStream writes each message from recv as a line of JSON, {"result": ...},
+// flushing as it goes. Errors before the first message get an HTTP status;
+// later ones can only be sent in-band as a final {"error": ...} line.
+func serveStream(w http.ResponseWriter, header *metadata.MD, recv func() (proto.Message, error)) {
+	msg, err := recv()
+	setResponseHeaders(w, *header)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	rc := http.NewResponseController(w)
+	for {
+		data, err := marshal.Marshal(msg)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(w, "{\"result\":%s}\n", data)
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		msg, err = recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			data, _ := marshal.Marshal(status.Convert(err).Proto())
+			fmt.Fprintf(w, "{\"error\":%s}\n", data)
+			return
+		}
+	}
+}
+
+// serveBody writes a stream of HttpBody messages as one raw response, offered
+// as a download named filename. A failure after the first message can only
+// abort the response, which the client sees as a truncated body.
+func serveBody(w http.ResponseWriter, header *metadata.MD, filename string, recv func() (*httpbody.HttpBody, error)) {
+	body, err := recv()
+	setResponseHeaders(w, *header)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", body.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	for {
+		if _, err := w.Write(body.Data); err != nil {
+			return
+		}
+
+		body, err = recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
+
+func outgoingContext(r *http.Request) context.Context {
+	md := metadata.MD{}
+	for _, h := range forwardedHeaders {
+		if v := r.Header.Get(h); v != "" {
+			md.Set(h, v)
+		}
+	}
+	// The service sees the gateway as its peer, so pass on who is really calling.
+	// An X-Forwarded-For sent by the client is not trusted.
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		md.Set(ratelimit.ForwardedForHeader, host)
+	}
+	// Likewise for the client certificate, which is never copied from the request
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		md.Set(auth.ClientSubjectHeader, r.TLS.VerifiedChains[0][0].Subject.String())
+	}
+	return metadata.NewOutgoingContext(r.Context(), md)
+}
+
+// setResponseHeaders copies the request id and any retry-after hint from the
+// gRPC response headers.
+func setResponseHeaders(w http.ResponseWriter, header metadata.MD) {
+	if ids := header.Get(logging.RequestIDHeader); len(ids) > 0 {
+		w.Header().Set(logging.RequestIDHeader, ids[0])
+	}
+	if v := header.Get(ratelimit.RetryAfterHeader); len(v) > 0 {
+		w.Header().Set("Retry-After", v[0])
+	}
+}
+
+// writeError writes the gRPC status as JSON, e.g. {"code":5,"message":"ticket not found"}.
+func writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	data, _ := marshal.Marshal(st.Proto())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(HTTPStatus(st.Code()))
+	w.Write(data)
+}
+
+// HTTPStatus maps a gRPC code to the HTTP status the gateway returns for it.
+func HTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499 // Client Closed Request
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
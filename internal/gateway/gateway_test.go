@@ -0,0 +1,504 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/auth"
+	"github.com/cloudbees/train-ticket-service/internal/config"
+	"github.com/cloudbees/train-ticket-service/internal/logging"
+	"github.com/cloudbees/train-ticket-service/internal/openapi"
+	"github.com/cloudbees/train-ticket-service/internal/ratelimit"
+	"github.com/cloudbees/train-ticket-service/internal/service"
+	"github.com/cloudbees/train-ticket-service/internal/store"
+	"github.com/cloudbees/train-ticket-service/internal/tlsutil"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var pathParam = regexp.MustCompile(`\{[^}]+\}`)
+
+func createTestJWT(email, firstName, lastName, role string) string {
+	claims := jwt.MapClaims{
+		"email":      email,
+		"first_name": firstName,
+		"last_name":  lastName,
+		"role":       role,
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, _ := token.SignedString([]byte("test-secret"))
+	return tokenString
+}
+
+// newTestGateway serves the real service over bufconn behind the gateway.
+func newTestGateway(t *testing.T, interceptors ...grpc.UnaryServerInterceptor) *httptest.Server {
+	t.Helper()
+
+	redactor, err := logging.NewRedactor(logging.RedactionRedact)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	interceptors = append([]grpc.UnaryServerInterceptor{
+		logging.UnaryServerInterceptor(slog.New(slog.DiscardHandler), redactor),
+	}, interceptors...)
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+	ticket.RegisterTicketServiceServer(srv, service.NewTicketService(store.NewStore(),
+		service.WithPurchaseAuthMode(config.PurchaseAuthRequired),
+		service.WithTokenVerifier(auth.NewVerifier([]byte("test-secret"), []string{"HS256"})),
+	))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	ts := httptest.NewServer(New(ticket.NewTicketServiceClient(conn)))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func do(t *testing.T, ts *httptest.Server, method, path, token, body string) (*http.Response, map[string]any) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Expected JSON body, got %q: %v", data, err)
+	}
+	return resp, out
+}
+
+func TestGateway_PurchaseAndReceipt(t *testing.T) {
+	ts := newTestGateway(t)
+	token := createTestJWT("john@example.com", "John", "Doe", "user")
+
+	resp, body := do(t, ts, "POST", "/v1/tickets", token,
+		`{"first_name":"John","last_name":"Doe","email":"john@example.com"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %v", resp.StatusCode, body)
+	}
+	if resp.Header.Get(logging.RequestIDHeader) == "" {
+		t.Error("Expected a request id in the response headers")
+	}
+
+	resp, body = do(t, ts, "GET", "/v1/me/receipt", token, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %v", resp.StatusCode, body)
+	}
+	receipt := body["receipt"].(map[string]any)
+	if receipt["price_paid"] != float64(2000) {
+		t.Errorf("Expected price_paid 2000, got %v", receipt["price_paid"])
+	}
+}
+
+func TestGateway_AdminRoutes(t *testing.T) {
+	ts := newTestGateway(t)
+	user := createTestJWT("john@example.com", "John", "Doe", "user")
+	admin := createTestJWT("admin@example.com", "Admin", "User", "admin")
+
+	do(t, ts, "POST", "/v1/tickets", user, `{"first_name":"John","last_name":"Doe","email":"john@example.com"}`)
+
+	resp, body := do(t, ts, "GET", "/v1/allocations?section=A", admin, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %v", resp.StatusCode, body)
+	}
+	if n := len(body["allocations"].([]any)); n != 1 {
+		t.Errorf("Expected 1 allocation in section A, got %d", n)
+	}
+
+	resp, body = do(t, ts, "GET", "/v1/allocations?search=JOHN&status=confirmed&order_by=name+desc&page_size=1", admin, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %v", resp.StatusCode, body)
+	}
+	if body["total_size"] != float64(1) || body["next_page_token"] != "" {
+		t.Errorf("Expected a single page of one allocation, got %v", body)
+	}
+
+	resp, body = do(t, ts, "PATCH", "/v1/tickets/john@example.com/seat", admin, `{"section":"B","seat_number":3}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %v", resp.StatusCode, body)
+	}
+
+	_, body = do(t, ts, "GET", "/v1/allocations?section=A", admin, "")
+	if n := len(body["allocations"].([]any)); n != 0 {
+		t.Errorf("Expected section A to be empty after the move, got %d", n)
+	}
+
+	resp, body = do(t, ts, "DELETE", "/v1/tickets/john@example.com", admin, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %v", resp.StatusCode, body)
+	}
+
+	resp, _ = do(t, ts, "GET", "/v1/me/receipt", user, "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 after removal, got %d", resp.StatusCode)
+	}
+}
+
+func TestGateway_Errors(t *testing.T) {
+	ts := newTestGateway(t)
+	user := createTestJWT("john@example.com", "John", "Doe", "user")
+
+	tests := []struct {
+		name         string
+		method, path string
+		token, body  string
+		wantStatus   int
+		wantCode     codes.Code
+	}{
+		{"missing token", "GET", "/v1/me/receipt", "", "", http.StatusUnauthorized, codes.Unauthenticated},
+		{"not admin", "GET", "/v1/allocations", user, "", http.StatusForbidden, codes.PermissionDenied},
+		{"bad page size", "GET", "/v1/allocations?page_size=ten", user, "", http.StatusBadRequest, codes.InvalidArgument},
+		{"no ticket", "GET", "/v1/me/receipt", user, "", http.StatusNotFound, codes.NotFound},
+		{"bad json", "POST", "/v1/tickets", user, "{", http.StatusBadRequest, codes.InvalidArgument},
+		{"invalid email", "POST", "/v1/tickets", user, `{"first_name":"John","last_name":"Doe","email":"@@"}`, http.StatusBadRequest, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := do(t, ts, tt.method, tt.path, tt.token, tt.body)
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %v", tt.wantStatus, resp.StatusCode, body)
+			}
+			if body["code"] != float64(tt.wantCode) {
+				t.Errorf("Expected code %d, got %v", tt.wantCode, body["code"])
+			}
+			if body["message"] == "" {
+				t.Error("Expected an error message")
+			}
+		})
+	}
+}
+
+// TestGateway_ServesSpecRoutes fails when a route in the OpenAPI spec has no
+// handler, e.g. after adding an http annotation without a gateway route.
+func TestGateway_ServesSpecRoutes(t *testing.T) {
+	ts := newTestGateway(t)
+
+	resp, err := http.Get(ts.URL + "/openapi.json")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var doc openapi.Document
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(doc.Paths) == 0 {
+		t.Fatal("Expected paths in the served spec")
+	}
+
+	for path, item := range doc.Paths {
+		for verb := range item {
+			method := strings.ToUpper(verb)
+			url := pathParam.ReplaceAllString(path, "x")
+			t.Run(method+" "+path, func(t *testing.T) {
+				req, _ := http.NewRequest(method, ts.URL+url, nil)
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Fatalf("Expected no error, got: %v", err)
+				}
+				resp.Body.Close()
+				// The mux answers unrouted requests with plain text
+				if ct := resp.Header.Get("Content-Type"); strings.HasPrefix(ct, "text/plain") {
+					t.Errorf("Expected a gateway response, got %d %s", resp.StatusCode, ct)
+				}
+			})
+		}
+	}
+}
+
+func TestGateway_StreamsOccupancy(t *testing.T) {
+	ts := newTestGateway(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", ts.URL+"/v1/occupancy", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Expected application/x-ndjson, got %s", ct)
+	}
+
+	var line struct {
+		Result struct {
+			Sections []map[string]any `json:"sections"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&line); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(line.Result.Sections) != 2 {
+		t.Errorf("Expected 2 sections in the first update, got %v", line.Result.Sections)
+	}
+}
+
+func TestGateway_ExportsManifest(t *testing.T) {
+	ts := newTestGateway(t)
+	user := createTestJWT("john@example.com", "John", "Doe", "user")
+	admin := createTestJWT("admin@example.com", "Admin", "User", "admin")
+
+	do(t, ts, "POST", "/v1/tickets", user, `{"first_name":"John","last_name":"Doe","email":"john@example.com"}`)
+
+	req, _ := http.NewRequest("GET", ts.URL+"/v1/admin/manifest?fields=seat_number,email&mask_emails=true", nil)
+	req.Header.Set("Authorization", "Bearer "+admin)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, data)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Errorf("Expected text/csv, got %s", ct)
+	}
+	if cd := resp.Header.Get("Content-Disposition"); cd != "attachment; filename=manifest.csv" {
+		t.Errorf("Expected a manifest.csv attachment, got %q", cd)
+	}
+	if want := "seat_number,email\n1,j***@example.com\n"; string(data) != want {
+		t.Errorf("Expected %q, got %q", want, data)
+	}
+
+	resp, _ = do(t, ts, "GET", "/v1/admin/manifest?format=docx", admin, "")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown format, got %d", resp.StatusCode)
+	}
+}
+
+func TestGateway_ImportsBookings(t *testing.T) {
+	ts := newTestGateway(t)
+	admin := createTestJWT("admin@example.com", "Admin", "User", "admin")
+
+	rows := `[{"first_name":"Ann","last_name":"Lee","email":"ann@example.com","section":"B","seat_number":1},` +
+		`{"first_name":"Bo","last_name":"Ng","email":"bo@example.com","section":"B","seat_number":1}]`
+	resp, body := do(t, ts, "POST", "/v1/admin/bookings/import", admin, `{"commit":true,"rows":`+rows+`}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %v", resp.StatusCode, body)
+	}
+	if body["committed"] != false || len(body["conflicts"].([]any)) != 1 {
+		t.Errorf("Expected one conflict and no commit, got %v", body)
+	}
+
+	resp, body = do(t, ts, "POST", "/v1/admin/bookings/import", admin, `{"commit":true,"rows":[{"first_name":"Ann","last_name":"Lee","email":"ann@example.com","section":"B","seat_number":1}]}`)
+	if resp.StatusCode != http.StatusOK || body["committed"] != true {
+		t.Fatalf("Expected the import to commit, got %d: %v", resp.StatusCode, body)
+	}
+
+	user := createTestJWT("ann@example.com", "Ann", "Lee", "user")
+	resp, _ = do(t, ts, "GET", "/v1/me/receipt", user, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the imported ticket, got %d", resp.StatusCode)
+	}
+}
+
+func TestGateway_DownloadsReceipt(t *testing.T) {
+	ts := newTestGateway(t)
+	user := createTestJWT("john@example.com", "John", "Doe", "user")
+
+	do(t, ts, "POST", "/v1/tickets", user, `{"first_name":"John","last_name":"Doe","email":"john@example.com"}`)
+
+	req, _ := http.NewRequest("GET", ts.URL+"/v1/me/receipt/download?format=pdf", nil)
+	req.Header.Set("Authorization", "Bearer "+user)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, data)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/pdf" || !strings.HasPrefix(string(data), "%PDF-") {
+		t.Errorf("Expected a PDF, got %s", ct)
+	}
+	if cd := resp.Header.Get("Content-Disposition"); cd != "attachment; filename=ticket.pdf" {
+		t.Errorf("Expected a ticket.pdf attachment, got %q", cd)
+	}
+
+	resp, _ = do(t, ts, "GET", "/v1/me/receipt/download", "", "")
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a token, got %d", resp.StatusCode)
+	}
+}
+
+func TestGateway_SyncsRevocations(t *testing.T) {
+	ts := newTestGateway(t)
+	user := createTestJWT("john@example.com", "John", "Doe", "user")
+
+	_, body := do(t, ts, "POST", "/v1/tickets", user, `{"first_name":"John","last_name":"Doe","email":"john@example.com"}`)
+	ticketID := body["receipt"].(map[string]any)["ticket_id"]
+	do(t, ts, "PATCH", "/v1/me/seat", user, `{"section":"B","seat_number":1}`)
+
+	resp, body := do(t, ts, "GET", "/v1/revocations?since=0", "", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %v", resp.StatusCode, body)
+	}
+	// int64 fields are strings in JSON
+	if body["cursor"] != "1" || len(body["keys"].([]any)) != 1 {
+		t.Errorf("Expected one key and cursor 1, got %v", body)
+	}
+	revocations := body["revocations"].([]any)
+	if len(revocations) != 1 || revocations[0].(map[string]any)["ticket_id"] != ticketID {
+		t.Errorf("Expected the old seat of %v to be revoked, got %v", ticketID, revocations)
+	}
+
+	resp, _ = do(t, ts, "GET", "/v1/revocations?since=soon", "", "")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a bad cursor, got %d", resp.StatusCode)
+	}
+}
+
+func TestGateway_RateLimitedByClientIP(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.NewMemoryStore(), ratelimit.Rules{
+		Default: ratelimit.Rule{PerIP: ratelimit.Limit{PerMinute: 6, Burst: 1}},
+	}, nil)
+	ts := newTestGateway(t, limiter.UnaryServerInterceptor())
+
+	// Unauthenticated, but the limit applies before the service rejects it
+	resp, _ := do(t, ts, "GET", "/v1/me/receipt", "", "")
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected 401, got %d", resp.StatusCode)
+	}
+
+	resp, body := do(t, ts, "GET", "/v1/me/receipt", "", "")
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Expected 429, got %d: %v", resp.StatusCode, body)
+	}
+	if got := resp.Header.Get("Retry-After"); got != "10" {
+		t.Errorf("Expected Retry-After 10, got %q", got)
+	}
+}
+
+func TestGateway_ClientCertificateIdentity(t *testing.T) {
+	certs, err := tlsutil.GenerateDevCerts(t.TempDir(), []string{"localhost"}, "billing")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	cfg := config.Default()
+	cfg.TLS.ClientIdentities = map[string]config.ServiceIdentity{
+		"CN=billing": {Email: "billing@services.example.com", Role: "admin"},
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(auth.TrustedSubjectUnaryInterceptor()))
+	ticket.RegisterTicketServiceServer(srv, service.NewTicketService(store.NewStore(), service.WithConfig(cfg)))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	serverConfig, err := tlsutil.ServerConfig(tlsutil.ServerOptions{
+		CertFile:     certs.ServerCertFile,
+		KeyFile:      certs.ServerKeyFile,
+		ClientCAFile: certs.CAFile,
+		ClientAuth:   tlsutil.ClientAuthOptional,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	ts := httptest.NewUnstartedServer(New(ticket.NewTicketServiceClient(conn)))
+	ts.TLS = serverConfig
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+
+	get := func(opts tlsutil.ClientOptions, header string) int {
+		opts.CAFile = certs.CAFile
+		opts.ServerName = "localhost"
+		clientConfig, err := tlsutil.ClientConfig(opts)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientConfig}}
+		req, _ := http.NewRequest("GET", ts.URL+"/v1/allocations", nil)
+		if header != "" {
+			req.Header.Set(auth.ClientSubjectHeader, header)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// The verified certificate reaches the service through the gateway
+	if code := get(tlsutil.ClientOptions{CertFile: certs.ClientCertFile, KeyFile: certs.ClientKeyFile}, ""); code != http.StatusOK {
+		t.Errorf("Expected 200 with a client certificate, got %d", code)
+	}
+
+	// Claiming the subject in a header does not
+	if code := get(tlsutil.ClientOptions{}, "CN=billing"); code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a forged subject header, got %d", code)
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := map[codes.Code]int{
+		codes.OK:                 http.StatusOK,
+		codes.InvalidArgument:    http.StatusBadRequest,
+		codes.NotFound:           http.StatusNotFound,
+		codes.AlreadyExists:      http.StatusConflict,
+		codes.FailedPrecondition: http.StatusBadRequest,
+		codes.ResourceExhausted:  http.StatusTooManyRequests,
+		codes.Internal:           http.StatusInternalServerError,
+	}
+	for code, want := range tests {
+		if got := HTTPStatus(code); got != want {
+			t.Errorf("HTTPStatus(%s) = %d, want %d", code, got, want)
+		}
+	}
+}
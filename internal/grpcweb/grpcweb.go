@@ -0,0 +1,268 @@
+package grpcweb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/cloudbees/train-ticket-service/internal/auth"
+	"github.com/cloudbees/train-ticket-service/internal/logging"
+	"google.golang.org/grpc"
+)
+
+const (
+	contentTypeGRPC    = "application/grpc"
+	contentTypeWeb     = "application/grpc-web"
+	contentTypeWebText = "application/grpc-web-text"
+
+	// trailerFlag marks the frame that carries the trailers at the end of a
+	// gRPC-Web response body.
+	trailerFlag = 0x80
+)
+
+// allowedHeaders are the request headers a browser may send cross-origin:
+// the gRPC-Web protocol headers plus those the service reads.
+var allowedHeaders = []string{
+	"content-type",
+	"x-grpc-web",
+	"x-user-agent",
+	"grpc-timeout",
+	"authorization",
+	auth.ImpersonationHeader,
+	logging.RequestIDHeader,
+	"traceparent",
+	"tracestate",
+}
+
+// exposedHeaders are the response headers a cross-origin browser may read.
+// Trailers-only responses carry the status here rather than in the body.
+var exposedHeaders = []string{
+	"grpc-status",
+	"grpc-message",
+	"grpc-status-details-bin",
+	logging.RequestIDHeader,
+}
+
+// New serves the gRPC-Web protocol that browsers speak over HTTP/1.1,
+// translating each call for srv in-process. Cross-origin callers are allowed
+// from allowedOrigins, where "*" allows any origin. Anything that is not a
+// gRPC-Web or CORS preflight request gets a 404.
+func New(srv *grpc.Server, allowedOrigins []string) http.Handler {
+	return &handler{srv: srv, originAllowed: originAllowed(allowedOrigins)}
+}
+
+type handler struct {
+	srv           *grpc.Server
+	originAllowed func(origin string) bool
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case isPreflight(r):
+		h.preflight(w, r)
+	case r.Method == http.MethodPost && isGRPCWeb(r):
+		h.cors(w, r)
+		h.serve(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func isGRPCWeb(r *http.Request) bool {
+	return strings.HasPrefix(strings.ToLower(r.Header.Get("Content-Type")), contentTypeWeb)
+}
+
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions &&
+		r.Header.Get("Origin") != "" &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// preflight answers a CORS preflight. A disallowed origin still gets a reply,
+// just without the headers the browser needs to go ahead.
+func (h *handler) preflight(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	w.Header().Add("Vary", "Origin")
+	if h.originAllowed(origin) {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Methods", http.MethodPost)
+		w.Header().Set("Access-Control-Allow-Headers", strings.Join(allowedHeaders, ", "))
+		w.Header().Set("Access-Control-Max-Age", "600")
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) cors(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return
+	}
+	w.Header().Add("Vary", "Origin")
+	if h.originAllowed(origin) {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Expose-Headers", strings.Join(exposedHeaders, ", "))
+	}
+}
+
+// serve rewrites the call as the HTTP/2 gRPC request srv expects and turns
+// the response back into gRPC-Web, moving the trailers into the body. The
+// TLS state and remote address are kept, so srv sees the caller's client
+// certificate as it would on its own listener.
+func (h *handler) serve(w http.ResponseWriter, r *http.Request) {
+	contentType := strings.ToLower(r.Header.Get("Content-Type"))
+	text := strings.HasPrefix(contentType, contentTypeWebText)
+	webType := contentTypeWeb
+	if text {
+		webType = contentTypeWebText
+	}
+	subtype := strings.TrimPrefix(contentType, webType)
+
+	req := r.Clone(r.Context())
+	req.Proto = "HTTP/2"
+	req.ProtoMajor = 2
+	req.ProtoMinor = 0
+	req.Header.Set("Content-Type", contentTypeGRPC+subtype)
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+	if text {
+		req.Body = io.NopCloser(base64.NewDecoder(base64.StdEncoding, r.Body))
+	}
+
+	rw := &responseWriter{w: w, header: make(http.Header), webType: webType, text: text}
+	h.srv.ServeHTTP(rw, req)
+	rw.finish()
+}
+
+// responseWriter sits between srv and the browser. Headers are held back
+// until the first message or an explicit WriteHeader, so a call that fails
+// before sending anything can still be answered trailers-only.
+type responseWriter struct {
+	w       http.ResponseWriter
+	header  http.Header
+	webType string
+	text    bool
+	written bool
+}
+
+func (rw *responseWriter) Header() http.Header {
+	return rw.header
+}
+
+func (rw *responseWriter) WriteHeader(code int) {
+	if rw.written {
+		return
+	}
+	rw.written = true
+
+	dst := rw.w.Header()
+	for k, vv := range rw.header {
+		if k == "Trailer" || strings.HasPrefix(k, http.TrailerPrefix) || rw.isTrailer(k) {
+			continue
+		}
+		dst[k] = vv
+	}
+	dst.Set("Content-Type", rw.contentType())
+	rw.w.WriteHeader(code)
+}
+
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	rw.WriteHeader(http.StatusOK)
+	if rw.text {
+		if _, err := io.WriteString(rw.w, base64.StdEncoding.EncodeToString(b)); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	return rw.w.Write(b)
+}
+
+func (rw *responseWriter) Flush() {
+	if !rw.written {
+		return
+	}
+	if f, ok := rw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// finish sends the trailers: as plain headers when nothing else was sent,
+// otherwise as a trailer frame at the end of the body.
+func (rw *responseWriter) finish() {
+	trailers := rw.trailers()
+
+	if !rw.written {
+		for k := range rw.header {
+			if strings.HasPrefix(k, http.TrailerPrefix) {
+				delete(rw.header, k)
+			}
+		}
+		rw.header.Del("Trailer")
+		for k, vv := range trailers {
+			rw.header[k] = vv
+		}
+		rw.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var buf bytes.Buffer
+	keys := make([]string, 0, len(trailers))
+	for k := range trailers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range trailers[k] {
+			buf.WriteString(strings.ToLower(k) + ": " + v + "\r\n")
+		}
+	}
+
+	frame := make([]byte, 5, 5+buf.Len())
+	frame[0] = trailerFlag
+	binary.BigEndian.PutUint32(frame[1:], uint32(buf.Len()))
+	rw.Write(append(frame, buf.Bytes()...))
+	rw.Flush()
+}
+
+// trailers collects the declared trailers and the ones srv set with
+// http.TrailerPrefix after the headers went out.
+func (rw *responseWriter) trailers() http.Header {
+	out := make(http.Header)
+	for k, vv := range rw.header {
+		switch {
+		case strings.HasPrefix(k, http.TrailerPrefix):
+			name := http.CanonicalHeaderKey(strings.TrimPrefix(k, http.TrailerPrefix))
+			out[name] = append(out[name], vv...)
+		case rw.isTrailer(k):
+			out[k] = append(out[k], vv...)
+		}
+	}
+	return out
+}
+
+func (rw *responseWriter) isTrailer(key string) bool {
+	for _, declared := range rw.header.Values("Trailer") {
+		if strings.EqualFold(declared, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rw *responseWriter) contentType() string {
+	return strings.Replace(rw.header.Get("Content-Type"), contentTypeGRPC, rw.webType, 1)
+}
+
+func originAllowed(allowed []string) func(origin string) bool {
+	return func(origin string) bool {
+		for _, a := range allowed {
+			if a == "*" || strings.EqualFold(a, origin) {
+				return true
+			}
+		}
+		return false
+	}
+}
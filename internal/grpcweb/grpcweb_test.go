@@ -0,0 +1,173 @@
+package grpcweb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/service"
+	"github.com/cloudbees/train-ticket-service/internal/store"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestServer(t *testing.T, origins ...string) *httptest.Server {
+	t.Helper()
+
+	srv := grpc.NewServer()
+	svc := service.NewTicketService(store.NewStore())
+	ticket.RegisterTicketServiceServer(srv, svc)
+
+	ts := httptest.NewServer(New(srv, origins))
+	t.Cleanup(func() {
+		svc.Close()
+		ts.Close()
+		srv.Stop()
+	})
+	return ts
+}
+
+// frame encodes msg as a gRPC-Web data frame.
+func frame(t *testing.T, msg proto.Message) []byte {
+	t.Helper()
+
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	buf := make([]byte, 5, 5+len(data))
+	binary.BigEndian.PutUint32(buf[1:], uint32(len(data)))
+	return append(buf, data...)
+}
+
+func TestGRPCWeb_Unary(t *testing.T) {
+	ts := newTestServer(t)
+
+	req, _ := http.NewRequest("POST", ts.URL+"/ticket.TicketService/ViewUserReceipt",
+		bytes.NewReader(frame(t, &ticket.ViewUserReceiptRequest{})))
+	req.Header.Set("Content-Type", "application/grpc-web+proto")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	// No JWT, so the call reaches the service and is rejected there
+	if got := resp.Header.Get("Grpc-Status"); got != "16" {
+		t.Errorf("Expected grpc-status 16 (Unauthenticated), got %q", got)
+	}
+}
+
+func TestGRPCWeb_ServerStream(t *testing.T) {
+	ts := newTestServer(t)
+
+	req, _ := http.NewRequest("POST", ts.URL+"/ticket.TicketService/WatchOccupancy",
+		bytes.NewReader(frame(t, &ticket.WatchOccupancyRequest{})))
+	req.Header.Set("Content-Type", "application/grpc-web+proto")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer resp.Body.Close()
+
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(resp.Body, header); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if header[0] != 0 {
+		t.Fatalf("Expected a data frame, got flags %x", header[0])
+	}
+	data := make([]byte, binary.BigEndian.Uint32(header[1:]))
+	if _, err := io.ReadFull(resp.Body, data); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	update := &ticket.OccupancyUpdate{}
+	if err := proto.Unmarshal(data, update); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(update.Sections) != 2 {
+		t.Errorf("Expected 2 sections, got %d", len(update.Sections))
+	}
+}
+
+func TestGRPCWeb_CORS(t *testing.T) {
+	ts := newTestServer(t, "https://seats.example.com")
+
+	tests := []struct {
+		origin string
+		allow  bool
+	}{
+		{"https://seats.example.com", true},
+		{"https://evil.example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			req, _ := http.NewRequest("OPTIONS", ts.URL+"/ticket.TicketService/ViewUserReceipt", nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", "POST")
+			req.Header.Set("Access-Control-Request-Headers", "authorization,content-type,x-grpc-web")
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			resp.Body.Close()
+
+			got := resp.Header.Get("Access-Control-Allow-Origin")
+			if tt.allow && got != tt.origin {
+				t.Errorf("Expected origin to be allowed, got %q", got)
+			}
+			if !tt.allow && got != "" {
+				t.Errorf("Expected origin to be rejected, got %q", got)
+			}
+		})
+	}
+}
+
+func TestGRPCWeb_NotGRPCWeb(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp, err := http.Get(ts.URL + "/ticket.TicketService/ViewUserReceipt")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestGRPCWeb_Text(t *testing.T) {
+	ts := newTestServer(t)
+
+	body := base64.StdEncoding.EncodeToString(frame(t, &ticket.ViewUserReceiptRequest{}))
+	req, _ := http.NewRequest("POST", ts.URL+"/ticket.TicketService/ViewUserReceipt", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/grpc-web-text")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if got := resp.Header.Get("Content-Type"); !strings.HasPrefix(got, "application/grpc-web-text") {
+		t.Errorf("Expected a grpc-web-text response, got %q", got)
+	}
+	if got := resp.Header.Get("Grpc-Status"); got != "16" {
+		t.Errorf("Expected grpc-status 16 (Unauthenticated), got %q", got)
+	}
+}
@@ -0,0 +1,124 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// RequestIDHeader carries the request id in both directions. A caller may
+// send one to correlate logs across services; otherwise one is generated.
+const RequestIDHeader = "x-request-id"
+
+// NewLogger returns a JSON logger writing to w at the named level.
+func NewLogger(w io.Writer, level string) (*slog.Logger, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("unknown log level %q", level)
+	}
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: l})), nil
+}
+
+type requestIDKey struct{}
+
+// RequestID returns the id assigned to the current call, or "" outside one.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// UnaryServerInterceptor assigns a request id, returns it in the response
+// header and logs one line per call with the redacted request.
+func UnaryServerInterceptor(logger *slog.Logger, redactor Redactor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx = withRequestID(ctx)
+
+		resp, err := handler(ctx, req)
+
+		attrs := callAttrs(ctx, info.FullMethod, start, err)
+		if msg, ok := req.(proto.Message); ok {
+			attrs = append(attrs, slog.Any("request", redactor.Message(msg)))
+		}
+		logger.LogAttrs(ctx, levelFor(err), "rpc", attrs...)
+		return resp, err
+	}
+}
+
+func StreamServerInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := withRequestID(ss.Context())
+
+		err := handler(srv, &loggedStream{ServerStream: ss, ctx: ctx})
+
+		logger.LogAttrs(ctx, levelFor(err), "rpc", callAttrs(ctx, info.FullMethod, start, err)...)
+		return err
+	}
+}
+
+func withRequestID(ctx context.Context) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDHeader); len(values) > 0 {
+			id = strings.TrimSpace(values[0])
+		}
+	}
+	// Bound caller-supplied ids so they cannot flood the logs
+	if id == "" || len(id) > 128 {
+		id = newRequestID()
+	}
+
+	grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, id))
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func callAttrs(ctx context.Context, method string, start time.Time, err error) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("request_id", RequestID(ctx)),
+		slog.String("method", method),
+		slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+		slog.String("code", status.Code(err).String()),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+	}
+	return attrs
+}
+
+// levelFor logs server-side failures as errors and everything else,
+// including client errors such as NotFound, at info.
+func levelFor(err error) slog.Level {
+	switch status.Code(err) {
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type loggedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *loggedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,132 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRedactor_Policies(t *testing.T) {
+	req := &ticket.PurchaseTicketRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com"}
+
+	tests := []struct {
+		policy string
+		want   string
+	}{
+		{RedactionNone, `"email":"john@example.com"`},
+		{RedactionRedact, `"email":"[REDACTED]"`},
+		{RedactionHash, `"email":"sha256:`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			r, err := NewRedactor(tt.policy)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			got := string(r.Message(req))
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("Expected %s in %s", tt.want, got)
+			}
+			if tt.policy != RedactionNone && (strings.Contains(got, "john") || strings.Contains(got, "Doe")) {
+				t.Errorf("Expected personal data to be removed, got %s", got)
+			}
+		})
+	}
+
+	// The caller's message must not be modified
+	if req.Email != "john@example.com" {
+		t.Errorf("Expected request to be left intact, got %s", req.Email)
+	}
+}
+
+func TestRedactor_HashIsStable(t *testing.T) {
+	r, _ := NewRedactor(RedactionHash)
+
+	if r.Value("John@Example.com") != r.Value("john@example.com") {
+		t.Error("Expected the same email to hash the same regardless of case")
+	}
+	if r.Value("a@example.com") == r.Value("b@example.com") {
+		t.Error("Expected different emails to hash differently")
+	}
+}
+
+func TestRedactor_NestedAndSecretFields(t *testing.T) {
+	r, _ := NewRedactor(RedactionNone)
+
+	got := string(r.Message(&ticket.VerifyPurchaseRequest{Email: "john@example.com", Code: "123456"}))
+	if strings.Contains(got, "123456") {
+		t.Errorf("Expected verification code to be removed under every policy, got %s", got)
+	}
+
+	r, _ = NewRedactor(RedactionRedact)
+	got = string(r.Message(&ticket.BulkApplyRequest{Operations: []*ticket.BulkOperation{
+		{Operation: &ticket.BulkOperation_Remove{Remove: &ticket.RemoveOperation{Email: "a@example.com"}}},
+	}}))
+	if strings.Contains(got, "a@example.com") {
+		t.Errorf("Expected emails in repeated messages to be redacted, got %s", got)
+	}
+
+	got = string(r.Message(&ticket.RequestSeatSwapRequest{CounterpartyEmail: "b@example.com"}))
+	if strings.Contains(got, "b@example.com") {
+		t.Errorf("Expected *_email fields to be redacted, got %s", got)
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(&buf, "info")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	redactor, _ := NewRedactor(RedactionRedact)
+	interceptor := UnaryServerInterceptor(logger, redactor)
+	info := &grpc.UnaryServerInfo{FullMethod: ticket.TicketService_PurchaseTicket_FullMethodName}
+	req := &ticket.PurchaseTicketRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com"}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDHeader, "req-123"))
+	var seen string
+	interceptor(ctx, req, info, func(ctx context.Context, _ any) (any, error) {
+		seen = RequestID(ctx)
+		return nil, status.Error(codes.AlreadyExists, "user already has a ticket")
+	})
+
+	if seen != "req-123" {
+		t.Errorf("Expected caller's request id, got %q", seen)
+	}
+
+	var line struct {
+		RequestID string          `json:"request_id"`
+		Method    string          `json:"method"`
+		Code      string          `json:"code"`
+		Request   json.RawMessage `json:"request"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("Expected a JSON log line, got %q: %v", buf.String(), err)
+	}
+	if line.RequestID != "req-123" || line.Method != info.FullMethod || line.Code != "AlreadyExists" {
+		t.Errorf("Unexpected log line: %s", buf.String())
+	}
+	if strings.Contains(buf.String(), "john@example.com") {
+		t.Errorf("Expected email to be redacted, got %s", buf.String())
+	}
+
+	// Without an incoming id one is generated
+	buf.Reset()
+	interceptor(context.Background(), req, info, func(ctx context.Context, _ any) (any, error) {
+		seen = RequestID(ctx)
+		return nil, nil
+	})
+	if seen == "" || !strings.Contains(buf.String(), seen) {
+		t.Errorf("Expected a generated request id to be logged, got %q in %s", seen, buf.String())
+	}
+}
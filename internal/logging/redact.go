@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Redaction policies for personal data in request logs.
+const (
+	RedactionNone   = "none"   // log emails and names as sent
+	RedactionRedact = "redact" // replace them with a fixed marker
+	RedactionHash   = "hash"   // replace them with a short SHA-256 digest, so one passenger's calls can still be correlated
+)
+
+const redacted = "[REDACTED]"
+
+// Redactor rewrites personal fields of request messages before they are logged.
+type Redactor struct {
+	policy string
+}
+
+func NewRedactor(policy string) (Redactor, error) {
+	switch policy {
+	case RedactionNone, RedactionRedact, RedactionHash:
+		return Redactor{policy: policy}, nil
+	default:
+		return Redactor{}, fmt.Errorf("unknown redaction policy %q", policy)
+	}
+}
+
+// Value returns v with the policy applied.
+func (r Redactor) Value(v string) string {
+	if v == "" {
+		return v
+	}
+	switch r.policy {
+	case RedactionNone:
+		return v
+	case RedactionHash:
+		sum := sha256.Sum256([]byte(strings.ToLower(v)))
+		return "sha256:" + hex.EncodeToString(sum[:6])
+	default:
+		return redacted
+	}
+}
+
+// Message renders msg as JSON with personal fields rewritten. Secrets such
+// as verification codes are always removed, whatever the policy.
+func (r Redactor) Message(msg proto.Message) json.RawMessage {
+	clone := proto.Clone(msg)
+	r.walk(clone.ProtoReflect())
+
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(clone)
+	if err != nil {
+		return json.RawMessage(`{}`)
+	}
+	return json.RawMessage(data)
+}
+
+func (r Redactor) walk(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.Kind() == protoreflect.StringKind && !fd.IsList() && !fd.IsMap():
+			name := string(fd.Name())
+			switch {
+			case isSecretField(name):
+				m.Set(fd, protoreflect.ValueOfString(redacted))
+			case isPersonalField(name):
+				m.Set(fd, protoreflect.ValueOfString(r.Value(v.String())))
+			}
+		case fd.Kind() == protoreflect.MessageKind && fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				r.walk(list.Get(i).Message())
+			}
+		case fd.Kind() == protoreflect.MessageKind && !fd.IsMap():
+			r.walk(v.Message())
+		}
+		return true
+	})
+}
+
+func isPersonalField(name string) bool {
+	return name == "email" || strings.HasSuffix(name, "_email") ||
+		name == "first_name" || name == "last_name" || name == "purchased_by"
+}
+
+func isSecretField(name string) bool {
+	return name == "code"
+}
@@ -0,0 +1,197 @@
+// Package manifest renders the passenger list conductors print, as CSV, JSON
+// Lines or PDF.
+package manifest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloudbees/train-ticket-service/internal/model"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+	FormatPDF   = "pdf"
+)
+
+// Fields a manifest can show, one column each.
+const (
+	FieldSection     = "section"
+	FieldSeatNumber  = "seat_number"
+	FieldFirstName   = "first_name"
+	FieldLastName    = "last_name"
+	FieldEmail       = "email"
+	FieldPricePaid   = "price_paid"
+	FieldFrom        = "from"
+	FieldTo          = "to"
+	FieldPurchasedBy = "purchased_by"
+)
+
+var (
+	AllFields = []string{
+		FieldSection, FieldSeatNumber, FieldFirstName, FieldLastName, FieldEmail,
+		FieldPricePaid, FieldFrom, FieldTo, FieldPurchasedBy,
+	}
+	DefaultFields = []string{FieldSection, FieldSeatNumber, FieldFirstName, FieldLastName, FieldEmail}
+)
+
+var contentTypes = map[string]string{
+	FormatCSV:   "text/csv; charset=utf-8",
+	FormatJSONL: "application/x-ndjson",
+	FormatPDF:   "application/pdf",
+}
+
+type Options struct {
+	Format string
+	// Fields are the columns, in order.
+	Fields     []string
+	MaskEmails bool
+	// Title heads each PDF page; CSV and JSON Lines have no title.
+	Title string
+}
+
+// ContentType returns the MIME type of format.
+func ContentType(format string) (string, error) {
+	ct, ok := contentTypes[format]
+	if !ok {
+		return "", fmt.Errorf("must be %q, %q or %q", FormatCSV, FormatJSONL, FormatPDF)
+	}
+	return ct, nil
+}
+
+// CheckFields reports the first field a manifest cannot show.
+func CheckFields(fields []string) error {
+	for _, f := range fields {
+		if !slices.Contains(AllFields, f) {
+			return fmt.Errorf("has unknown field %q, want one of %s", f, strings.Join(AllFields, ", "))
+		}
+	}
+	return nil
+}
+
+// Write renders tickets, in the order given, to w. CSV and JSON Lines are
+// written a row at a time; a PDF is laid out in full first.
+func Write(w io.Writer, o Options, tickets []*model.Ticket) error {
+	switch o.Format {
+	case FormatCSV:
+		return writeCSV(w, o, tickets)
+	case FormatJSONL:
+		return writeJSONL(w, o, tickets)
+	case FormatPDF:
+		return writePDF(w, o, tickets)
+	default:
+		return fmt.Errorf("unknown manifest format %q", o.Format)
+	}
+}
+
+// MaskEmail keeps the first character of the local part and the domain,
+// e.g. j***@example.com.
+func MaskEmail(email string) string {
+	at := strings.LastIndexByte(email, '@')
+	if at < 1 {
+		return "***"
+	}
+	_, size := utf8.DecodeRuneInString(email)
+	return email[:size] + "***" + email[at:]
+}
+
+// value returns field of t; numbers stay numbers so JSON Lines keeps their type.
+func value(t *model.Ticket, field string, mask bool) any {
+	switch field {
+	case FieldSection:
+		return t.Seat.Section
+	case FieldSeatNumber:
+		return t.Seat.SeatNumber
+	case FieldFirstName:
+		return t.User.FirstName
+	case FieldLastName:
+		return t.User.LastName
+	case FieldEmail:
+		if mask {
+			return MaskEmail(t.User.Email)
+		}
+		return t.User.Email
+	case FieldPricePaid:
+		return t.PricePaid
+	case FieldFrom:
+		return t.From
+	case FieldTo:
+		return t.To
+	case FieldPurchasedBy:
+		if mask && t.PurchasedBy != "" {
+			return MaskEmail(t.PurchasedBy)
+		}
+		return t.PurchasedBy
+	default:
+		return ""
+	}
+}
+
+func text(t *model.Ticket, field string, mask bool) string {
+	switch v := value(t, field, mask).(type) {
+	case int32:
+		return strconv.Itoa(int(v))
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func writeCSV(w io.Writer, o Options, tickets []*model.Ticket) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(o.Fields); err != nil {
+		return err
+	}
+
+	record := make([]string, len(o.Fields))
+	for _, t := range tickets {
+		for i, f := range o.Fields {
+			record[i] = csvSafe(text(t, f, o.MaskEmails))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvSafe stops a spreadsheet from running a cell such as "=cmd@x.com" as a formula.
+func csvSafe(s string) string {
+	if s != "" && strings.ContainsRune("=+-@", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func writeJSONL(w io.Writer, o Options, tickets []*model.Ticket) error {
+	var line []byte
+	for _, t := range tickets {
+		// Built by hand so keys keep the requested order
+		line = append(line[:0], '{')
+		for i, f := range o.Fields {
+			if i > 0 {
+				line = append(line, ',')
+			}
+			key, _ := json.Marshal(f)
+			val, err := json.Marshal(value(t, f, o.MaskEmails))
+			if err != nil {
+				return err
+			}
+			line = append(append(append(line, key...), ':'), val...)
+		}
+		line = append(line, '}', '\n')
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
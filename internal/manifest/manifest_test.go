@@ -0,0 +1,118 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cloudbees/train-ticket-service/internal/model"
+)
+
+func testTickets(n int) []*model.Ticket {
+	tickets := make([]*model.Ticket, n)
+	for i := range tickets {
+		tickets[i] = &model.Ticket{
+			From:      "London",
+			To:        "France",
+			User:      model.User{FirstName: "Zoë", LastName: fmt.Sprintf("User%d", i+1), Email: fmt.Sprintf("user%d@example.com", i+1)},
+			PricePaid: 2000,
+			Seat:      model.Seat{Section: "A", SeatNumber: int32(i + 1)},
+		}
+	}
+	return tickets
+}
+
+func TestWriteCSV(t *testing.T) {
+	tickets := testTickets(2)
+	tickets[1].User.Email = "=cmd@example.com"
+
+	var buf bytes.Buffer
+	err := Write(&buf, Options{Format: FormatCSV, Fields: []string{FieldSeatNumber, FieldEmail}}, tickets)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	want := "seat_number,email\n1,user1@example.com\n2,'=cmd@example.com\n"
+	if buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestWriteJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(&buf, Options{Format: FormatJSONL, Fields: []string{FieldEmail, FieldSeatNumber, FieldFirstName}, MaskEmails: true}, testTickets(1))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	want := `{"email":"u***@example.com","seat_number":1,"first_name":"Zoë"}` + "\n"
+	if buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestWritePDF(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(&buf, Options{Format: FormatPDF, Fields: DefaultFields, Title: "London to France"}, testTickets(60))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	pdf := buf.Bytes()
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatal("Expected a PDF header and trailer")
+	}
+	if !bytes.Contains(pdf, []byte("/Count 2")) {
+		t.Error("Expected 60 passengers to take two pages")
+	}
+	if !bytes.Contains(pdf, []byte("Zo\xeb")) {
+		t.Error("Expected names in WinAnsi encoding")
+	}
+
+	// Every cross-reference entry must point at its object
+	xref := bytes.LastIndex(pdf, []byte("\nxref\n")) + 1
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(pdf[xref:], -1)
+	if len(entries) != 3+2*2 {
+		t.Fatalf("Expected 7 objects, got %d", len(entries))
+	}
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		if !bytes.HasPrefix(pdf[off:], []byte(fmt.Sprintf("%d 0 obj", i+1))) {
+			t.Errorf("Expected object %d at offset %d", i+1, off)
+		}
+	}
+	startxref := bytes.LastIndex(pdf, []byte("startxref\n"))
+	if got := strings.Fields(string(pdf[startxref:]))[1]; got != strconv.Itoa(xref) {
+		t.Errorf("Expected startxref %d, got %s", xref, got)
+	}
+}
+
+func TestCheckFields(t *testing.T) {
+	if err := CheckFields(AllFields); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if err := CheckFields([]string{FieldEmail, "phone"}); err == nil {
+		t.Error("Expected an error for an unknown field")
+	}
+	if _, err := ContentType("xml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestMaskEmail(t *testing.T) {
+	tests := []struct {
+		email, want string
+	}{
+		{"jane@example.com", "j***@example.com"},
+		{"élodie@example.fr", "é***@example.fr"},
+		{"not-an-email", "***"},
+	}
+	for _, tt := range tests {
+		if got := MaskEmail(tt.email); got != tt.want {
+			t.Errorf("MaskEmail(%q) = %q, want %q", tt.email, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloudbees/train-ticket-service/internal/model"
+	"github.com/cloudbees/train-ticket-service/internal/pdf"
+)
+
+// The PDF is landscape A4 in 9pt Courier, a built-in font, so it needs no
+// embedded fonts and columns line up by padding.
+const (
+	pageWidth    = 842
+	pageHeight   = 595
+	margin       = 40
+	fontSize     = 9
+	leading      = 12
+	linesPerPage = (pageHeight - 2*margin) / leading
+	// maxCellWidth truncates long values rather than wrapping them.
+	maxCellWidth = 40
+)
+
+func writePDF(w io.Writer, o Options, tickets []*model.Ticket) error {
+	rows := make([][]string, 0, len(tickets)+1)
+	rows = append(rows, o.Fields)
+	for _, t := range tickets {
+		row := make([]string, len(o.Fields))
+		for i, f := range o.Fields {
+			row[i] = text(t, f, o.MaskEmails)
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(o.Fields))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = min(max(widths[i], utf8.RuneCountInString(cell)), maxCellWidth)
+		}
+	}
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = formatRow(row, widths)
+	}
+	header, body := lines[0], lines[1:]
+
+	// Each page repeats the title and column header
+	perPage := linesPerPage - 4
+	var pages [][]string
+	for len(body) > 0 || len(pages) == 0 {
+		n := min(perPage, len(body))
+		pages = append(pages, body[:n])
+		body = body[n:]
+	}
+
+	doc := pdf.NewWriter(w)
+	pageRefs := make([]string, len(pages))
+	for i := range pages {
+		// Objects 1-3 are the catalog, page tree and font; each page then takes two
+		pageRefs[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	doc.Object("<< /Type /Catalog /Pages 2 0 R >>")
+	doc.Object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(pageRefs, " "), len(pages)))
+	doc.Object(pdf.Courier)
+
+	for i, page := range pages {
+		var content bytes.Buffer
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d TL %d %d Td\n", fontSize, leading, margin, pageHeight-margin)
+		for _, line := range append([]string{o.Title, fmt.Sprintf("Passengers: %d, page %d of %d", len(tickets), i+1, len(pages)), "", header}, page...) {
+			fmt.Fprintf(&content, "(%s) Tj T*\n", pdf.String(line))
+		}
+		content.WriteString("ET\n")
+
+		doc.Object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 5+2*i))
+		doc.Stream(content.Bytes())
+	}
+	return doc.Finish()
+}
+
+func formatRow(row []string, widths []int) string {
+	cells := make([]string, len(row))
+	for i, cell := range row {
+		if utf8.RuneCountInString(cell) > widths[i] {
+			cell = string([]rune(cell)[:widths[i]-1]) + "~"
+		}
+		cells[i] = cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+	}
+	return strings.TrimRight(strings.Join(cells, "  "), " ")
+}
@@ -0,0 +1,176 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/cloudbees/train-ticket-service/internal/store"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Purchase kinds for RecordPurchase.
+const (
+	PurchaseSelf     = "self"      // authenticated purchase
+	PurchaseVerified = "verified"  // anonymous purchase confirmed by email
+	PurchaseOnBehalf = "on_behalf" // admin purchase for a passenger
+	PurchaseImported = "imported"  // booking loaded by ImportBookings
+)
+
+// Metrics owns the Prometheus registry served on /metrics. RPC metrics come
+// from the interceptors, business counters from the service, and occupancy
+// gauges are read from the store on each scrape.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	rpcHandled  *prometheus.CounterVec
+	rpcDuration *prometheus.HistogramVec
+
+	purchases       *prometheus.CounterVec
+	removals        prometheus.Counter
+	modifications   prometheus.Counter
+	paymentFailures prometheus.Counter
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		rpcHandled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "RPCs completed on the server, by method and status code.",
+		}, []string{"grpc_method", "grpc_code"}),
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Time taken to handle an RPC, by method and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"grpc_method", "grpc_code"}),
+		purchases: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ticket_purchases_total",
+			Help: "Confirmed ticket purchases, by kind.",
+		}, []string{"kind"}),
+		removals: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "ticket_removals_total",
+			Help: "Passengers removed from the train.",
+		}),
+		modifications: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "ticket_seat_modifications_total",
+			Help: "Seat changes, including swaps and bulk moves.",
+		}),
+		paymentFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "ticket_payment_failures_total",
+			Help: "Failed ticket payments.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.rpcHandled,
+		m.rpcDuration,
+		m.purchases,
+		m.removals,
+		m.modifications,
+		m.paymentFailures,
+	)
+
+	return m
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// WatchStore exports the occupancy of s, labelled with the train name.
+func (m *Metrics) WatchStore(train string, s *store.Store) {
+	m.registry.MustRegister(newOccupancyCollector(train, s))
+}
+
+func (m *Metrics) RecordPurchase(kind string) {
+	m.RecordPurchases(kind, 1)
+}
+
+func (m *Metrics) RecordPurchases(kind string, n int) {
+	m.purchases.WithLabelValues(kind).Add(float64(n))
+}
+
+func (m *Metrics) RecordRemovals(n int) {
+	m.removals.Add(float64(n))
+}
+
+func (m *Metrics) RecordModifications(n int) {
+	m.modifications.Add(float64(n))
+}
+
+// RecordPaymentFailure counts a declined or failed payment. Tickets are not
+// charged yet, so nothing calls it and the counter stays at zero until a
+// payment provider is integrated.
+func (m *Metrics) RecordPaymentFailure() {
+	m.paymentFailures.Inc()
+}
+
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.observeRPC(info.FullMethod, err, time.Since(start))
+		return resp, err
+	}
+}
+
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		m.observeRPC(info.FullMethod, err, time.Since(start))
+		return err
+	}
+}
+
+func (m *Metrics) observeRPC(method string, err error, elapsed time.Duration) {
+	code := status.Code(err).String()
+	m.rpcHandled.WithLabelValues(method, code).Inc()
+	m.rpcDuration.WithLabelValues(method, code).Observe(elapsed.Seconds())
+}
+
+// occupancyCollector reads the store on every scrape, so the gauges can never
+// drift from the bookings they describe.
+type occupancyCollector struct {
+	store *store.Store
+
+	occupied *prometheus.Desc
+	holds    *prometheus.Desc
+	waitlist *prometheus.Desc
+}
+
+func newOccupancyCollector(train string, s *store.Store) *occupancyCollector {
+	labels := prometheus.Labels{"train": train}
+	return &occupancyCollector{
+		store: s,
+		occupied: prometheus.NewDesc("ticket_seats_occupied",
+			"Seats held by confirmed tickets, by section.", []string{"section"}, labels),
+		holds: prometheus.NewDesc("ticket_holds",
+			"Seats held by purchases awaiting email verification.", nil, labels),
+		waitlist: prometheus.NewDesc("ticket_waitlist_length",
+			"Passengers waiting for a seat after a section was decommissioned.", nil, labels),
+	}
+}
+
+func (c *occupancyCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.occupied
+	ch <- c.holds
+	ch <- c.waitlist
+}
+
+func (c *occupancyCollector) Collect(ch chan<- prometheus.Metric) {
+	occ := c.store.Occupancy()
+	for section, n := range occ.Occupied {
+		ch <- prometheus.MustNewConstMetric(c.occupied, prometheus.GaugeValue, float64(n), section)
+	}
+	ch <- prometheus.MustNewConstMetric(c.holds, prometheus.GaugeValue, float64(occ.Holds))
+	ch <- prometheus.MustNewConstMetric(c.waitlist, prometheus.GaugeValue, float64(occ.Waitlist))
+}
@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cloudbees/train-ticket-service/internal/model"
+	"github.com/cloudbees/train-ticket-service/internal/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	m := New()
+	interceptor := m.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/ticket.TicketService/PurchaseTicket"}
+
+	interceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return nil, nil
+	})
+	interceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return nil, status.Error(codes.ResourceExhausted, "train is full")
+	})
+
+	body := scrape(t, m)
+	for _, want := range []string{
+		`grpc_server_handled_total{grpc_code="OK",grpc_method="/ticket.TicketService/PurchaseTicket"} 1`,
+		`grpc_server_handled_total{grpc_code="ResourceExhausted",grpc_method="/ticket.TicketService/PurchaseTicket"} 1`,
+		`grpc_server_handling_seconds_count{grpc_code="OK",grpc_method="/ticket.TicketService/PurchaseTicket"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected metrics to contain %s", want)
+		}
+	}
+}
+
+func TestWatchStore(t *testing.T) {
+	m := New()
+	s := store.NewStore()
+	m.WatchStore("London-France", s)
+
+	s.PurchaseTicket(context.Background(), model.User{Email: "a@example.com"}, "London", "France", 2000)
+	s.DecommissionSection(context.Background(), "B", model.OverflowWaitlist)
+
+	body := scrape(t, m)
+	for _, want := range []string{
+		`ticket_seats_occupied{section="A",train="London-France"} 1`,
+		`ticket_seats_occupied{section="B",train="London-France"} 0`,
+		`ticket_holds{train="London-France"} 0`,
+		`ticket_waitlist_length{train="London-France"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected metrics to contain %s", want)
+		}
+	}
+}
+
+func TestBusinessCounters(t *testing.T) {
+	m := New()
+	if body := scrape(t, m); !strings.Contains(body, "ticket_payment_failures_total 0") {
+		t.Error("Expected the payment failure counter to be exported at zero")
+	}
+
+	m.RecordPurchase(PurchaseSelf)
+	m.RecordPurchase(PurchaseOnBehalf)
+	m.RecordRemovals(2)
+	m.RecordModifications(0)
+	m.RecordPurchases(PurchaseImported, 3)
+	m.RecordPaymentFailure()
+
+	body := scrape(t, m)
+	for _, want := range []string{
+		`ticket_purchases_total{kind="self"} 1`,
+		`ticket_purchases_total{kind="on_behalf"} 1`,
+		`ticket_removals_total 2`,
+		`ticket_seat_modifications_total 0`,
+		`ticket_purchases_total{kind="imported"} 3`,
+		`ticket_payment_failures_total 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected metrics to contain %s", want)
+		}
+	}
+}
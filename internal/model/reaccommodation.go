@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// Overflow policies for passengers who cannot be reseated when a section is decommissioned.
+const (
+	OverflowWaitlist = "waitlist"
+	OverflowRefund   = "refund"
+)
+
+type SeatMove struct {
+	User User
+	From Seat
+	To   Seat
+}
+
+type DisplacedPassenger struct {
+	User        User
+	From        Seat
+	RefundCents int32
+}
+
+// ReaccommodationReport describes what happened to each passenger of a decommissioned section.
+type ReaccommodationReport struct {
+	Section    string
+	Moved      []SeatMove
+	Waitlisted []DisplacedPassenger
+	Refunded   []DisplacedPassenger
+}
+
+// WaitlistEntry keeps the ticket a passenger lost so it can be reissued when a seat frees up.
+type WaitlistEntry struct {
+	Ticket Ticket
+	Since  time.Time
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// Reasons a ticket token is revoked.
+const (
+	RevokedSeatChanged = "seat_changed"
+	RevokedRemoved     = "removed"
+)
+
+// Revocation invalidates the tokens of a ticket issued at Revision or
+// earlier. Seq orders revocations so readers can fetch only new ones.
+type Revocation struct {
+	Seq       int64
+	TicketID  string
+	Revision  int32
+	Reason    string
+	RevokedAt time.Time
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+const (
+	SwapStatusPending   = "pending"
+	SwapStatusCompleted = "completed"
+)
+
+type SeatSwap struct {
+	ID                string
+	RequesterEmail    string
+	RequesterSeat     Seat
+	CounterpartyEmail string
+	CounterpartySeat  Seat
+	Status            string
+	CreatedAt         time.Time
+}
@@ -1,5 +1,12 @@
 package model
 
+import "time"
+
+const (
+	TicketStatusConfirmed           = "confirmed"
+	TicketStatusPendingVerification = "pending_verification"
+)
+
 type User struct {
 	FirstName string
 	LastName  string
@@ -8,22 +15,122 @@ type User struct {
 
 type Seat struct {
 	Section    string
-	SeatNumber int32 
+	SeatNumber int32
 }
 
 type Ticket struct {
-	From      string 
-	To        string 
+	// ID names the ticket in its signed token and in revocations.
+	ID string
+	// Revision goes up each time the ticket loses its seat, so tokens issued
+	// for an earlier seat can be revoked.
+	Revision int32
+
+	From      string
+	To        string
 	User      User
-	PricePaid int32 
+	PricePaid int32
 	Seat      Seat
+
+	// PurchasedBy is the email of the admin who booked the ticket, empty for self-service purchases.
+	PurchasedBy string
+
+	Status string
+	// HoldExpiresAt is when an unverified ticket releases its seat; zero for confirmed tickets.
+	HoldExpiresAt time.Time
+}
+
+func (t *Ticket) IsPending() bool {
+	return t.Status == TicketStatusPendingVerification
+}
+
+func (t *Ticket) HoldExpired(now time.Time) bool {
+	return t.IsPending() && !now.Before(t.HoldExpiresAt)
+}
+
+// Layout describes the sections of the train and how many seats each has.
+type Layout struct {
+	Sections        []string
+	SeatsPerSection int32
+	// SeatsPerRow is how many seats sit side by side, split by a central
+	// aisle. Zero puts the whole section in one row.
+	SeatsPerRow int32
+}
+
+func DefaultLayout() Layout {
+	return Layout{
+		Sections:        []string{"A", "B"},
+		SeatsPerSection: 10,
+		SeatsPerRow:     4,
+	}
+}
+
+func (l Layout) HasSection(section string) bool {
+	for _, s := range l.Sections {
+		if s == section {
+			return true
+		}
+	}
+	return false
 }
 
+func (l Layout) HasSeatNumber(seatNumber int32) bool {
+	return seatNumber >= 1 && seatNumber <= l.SeatsPerSection
+}
+
+// Seat attributes derived from where a seat sits in its row.
+const (
+	SeatAttributeWindow = "window"
+	SeatAttributeAisle  = "aisle"
+	SeatAttributeMiddle = "middle"
+)
+
+// RowWidth returns the number of seats in a full row.
+func (l Layout) RowWidth() int32 {
+	if l.SeatsPerRow > 0 {
+		return l.SeatsPerRow
+	}
+	return l.SeatsPerSection
+}
+
+// Row returns the 1-based row of seatNumber.
+func (l Layout) Row(seatNumber int32) int32 {
+	return (seatNumber-1)/l.RowWidth() + 1
+}
+
+// AisleAfter returns the position in a row the aisle follows, 0 for rows too
+// narrow to have one.
+func (l Layout) AisleAfter() int32 {
+	if l.RowWidth() < 3 {
+		return 0
+	}
+	return (l.RowWidth() + 1) / 2
+}
+
+// SeatAttribute reports whether seatNumber is a window, aisle or middle seat.
+// Window wins for seats that are both.
+func (l Layout) SeatAttribute(seatNumber int32) string {
+	width := l.RowWidth()
+	pos := (seatNumber-1)%width + 1
+	switch aisle := l.AisleAfter(); {
+	case pos == 1 || pos == width:
+		return SeatAttributeWindow
+	case pos == aisle || pos == aisle+1:
+		return SeatAttributeAisle
+	default:
+		return SeatAttributeMiddle
+	}
+}
+
+func (l Layout) Capacity() int {
+	return len(l.Sections) * int(l.SeatsPerSection)
+}
+
+// IsValidSection reports whether section exists in the default layout.
 func IsValidSection(section string) bool {
-	return section == "A" || section == "B"
+	return DefaultLayout().HasSection(section)
 }
 
+// IsValidSeatNumber reports whether seatNumber exists in the default layout.
 func IsValidSeatNumber(seatNumber int32) bool {
-	return seatNumber >= 1 && seatNumber <= 10
+	return DefaultLayout().HasSeatNumber(seatNumber)
 }
-
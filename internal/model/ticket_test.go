@@ -43,3 +43,33 @@ func TestIsValidSeatNumber(t *testing.T) {
 	}
 }
 
+func TestSeatAttribute(t *testing.T) {
+	tests := []struct {
+		seatsPerRow int32
+		seatNumber  int32
+		wantRow     int32
+		want        string
+	}{
+		{4, 1, 1, SeatAttributeWindow},
+		{4, 2, 1, SeatAttributeAisle},
+		{4, 3, 1, SeatAttributeAisle},
+		{4, 4, 1, SeatAttributeWindow},
+		{4, 5, 2, SeatAttributeWindow},
+		{4, 10, 3, SeatAttributeAisle},
+		{5, 2, 1, SeatAttributeMiddle},
+		{5, 3, 1, SeatAttributeAisle},
+		{5, 4, 1, SeatAttributeAisle},
+		{2, 2, 1, SeatAttributeWindow},
+		{0, 7, 1, SeatAttributeMiddle},
+	}
+
+	for _, tt := range tests {
+		l := Layout{Sections: []string{"A"}, SeatsPerSection: 10, SeatsPerRow: tt.seatsPerRow}
+		if got := l.Row(tt.seatNumber); got != tt.wantRow {
+			t.Errorf("Row(%d) with %d per row = %d, want %d", tt.seatNumber, tt.seatsPerRow, got, tt.wantRow)
+		}
+		if got := l.SeatAttribute(tt.seatNumber); got != tt.want {
+			t.Errorf("SeatAttribute(%d) with %d per row = %q, want %q", tt.seatNumber, tt.seatsPerRow, got, tt.want)
+		}
+	}
+}
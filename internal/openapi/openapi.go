@@ -0,0 +1,311 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Document is the subset of OpenAPI 3.0 the generator emits.
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Security   []map[string][]string           `json:"security"`
+	Paths      map[string]map[string]Operation `json:"paths"`
+	Components Components                      `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Tags        []string            `json:"tags"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+const (
+	bearerAuth = "bearerAuth"
+	statusName = "google.rpc.Status"
+	jsonType   = "application/json"
+	ndjsonType = "application/x-ndjson"
+	// httpBodyName is returned for files: the gateway writes its data as the
+	// raw response body, with its content type.
+	httpBodyName = "google.api.HttpBody"
+)
+
+var pathParam = regexp.MustCompile(`\{([^}=]+)\}`)
+
+// Spec returns the TicketService document as indented JSON. It is what the
+// gateway serves at /openapi.json and what docs/openapi.json must match.
+func Spec() ([]byte, error) {
+	doc, err := Generate(ticket.File_api_ticket_proto.Services().ByName("TicketService"), "v1")
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Generate builds the OpenAPI document for service from its google.api.http
+// annotations. Every RPC must be annotated, so a new RPC cannot silently go
+// missing from the HTTP API.
+func Generate(service protoreflect.ServiceDescriptor, version string) (*Document, error) {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: string(service.FullName()), Version: version},
+		// The bearer token is optional: anonymous purchases and verification need none
+		Security: []map[string][]string{{}, {bearerAuth: {}}},
+		Paths:    make(map[string]map[string]Operation),
+		Components: Components{
+			Schemas: map[string]*Schema{
+				statusName: {
+					Type: "object",
+					Properties: map[string]*Schema{
+						"code":    {Type: "integer", Format: "int32"},
+						"message": {Type: "string"},
+						"details": {Type: "array", Items: &Schema{Type: "object"}},
+					},
+				},
+			},
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	methods := service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		m := methods.Get(i)
+		rule, ok := proto.GetExtension(m.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if !ok || rule == nil {
+			return nil, fmt.Errorf("%s has no google.api.http option", m.FullName())
+		}
+
+		bindings := append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...)
+		for n, b := range bindings {
+			verb, path, err := pattern(b)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", m.FullName(), err)
+			}
+			op := doc.operation(service, m, path, b.GetBody())
+			if n > 0 {
+				op.OperationID = fmt.Sprintf("%s%d", op.OperationID, n+1)
+			}
+			if doc.Paths[path] == nil {
+				doc.Paths[path] = make(map[string]Operation)
+			}
+			if _, dup := doc.Paths[path][verb]; dup {
+				return nil, fmt.Errorf("%s: %s %s is already bound", m.FullName(), strings.ToUpper(verb), path)
+			}
+			doc.Paths[path][verb] = op
+		}
+	}
+
+	return doc, nil
+}
+
+func pattern(rule *annotations.HttpRule) (verb, path string, err error) {
+	switch p := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		return "get", p.Get, nil
+	case *annotations.HttpRule_Post:
+		return "post", p.Post, nil
+	case *annotations.HttpRule_Put:
+		return "put", p.Put, nil
+	case *annotations.HttpRule_Patch:
+		return "patch", p.Patch, nil
+	case *annotations.HttpRule_Delete:
+		return "delete", p.Delete, nil
+	default:
+		return "", "", fmt.Errorf("unsupported HTTP pattern %T", p)
+	}
+}
+
+func (d *Document) operation(service protoreflect.ServiceDescriptor, m protoreflect.MethodDescriptor, path, body string) Operation {
+	op := Operation{
+		OperationID: fmt.Sprintf("%s_%s", service.Name(), m.Name()),
+		Tags:        []string{string(service.Name())},
+		Responses: map[string]Response{
+			"200": {
+				Description: "A successful response.",
+				Content:     map[string]MediaType{jsonType: {Schema: d.ref(m.Output())}},
+			},
+			"default": {
+				Description: "The gRPC status of a failed call.",
+				Content:     map[string]MediaType{jsonType: {Schema: d.ref(nil)}},
+			},
+		},
+	}
+
+	input := m.Input()
+	inPath := make(map[string]bool)
+	for _, match := range pathParam.FindAllStringSubmatch(path, -1) {
+		name := match[1]
+		inPath[name] = true
+		schema := &Schema{Type: "string"}
+		if f := input.Fields().ByName(protoreflect.Name(name)); f != nil {
+			schema = d.field(f)
+		}
+		op.Parameters = append(op.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+
+	switch body {
+	case "*":
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{jsonType: {Schema: d.ref(input)}},
+		}
+	case "":
+		// Without a body, the remaining scalar fields are query parameters
+		fields := input.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			f := fields.Get(i)
+			if inPath[string(f.Name())] || f.Message() != nil {
+				continue
+			}
+			op.Parameters = append(op.Parameters, Parameter{Name: string(f.Name()), In: "query", Schema: d.field(f)})
+		}
+	}
+
+	switch {
+	case m.Output().FullName() == httpBodyName:
+		op.Responses["200"] = Response{
+			Description: "The raw file; its content type depends on the request.",
+			Content:     map[string]MediaType{"*/*": {Schema: &Schema{Type: "string", Format: "binary"}}},
+		}
+	case m.IsStreamingServer():
+		// Streams are served as newline-delimited JSON, one object per message
+		op.Responses["200"] = Response{
+			Description: "A stream of results, one JSON object per line.",
+			Content: map[string]MediaType{ndjsonType: {Schema: &Schema{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"result": d.ref(m.Output()),
+					"error":  d.ref(nil),
+				},
+			}}},
+		}
+	}
+
+	return op
+}
+
+// ref returns a reference to md's schema, adding it and the messages it
+// uses to the components. A nil md refers to the error status.
+func (d *Document) ref(md protoreflect.MessageDescriptor) *Schema {
+	if md == nil {
+		return &Schema{Ref: "#/components/schemas/" + statusName}
+	}
+
+	name := string(md.FullName())
+	if _, ok := d.Components.Schemas[name]; !ok {
+		schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		d.Components.Schemas[name] = schema
+
+		fields := md.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			f := fields.Get(i)
+			schema.Properties[string(f.Name())] = d.field(f)
+		}
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (d *Document) field(f protoreflect.FieldDescriptor) *Schema {
+	if f.IsMap() {
+		return &Schema{Type: "object", AdditionalProperties: d.singular(f.MapValue())}
+	}
+	if f.IsList() {
+		return &Schema{Type: "array", Items: d.singular(f)}
+	}
+	return d.singular(f)
+}
+
+// singular maps a field's kind to its protojson representation.
+func (d *Document) singular(f protoreflect.FieldDescriptor) *Schema {
+	switch f.Kind() {
+	case protoreflect.BoolKind:
+		return &Schema{Type: "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return &Schema{Type: "integer", Format: "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &Schema{Type: "integer", Format: "int64"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// protojson writes 64-bit integers as strings
+		return &Schema{Type: "string", Format: "int64"}
+	case protoreflect.FloatKind:
+		return &Schema{Type: "number", Format: "float"}
+	case protoreflect.DoubleKind:
+		return &Schema{Type: "number", Format: "double"}
+	case protoreflect.BytesKind:
+		return &Schema{Type: "string", Format: "byte"}
+	case protoreflect.EnumKind:
+		values := f.Enum().Values()
+		schema := &Schema{Type: "string"}
+		for i := 0; i < values.Len(); i++ {
+			schema.Enum = append(schema.Enum, string(values.Get(i).Name()))
+		}
+		return schema
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return d.ref(f.Message())
+	default:
+		return &Schema{Type: "string"}
+	}
+}
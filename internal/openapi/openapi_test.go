@@ -0,0 +1,90 @@
+package openapi
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"testing"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+var update = flag.Bool("update", false, "rewrite docs/openapi.json from the proto")
+
+const goldenPath = "../../docs/openapi.json"
+
+// TestSpecMatchesDocs fails when api/ticket.proto changes without
+// regenerating docs/openapi.json (make openapi).
+func TestSpecMatchesDocs(t *testing.T) {
+	got, err := Spec()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if *update {
+		if err := os.WriteFile(goldenPath, got, 0o644); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("docs/openapi.json is out of date with api/ticket.proto, run `make openapi`")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	doc, err := Generate(ticket.File_api_ticket_proto.Services().ByName("TicketService"), "v1")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	methods := ticket.File_api_ticket_proto.Services().ByName("TicketService").Methods()
+	ops := 0
+	for _, item := range doc.Paths {
+		ops += len(item)
+	}
+	if ops < methods.Len() {
+		t.Errorf("Expected at least one operation per RPC (%d), got %d", methods.Len(), ops)
+	}
+
+	op, ok := doc.Paths["/v1/tickets/{email}/seat"]["patch"]
+	if !ok {
+		t.Fatal("Expected PATCH /v1/tickets/{email}/seat")
+	}
+	if len(op.Parameters) != 1 || op.Parameters[0].In != "path" || op.Parameters[0].Name != "email" {
+		t.Errorf("Expected the email path parameter, got %+v", op.Parameters)
+	}
+	if op.RequestBody == nil {
+		t.Error("Expected a request body")
+	}
+
+	op = doc.Paths["/v1/allocations"]["get"]
+	if len(op.Parameters) != 6 || op.Parameters[0].In != "query" || op.Parameters[0].Name != "section" || op.Parameters[1].Name != "page_size" {
+		t.Errorf("Expected section and paging query parameters, got %+v", op.Parameters)
+	}
+
+	if _, ok := doc.Components.Schemas["ticket.Receipt"]; !ok {
+		t.Error("Expected nested messages to be included in the schemas")
+	}
+}
+
+func TestGenerate_MissingAnnotation(t *testing.T) {
+	fdp := protodesc.ToFileDescriptorProto(ticket.File_api_ticket_proto)
+	fdp.Service[0].Method[0].Options = &descriptorpb.MethodOptions{}
+
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if _, err := Generate(fd.Services().Get(0), "v1"); err == nil {
+		t.Error("Expected an error for an RPC without an HTTP rule")
+	}
+}
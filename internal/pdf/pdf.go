@@ -0,0 +1,74 @@
+// Package pdf writes small PDF documents by hand. Text is set in the built-in
+// Courier font, so documents need no embedded fonts.
+package pdf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Courier is the font dictionary for text written with String.
+const Courier = "<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>"
+
+var escaper = strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`)
+
+// String encodes s for a literal string in Courier's WinAnsi encoding;
+// characters it lacks become "?".
+func String(s string) string {
+	encoded := make([]byte, 0, len(s))
+	for _, r := range s {
+		b, ok := charmap.Windows1252.EncodeRune(r)
+		if !ok {
+			b = '?'
+		}
+		encoded = append(encoded, b)
+	}
+	return escaper.Replace(string(encoded))
+}
+
+// Writer writes numbered objects and the cross-reference table that points
+// at them. Object 1 must be the document catalog.
+type Writer struct {
+	w       *bufio.Writer
+	offset  int
+	offsets []int
+}
+
+// NewWriter starts a PDF document on w.
+func NewWriter(w io.Writer) *Writer {
+	p := &Writer{w: bufio.NewWriter(w)}
+	// The binary comment marks the file as binary for transfer tools
+	p.write("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	return p
+}
+
+func (p *Writer) write(s string) {
+	n, _ := p.w.WriteString(s)
+	p.offset += n
+}
+
+// Object writes the next object, numbered from 1.
+func (p *Writer) Object(body string) {
+	p.offsets = append(p.offsets, p.offset)
+	p.write(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", len(p.offsets), body))
+}
+
+// Stream writes the next object as a stream of data.
+func (p *Writer) Stream(data []byte) {
+	p.Object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(data), data))
+}
+
+// Finish writes the cross-reference table and trailer.
+func (p *Writer) Finish() error {
+	xref := p.offset
+	p.write(fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", len(p.offsets)+1))
+	for _, off := range p.offsets {
+		p.write(fmt.Sprintf("%010d 00000 n \n", off))
+	}
+	p.write(fmt.Sprintf("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(p.offsets)+1, xref))
+	return p.w.Flush()
+}
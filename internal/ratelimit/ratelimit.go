@@ -0,0 +1,168 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudbees/train-ticket-service/internal/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// RetryAfterHeader carries the whole seconds to wait after ResourceExhausted.
+	RetryAfterHeader = "retry-after"
+	// ForwardedForHeader is set by the in-process HTTP gateway to the HTTP
+	// client's address. It is only trusted when the peer has no IP of its own.
+	ForwardedForHeader = "x-forwarded-for"
+)
+
+// Limit is a token bucket holding up to Burst calls, refilled at PerMinute.
+// A zero PerMinute means no limit.
+type Limit struct {
+	PerMinute float64
+	Burst     int
+}
+
+func (l Limit) PerSecond() float64 {
+	return l.PerMinute / 60
+}
+
+func (l Limit) unlimited() bool {
+	return l.PerMinute <= 0 || l.Burst <= 0
+}
+
+// Rule limits a method separately for each authenticated user and each client
+// IP. Callers without a verified JWT share the PerUser bucket of their IP.
+type Rule struct {
+	PerUser Limit
+	PerIP   Limit
+}
+
+type Rules struct {
+	Default Rule
+	// Methods overrides Default by RPC name, e.g. "PurchaseTicket".
+	Methods map[string]Rule
+}
+
+// exemptPrefixes are never limited, so probes and tooling keep working under load.
+var exemptPrefixes = []string{"/grpc.health.v1.", "/grpc.reflection."}
+
+type Limiter struct {
+	store  Store
+	rules  Rules
+	tokens *auth.Verifier
+}
+
+// New returns a Limiter that keys per-user buckets on JWTs verified by
+// tokens; with a nil tokens every caller is keyed by IP.
+func New(store Store, rules Rules, tokens *auth.Verifier) *Limiter {
+	return &Limiter{store: store, rules: rules, tokens: tokens}
+}
+
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if retryAfter, err := l.check(ctx, info.FullMethod); err != nil {
+			grpc.SetHeader(ctx, retryAfter)
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (l *Limiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if retryAfter, err := l.check(ss.Context(), info.FullMethod); err != nil {
+			ss.SetHeader(retryAfter)
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// check takes a token from each bucket that applies to the call. When one is
+// empty it returns ResourceExhausted and the retry-after header to send.
+func (l *Limiter) check(ctx context.Context, method string) (metadata.MD, error) {
+	for _, prefix := range exemptPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return nil, nil
+		}
+	}
+
+	rule, ok := l.rules.Methods[path.Base(method)]
+	if !ok {
+		rule = l.rules.Default
+	}
+
+	var keys []string
+	var limits []Limit
+	ip := ClientIP(ctx)
+	if !rule.PerUser.unlimited() {
+		// A forged token must not buy a fresh bucket, so only verified emails get their own
+		if user, err := l.tokens.ExtractUser(ctx); err == nil {
+			keys = append(keys, "user|"+method+"|"+strings.ToLower(user.Email))
+			limits = append(limits, rule.PerUser)
+		} else if ip != "" {
+			keys = append(keys, "user|"+method+"|ip:"+ip)
+			limits = append(limits, rule.PerUser)
+		}
+	}
+	if !rule.PerIP.unlimited() && ip != "" {
+		keys = append(keys, "ip|"+method+"|"+ip)
+		limits = append(limits, rule.PerIP)
+	}
+
+	var wait time.Duration
+	limited := false
+	for i, key := range keys {
+		ok, retryAfter, err := l.store.Take(ctx, key, limits[i])
+		if err != nil {
+			// Fail open: an unavailable store should not take the service down
+			slog.Warn("rate limit store failed", "key", key, "error", err)
+			continue
+		}
+		if !ok {
+			limited = true
+			wait = max(wait, retryAfter)
+		}
+	}
+	if !limited {
+		return nil, nil
+	}
+
+	secs := int(math.Ceil(wait.Seconds()))
+	return metadata.Pairs(RetryAfterHeader, strconv.Itoa(secs)),
+		status.Errorf(codes.ResourceExhausted, "rate limit exceeded, retry in %ds", secs)
+}
+
+// ClientIP returns the caller's IP address, or "" if it is unknown.
+func ClientIP(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		if ip := net.ParseIP(host); ip != nil {
+			return ip.String()
+		}
+	}
+
+	// In-process callers have no IP; the gateway passes on its client's
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(ForwardedForHeader); len(v) > 0 {
+			if ip := net.ParseIP(strings.TrimSpace(v[len(v)-1])); ip != nil {
+				return ip.String()
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,198 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/cloudbees/train-ticket-service/internal/auth"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var testTokens = auth.NewVerifier([]byte("test-secret"), []string{"HS256"})
+
+func createTestJWT(email string) string {
+	return signTestJWT(email, "test-secret")
+}
+
+func signTestJWT(email, secret string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"email": email})
+	tokenString, _ := token.SignedString([]byte(secret))
+	return tokenString
+}
+
+func peerContext(ip string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 1234}})
+}
+
+func TestCheck_PerIP(t *testing.T) {
+	l := New(NewMemoryStore(), Rules{
+		Default: Rule{PerIP: Limit{PerMinute: 60, Burst: 1}},
+	}, testTokens)
+	const method = "/ticket.TicketService/PurchaseTicket"
+
+	if _, err := l.check(peerContext("192.0.2.1"), method); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	md, err := l.check(peerContext("192.0.2.1"), method)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Expected ResourceExhausted, got: %v", err)
+	}
+	if got := md.Get(RetryAfterHeader); len(got) != 1 || got[0] != "1" {
+		t.Errorf("Expected retry-after 1, got %v", got)
+	}
+
+	if _, err := l.check(peerContext("192.0.2.2"), method); err != nil {
+		t.Errorf("Expected another IP to have its own bucket, got: %v", err)
+	}
+	if _, err := l.check(peerContext("192.0.2.1"), "/ticket.TicketService/ViewUserReceipt"); err != nil {
+		t.Errorf("Expected another method to have its own bucket, got: %v", err)
+	}
+}
+
+func TestCheck_PerUserAndOverrides(t *testing.T) {
+	l := New(NewMemoryStore(), Rules{
+		Default: Rule{PerUser: Limit{PerMinute: 60, Burst: 5}},
+		Methods: map[string]Rule{
+			"PurchaseTicket": {PerUser: Limit{PerMinute: 1, Burst: 1}},
+		},
+	}, testTokens)
+	const method = "/ticket.TicketService/PurchaseTicket"
+
+	ctx := func(email string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+createTestJWT(email)))
+	}
+
+	if _, err := l.check(ctx("john@example.com"), method); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// Emails are case-insensitive, so changing case does not get a new bucket
+	if _, err := l.check(ctx("JOHN@example.com"), method); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted, got: %v", err)
+	}
+	if _, err := l.check(ctx("jane@example.com"), method); err != nil {
+		t.Errorf("Expected another user to have their own bucket, got: %v", err)
+	}
+	if _, err := l.check(context.Background(), method); err != nil {
+		t.Errorf("Expected anonymous calls to skip the per-user limit, got: %v", err)
+	}
+}
+
+func TestCheck_PerUserNeedsVerifiedToken(t *testing.T) {
+	l := New(NewMemoryStore(), Rules{
+		Default: Rule{PerUser: Limit{PerMinute: 1, Burst: 1}},
+	}, testTokens)
+	const method = "/ticket.TicketService/PurchaseTicket"
+
+	ctx := func(token string) context.Context {
+		return metadata.NewIncomingContext(peerContext("192.0.2.1"), metadata.Pairs("authorization", "Bearer "+token))
+	}
+
+	// Forged tokens with a fresh email each time share the bucket of their IP
+	if _, err := l.check(ctx(signTestJWT("a@example.com", "forged")), method); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := l.check(ctx(signTestJWT("b@example.com", "forged")), method); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted, got: %v", err)
+	}
+
+	// A verified user from the same IP still has a bucket of their own
+	if _, err := l.check(ctx(createTestJWT("john@example.com")), method); err != nil {
+		t.Errorf("Expected verified user to have their own bucket, got: %v", err)
+	}
+}
+
+func TestCheck_Exempt(t *testing.T) {
+	l := New(NewMemoryStore(), Rules{
+		Default: Rule{PerIP: Limit{PerMinute: 1, Burst: 1}},
+	}, testTokens)
+
+	for i := 0; i < 3; i++ {
+		if _, err := l.check(peerContext("192.0.2.1"), "/grpc.health.v1.Health/Check"); err != nil {
+			t.Fatalf("Expected health checks to be exempt, got: %v", err)
+		}
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	if got := ClientIP(peerContext("2001:db8::1")); got != "2001:db8::1" {
+		t.Errorf("Expected the peer IP, got %q", got)
+	}
+
+	// A TCP peer's own address wins over a forwarded one
+	ctx := metadata.NewIncomingContext(peerContext("192.0.2.1"), metadata.Pairs(ForwardedForHeader, "198.51.100.7"))
+	if got := ClientIP(ctx); got != "192.0.2.1" {
+		t.Errorf("Expected the peer IP, got %q", got)
+	}
+
+	inproc := peer.NewContext(context.Background(), &peer.Peer{Addr: fakeAddr("bufconn")})
+	ctx = metadata.NewIncomingContext(inproc, metadata.Pairs(ForwardedForHeader, "198.51.100.7"))
+	if got := ClientIP(ctx); got != "198.51.100.7" {
+		t.Errorf("Expected the forwarded IP for an in-process peer, got %q", got)
+	}
+}
+
+type fakeAddr string
+
+func (a fakeAddr) Network() string { return string(a) }
+func (a fakeAddr) String() string  { return string(a) }
+
+func TestUnaryServerInterceptor_RetryAfterHeader(t *testing.T) {
+	l := New(NewMemoryStore(), Rules{
+		Default: Rule{PerUser: Limit{PerMinute: 6, Burst: 1}},
+	}, testTokens)
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(l.UnaryServerInterceptor()))
+	srv.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "test.Service",
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Call",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				in := &emptypb.Empty{}
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/test.Service/Call"}
+				return interceptor(ctx, in, info, func(context.Context, any) (any, error) { return &emptypb.Empty{}, nil })
+			},
+		}},
+	}, struct{}{})
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer conn.Close()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+createTestJWT("john@example.com"))
+	if err := conn.Invoke(ctx, "/test.Service/Call", &emptypb.Empty{}, &emptypb.Empty{}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var header metadata.MD
+	err = conn.Invoke(ctx, "/test.Service/Call", &emptypb.Empty{}, &emptypb.Empty{}, grpc.Header(&header))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Expected ResourceExhausted, got: %v", err)
+	}
+	if got := header.Get(RetryAfterHeader); len(got) != 1 || got[0] != "10" {
+		t.Errorf("Expected retry-after 10, got %v", got)
+	}
+}
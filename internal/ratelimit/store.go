@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// StoreMemory names the in-memory store in configuration.
+const StoreMemory = "memory"
+
+// Store holds token buckets. A shared implementation lets several replicas
+// enforce one limit together; the in-memory store limits each process alone.
+type Store interface {
+	// Take removes a token from the bucket at key, creating it full if needed.
+	// When the bucket is empty it reports how long until a token is available.
+	Take(ctx context.Context, key string, limit Limit) (ok bool, retryAfter time.Duration, err error)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// full is when the bucket will have refilled, after which it can be dropped.
+	full time.Time
+}
+
+// MemoryStore keeps buckets in process memory. Buckets that have refilled are
+// dropped periodically, so idle callers cost nothing.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+const sweepInterval = time.Minute
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+func (m *MemoryStore) Take(_ context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if now.Sub(m.lastSweep) >= sweepInterval {
+		m.sweepLocked(now)
+	}
+
+	rate := limit.PerSecond()
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		m.buckets[key] = b
+	} else {
+		b.tokens = min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+		b.last = now
+	}
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	b.full = now.Add(seconds((float64(limit.Burst) - b.tokens) / rate))
+
+	if allowed {
+		return true, 0, nil
+	}
+	return false, seconds((1 - b.tokens) / rate), nil
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// sweepLocked drops buckets that have refilled; recreating one later gives
+// the same result.
+func (m *MemoryStore) sweepLocked(now time.Time) {
+	m.lastSweep = now
+	for key, b := range m.buckets {
+		if !now.Before(b.full) {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	now := time.Unix(0, 0)
+	m := NewMemoryStore()
+	m.now = func() time.Time { return now }
+
+	limit := Limit{PerMinute: 60, Burst: 2}
+
+	for i := 0; i < 2; i++ {
+		if ok, _, _ := m.Take(context.Background(), "k", limit); !ok {
+			t.Fatalf("Expected call %d to be within the burst", i+1)
+		}
+	}
+
+	ok, retryAfter, err := m.Take(context.Background(), "k", limit)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if ok {
+		t.Fatal("Expected the bucket to be empty")
+	}
+	if retryAfter != time.Second {
+		t.Errorf("Expected retry after 1s, got %v", retryAfter)
+	}
+
+	if ok, _, _ := m.Take(context.Background(), "other", limit); !ok {
+		t.Error("Expected buckets to be independent")
+	}
+
+	now = now.Add(time.Second)
+	if ok, _, _ := m.Take(context.Background(), "k", limit); !ok {
+		t.Error("Expected a token after refilling for 1s")
+	}
+}
+
+func TestMemoryStore_Sweep(t *testing.T) {
+	now := time.Unix(0, 0)
+	m := NewMemoryStore()
+	m.now = func() time.Time { return now }
+
+	slow := Limit{PerMinute: 1, Burst: 5}
+	for i := 0; i < 5; i++ {
+		m.Take(context.Background(), "slow", slow)
+	}
+	m.Take(context.Background(), "fast", Limit{PerMinute: 60, Burst: 1})
+
+	// A minute refills the fast bucket but only one token of the slow one
+	now = now.Add(2 * sweepInterval)
+	m.Take(context.Background(), "trigger", Limit{PerMinute: 60, Burst: 1})
+
+	if _, ok := m.buckets["fast"]; ok {
+		t.Error("Expected the refilled bucket to be dropped")
+	}
+	if _, ok := m.buckets["slow"]; !ok {
+		t.Error("Expected the partly refilled bucket to be kept")
+	}
+}
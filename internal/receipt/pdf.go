@@ -0,0 +1,72 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/cloudbees/train-ticket-service/internal/pdf"
+	"rsc.io/qr"
+)
+
+// A receipt is one portrait A4 page: the template's text at the top, in 11pt
+// Courier, and the QR code below it.
+const (
+	pageWidth  = 595
+	pageHeight = 842
+	margin     = 50
+	fontSize   = 11
+	leading    = 15
+	// maxLineWidth is how many Courier characters fit across the page.
+	maxLineWidth = (pageWidth - 2*margin) * 10 / (fontSize * 6)
+	maxLines     = (pageHeight - 2*margin - qrSize) / leading
+	qrSize       = 180
+	// qrQuietZone is the blank border a scanner needs, in modules.
+	qrQuietZone = 4
+)
+
+func writePDF(w io.Writer, lines []string, code *qr.Code) error {
+	if len(lines) > maxLines {
+		return fmt.Errorf("receipt has %d lines, at most %d fit on the page", len(lines), maxLines)
+	}
+
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "BT /F1 %d Tf %d TL %d %d Td\n", fontSize, leading, margin, pageHeight-margin-fontSize)
+	for _, line := range lines {
+		if utf8.RuneCountInString(line) > maxLineWidth {
+			line = string([]rune(line)[:maxLineWidth-1]) + "~"
+		}
+		fmt.Fprintf(&content, "(%s) Tj T*\n", pdf.String(line))
+	}
+	content.WriteString("ET\n")
+
+	// One filled rectangle per run of dark modules in a row, scaled to
+	// qrSize with its quiet zone
+	module := float64(qrSize) / float64(code.Size+2*qrQuietZone)
+	left := float64(margin) + qrQuietZone*module
+	top := float64(pageHeight-margin-len(lines)*leading) - qrQuietZone*module
+	content.WriteString("0 g\n")
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; x++ {
+			if !code.Black(x, y) {
+				continue
+			}
+			run := 1
+			for x+run < code.Size && code.Black(x+run, y) {
+				run++
+			}
+			fmt.Fprintf(&content, "%.2f %.2f %.2f %.2f re\n", left+float64(x)*module, top-float64(y+1)*module, float64(run)*module, module)
+			x += run
+		}
+	}
+	content.WriteString("f\n")
+
+	doc := pdf.NewWriter(w)
+	doc.Object("<< /Type /Catalog /Pages 2 0 R >>")
+	doc.Object("<< /Type /Pages /Kids [4 0 R] /Count 1 >>")
+	doc.Object(pdf.Courier)
+	doc.Object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents 5 0 R >>", pageWidth, pageHeight))
+	doc.Stream(content.Bytes())
+	return doc.Finish()
+}
@@ -0,0 +1,137 @@
+// Package receipt renders e-tickets as HTML pages or PDFs. Both come from
+// templates that operators can replace, and both carry the ticket's signed
+// token as a QR code for conductors to scan.
+package receipt
+
+import (
+	"bytes"
+	"embed"
+	"encoding/base64"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"rsc.io/qr"
+)
+
+const (
+	FormatHTML = "html"
+	FormatPDF  = "pdf"
+)
+
+//go:embed templates
+var builtin embed.FS
+
+// Data is what the templates are executed with.
+type Data struct {
+	From        string
+	To          string
+	FirstName   string
+	LastName    string
+	Email       string
+	Section     string
+	SeatNumber  int32
+	Row         int32
+	Attribute   string // window, aisle or middle
+	PricePaid   int32  // cents
+	PurchasedBy string
+	IssuedAt    time.Time
+	// Token is the signed ticket token the QR code encodes.
+	Token string
+	// QRCode is the QR code as a PNG data URL for an img src. Render sets it
+	// for HTML receipts; PDF receipts draw the code themselves.
+	QRCode htmltemplate.URL
+}
+
+// Price formats PricePaid, e.g. "$20.00".
+func (d Data) Price() string {
+	return fmt.Sprintf("$%.2f", float64(d.PricePaid)/100)
+}
+
+// Renderer renders receipts with a template per format.
+type Renderer struct {
+	html *htmltemplate.Template
+	pdf  *texttemplate.Template
+}
+
+// New loads an html/template from htmlPath and a text/template from pdfPath.
+// An empty path keeps the built-in template for that format. The PDF template
+// produces plain text, one line of the page per line of output.
+func New(htmlPath, pdfPath string) (*Renderer, error) {
+	html, err := load(htmlPath, "receipt.html")
+	if err != nil {
+		return nil, err
+	}
+	text, err := load(pdfPath, "receipt.txt")
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Renderer{}
+	if r.html, err = htmltemplate.New("html").Parse(html); err != nil {
+		return nil, err
+	}
+	if r.pdf, err = texttemplate.New("pdf").Parse(text); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func load(path, name string) (string, error) {
+	var data []byte
+	var err error
+	if path == "" {
+		data, err = builtin.ReadFile("templates/" + name)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	return string(data), err
+}
+
+// ContentType returns the MIME type of format, or an error if it is unknown.
+func ContentType(format string) (string, error) {
+	switch format {
+	case FormatHTML:
+		return "text/html; charset=utf-8", nil
+	case FormatPDF:
+		return "application/pdf", nil
+	default:
+		return "", fmt.Errorf("must be %s or %s", FormatHTML, FormatPDF)
+	}
+}
+
+// Render writes the receipt for d to w in format.
+func (r *Renderer) Render(w io.Writer, format string, d Data) error {
+	code, err := qr.Encode(d.Token, qr.M)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case FormatHTML:
+		d.QRCode = htmltemplate.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(code.PNG()))
+		return r.html.Execute(w, d)
+	case FormatPDF:
+		var text bytes.Buffer
+		if err := r.pdf.Execute(&text, d); err != nil {
+			return err
+		}
+		return writePDF(w, strings.Split(strings.TrimRight(text.String(), "\n"), "\n"), code)
+	default:
+		_, err := ContentType(format)
+		return err
+	}
+}
+
+// Default returns a Renderer with the built-in templates.
+func Default() *Renderer {
+	r, err := New("", "")
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
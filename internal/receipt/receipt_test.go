@@ -0,0 +1,97 @@
+package receipt
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testData() Data {
+	return Data{
+		From:       "London",
+		To:         "France",
+		FirstName:  "Zoë",
+		LastName:   "<Lee>",
+		Email:      "zoe@example.com",
+		Section:    "B",
+		SeatNumber: 7,
+		Row:        2,
+		Attribute:  "aisle",
+		PricePaid:  2000,
+		IssuedAt:   time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC),
+		Token:      "header.payload.signature",
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	r, err := New("", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := r.Render(&buf, FormatHTML, testData()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	html := buf.String()
+	for _, want := range []string{"Zoë &lt;Lee&gt;", "B-7, row 2, aisle", "$20.00", `src="data:image/png;base64,`, "header.payload.signature"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected the receipt to contain %q", want)
+		}
+	}
+}
+
+func TestRenderPDF(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "receipt.txt")
+	if err := os.WriteFile(path, []byte("{{.FirstName}} {{.Section}}-{{.SeatNumber}}\n"), 0o644); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	r, err := New("", path)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := r.Render(&buf, FormatPDF, testData()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	pdf := buf.Bytes()
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatal("Expected a PDF header and trailer")
+	}
+	if !bytes.Contains(pdf, []byte("(Zo\xeb B-7) Tj")) {
+		t.Error("Expected the template's line in WinAnsi encoding")
+	}
+	if !bytes.Contains(pdf, []byte(" re\n")) {
+		t.Error("Expected the QR code to be drawn")
+	}
+
+	long := filepath.Join(dir, "long.txt")
+	os.WriteFile(long, []byte(strings.Repeat("line\n", maxLines+1)), 0o644)
+	if r, err = New("", long); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := r.Render(&buf, FormatPDF, testData()); err == nil {
+		t.Error("Expected an error for a receipt longer than a page")
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.html"), ""); err == nil {
+		t.Error("Expected an error for a missing template")
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.html")
+	os.WriteFile(bad, []byte("{{.From"), 0o644)
+	if _, err := New(bad, ""); err == nil {
+		t.Error("Expected an error for an invalid template")
+	}
+
+	if _, err := ContentType("docx"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// WatchReadiness runs check every interval and publishes the result on hs for
+// the overall server ("") and each of services, until ctx is done. A check
+// that does not return within interval counts as not ready.
+func WatchReadiness(ctx context.Context, hs *health.Server, interval time.Duration, check func() error, services ...string) {
+	publish := func(ready bool) {
+		status := healthpb.HealthCheckResponse_NOT_SERVING
+		if ready {
+			status = healthpb.HealthCheckResponse_SERVING
+		}
+		hs.SetServingStatus("", status)
+		for _, svc := range services {
+			hs.SetServingStatus(svc, status)
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var ready bool
+	for i := 0; ; i++ {
+		err := probe(check, interval)
+		if i == 0 || (err == nil) != ready {
+			if err != nil {
+				slog.Warn("health: not serving", "error", err)
+			} else {
+				slog.Info("health: serving")
+			}
+		}
+		ready = err == nil
+		publish(ready)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func probe(check func() error, timeout time.Duration) error {
+	result := make(chan error, 1)
+	go func() {
+		result <- check()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-result:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("readiness check timed out after %s", timeout)
+	}
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestWatchReadiness(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var failing atomic.Bool
+	hs := health.NewServer()
+	go WatchReadiness(ctx, hs, 10*time.Millisecond, func() error {
+		if failing.Load() {
+			return errors.New("store is closed")
+		}
+		return nil
+	}, "ticket.TicketService")
+
+	waitForStatus(t, hs, "ticket.TicketService", healthpb.HealthCheckResponse_SERVING)
+	waitForStatus(t, hs, "", healthpb.HealthCheckResponse_SERVING)
+
+	failing.Store(true)
+	waitForStatus(t, hs, "ticket.TicketService", healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+func TestWatchReadiness_SlowCheck(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stuck := make(chan struct{})
+	defer close(stuck)
+
+	hs := health.NewServer()
+	go WatchReadiness(ctx, hs, 10*time.Millisecond, func() error {
+		<-stuck
+		return nil
+	})
+
+	waitForStatus(t, hs, "", healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+func waitForStatus(t *testing.T, hs *health.Server, service string, want healthpb.HealthCheckResponse_ServingStatus) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		resp, err := hs.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err == nil && resp.Status == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %q to report %v, got %v (err %v)", service, want, resp.GetStatus(), err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
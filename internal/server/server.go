@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Hook runs once the server has stopped, to flush or persist state before exit.
+type Hook struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Serve runs srv on lis until ctx is done, then drains it: new calls are
+// refused and in-flight calls get up to timeout to finish. Calls and streams
+// still running after that are cancelled. Hooks run afterwards, in order,
+// with their own timeout.
+func Serve(ctx context.Context, srv *grpc.Server, lis net.Listener, timeout time.Duration, hooks ...Hook) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(lis)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down, waiting for in-flight calls", "timeout", timeout.String())
+	drain(srv, timeout)
+	// Serve reports ErrServerStopped if the signal arrived before it started
+	if err := <-serveErr; err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+	for _, h := range hooks {
+		if err := h.Run(flushCtx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", h.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func drain(srv *grpc.Server, timeout time.Duration) {
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-stopped:
+	case <-timer.C:
+		slog.Warn("shutdown deadline exceeded, cancelling remaining calls")
+		srv.Stop()
+		<-stopped
+	}
+}
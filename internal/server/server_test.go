@@ -0,0 +1,125 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// startSlowServer serves a single method whose handler runs handle. It
+// returns a client connection and the channel Serve's result is sent on.
+func startSlowServer(t *testing.T, ctx context.Context, timeout time.Duration, handle func(stream grpc.ServerStream) error, hooks ...Hook) (*grpc.ClientConn, <-chan error) {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(grpc.UnknownServiceHandler(func(_ any, stream grpc.ServerStream) error {
+		var req emptypb.Empty
+		if err := stream.RecvMsg(&req); err != nil {
+			return err
+		}
+		if err := handle(stream); err != nil {
+			return err
+		}
+		return stream.SendMsg(&emptypb.Empty{})
+	}))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, srv, lis, timeout, hooks...)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn, done
+}
+
+func TestServe_DrainsInFlightCalls(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	var flushed bool
+
+	conn, done := startSlowServer(t, ctx, time.Second, func(grpc.ServerStream) error {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	}, Hook{Name: "flush", Run: func(context.Context) error {
+		flushed = true
+		return nil
+	}})
+
+	callErr := make(chan error, 1)
+	go func() {
+		callErr <- conn.Invoke(context.Background(), "/test.Slow/Call", &emptypb.Empty{}, &emptypb.Empty{})
+	}()
+
+	<-started
+	cancel()
+
+	if err := <-callErr; err != nil {
+		t.Errorf("Expected in-flight call to complete, got: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !flushed {
+		t.Error("Expected hook to run after shutdown")
+	}
+}
+
+func TestServe_CancelsCallsAfterDeadline(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+
+	conn, done := startSlowServer(t, ctx, 50*time.Millisecond, func(stream grpc.ServerStream) error {
+		close(started)
+		<-stream.Context().Done()
+		return stream.Context().Err()
+	})
+
+	callErr := make(chan error, 1)
+	go func() {
+		callErr <- conn.Invoke(context.Background(), "/test.Slow/Call", &emptypb.Empty{}, &emptypb.Empty{})
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected Serve to return after the shutdown deadline")
+	}
+
+	if err := <-callErr; err == nil {
+		t.Error("Expected call still running at the deadline to fail")
+	}
+}
+
+func TestServe_HookErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	_, done := startSlowServer(t, ctx, time.Second, func(grpc.ServerStream) error { return nil },
+		Hook{Name: "outbox", Run: func(context.Context) error { return errors.New("disk full") }},
+	)
+	cancel()
+
+	if err := <-done; err == nil || err.Error() != "outbox: disk full" {
+		t.Errorf("Expected hook error, got: %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/abuse"
+	"github.com/cloudbees/train-ticket-service/internal/store"
+	"github.com/cloudbees/train-ticket-service/internal/verification"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// maxSeatSuggestions bounds the alternatives offered when a seat cannot be had.
+const maxSeatSuggestions = 3
+
+// errorDomain is the ErrorInfo domain of every reason the service sends.
+var errorDomain = ticket.TicketService_ServiceDesc.ServiceName
+
+// knownErrors gives each sentinel error its gRPC code and stable reason, so
+// clients can branch on the reason instead of parsing messages.
+var knownErrors = []struct {
+	err    error
+	code   codes.Code
+	reason ticket.ErrorReason
+}{
+	{store.ErrTicketNotFound, codes.NotFound, ticket.ErrorReason_TICKET_NOT_FOUND},
+	{store.ErrUserAlreadyHasTicket, codes.AlreadyExists, ticket.ErrorReason_DUPLICATE_TICKET},
+	{store.ErrSeatAlreadyOccupied, codes.AlreadyExists, ticket.ErrorReason_SEAT_OCCUPIED},
+	{store.ErrTrainFull, codes.ResourceExhausted, ticket.ErrorReason_TRAIN_FULL},
+	{store.ErrSectionFull, codes.ResourceExhausted, ticket.ErrorReason_SECTION_FULL},
+	{store.ErrInvalidSeat, codes.InvalidArgument, ticket.ErrorReason_INVALID_SEAT},
+	{store.ErrSectionOutOfService, codes.FailedPrecondition, ticket.ErrorReason_SECTION_OUT_OF_SERVICE},
+	{store.ErrSectionInService, codes.FailedPrecondition, ticket.ErrorReason_SECTION_IN_SERVICE},
+	{store.ErrTicketNotPending, codes.FailedPrecondition, ticket.ErrorReason_TICKET_NOT_PENDING},
+	{store.ErrTicketPending, codes.FailedPrecondition, ticket.ErrorReason_TICKET_PENDING},
+	{store.ErrSwapNotFound, codes.NotFound, ticket.ErrorReason_SWAP_NOT_FOUND},
+	{store.ErrSwapWithSelf, codes.InvalidArgument, ticket.ErrorReason_SWAP_WITH_SELF},
+	{store.ErrSwapStale, codes.FailedPrecondition, ticket.ErrorReason_SWAP_STALE},
+	{store.ErrRolledBack, codes.Aborted, ticket.ErrorReason_BATCH_ROLLED_BACK},
+
+	{verification.ErrNoPendingCode, codes.NotFound, ticket.ErrorReason_VERIFICATION_NOT_FOUND},
+	{verification.ErrCodeExpired, codes.FailedPrecondition, ticket.ErrorReason_VERIFICATION_EXPIRED},
+	{verification.ErrCodeMismatch, codes.PermissionDenied, ticket.ErrorReason_VERIFICATION_MISMATCH},
+	{verification.ErrTooManyAttempts, codes.ResourceExhausted, ticket.ErrorReason_VERIFICATION_ATTEMPTS_EXCEEDED},
+
+	{abuse.ErrDomainCapReached, codes.ResourceExhausted, ticket.ErrorReason_EMAIL_DOMAIN_CAP},
+	{abuse.ErrTooManyEmails, codes.PermissionDenied, ticket.ErrorReason_IP_PURCHASES_BLOCKED},
+	{abuse.ErrChallengeRequired, codes.FailedPrecondition, ticket.ErrorReason_CHALLENGE_REQUIRED},
+	{abuse.ErrChallengeExpired, codes.FailedPrecondition, ticket.ErrorReason_CHALLENGE_EXPIRED},
+	{abuse.ErrChallengeUsed, codes.FailedPrecondition, ticket.ErrorReason_CHALLENGE_USED},
+	{abuse.ErrChallengeInvalid, codes.InvalidArgument, ticket.ErrorReason_CHALLENGE_INVALID},
+	{abuse.ErrChallengeUnsolved, codes.InvalidArgument, ticket.ErrorReason_CHALLENGE_UNSOLVED},
+}
+
+// classify returns the code and reason for err; unknown errors are Internal.
+func classify(err error) (codes.Code, ticket.ErrorReason) {
+	for _, k := range knownErrors {
+		if errors.Is(err, k.err) {
+			return k.code, k.reason
+		}
+	}
+	return codes.Internal, ticket.ErrorReason_ERROR_REASON_UNSPECIFIED
+}
+
+// errorStatus converts err to a status error. Known errors carry an ErrorInfo
+// with metadata, followed by details such as seat suggestions.
+func errorStatus(err error, metadata map[string]string, details ...protoadapt.MessageV1) error {
+	code, reason := classify(err)
+	return reasonStatus(code, err.Error(), reason, metadata, details...)
+}
+
+func reasonStatus(code codes.Code, msg string, reason ticket.ErrorReason, metadata map[string]string, details ...protoadapt.MessageV1) error {
+	st := status.New(code, msg)
+	if reason != ticket.ErrorReason_ERROR_REASON_UNSPECIFIED {
+		info := &errdetails.ErrorInfo{Reason: reason.String(), Domain: errorDomain, Metadata: metadata}
+		details = append([]protoadapt.MessageV1{info}, details...)
+	}
+	if len(details) > 0 {
+		if withDetails, err := st.WithDetails(details...); err == nil {
+			st = withDetails
+		}
+	}
+	return st.Err()
+}
+
+// purchaseError reports a failed purchase for email.
+func (s *TicketService) purchaseError(err error, email string) error {
+	metadata := map[string]string{"email": email}
+	if errors.Is(err, store.ErrTrainFull) {
+		layout := s.store.Layout()
+		metadata = map[string]string{"capacity": strconv.Itoa(len(layout.Sections) * int(layout.SeatsPerSection))}
+	}
+	return errorStatus(err, metadata)
+}
+
+// seatError reports why section-seatNumber could not be taken, suggesting the
+// nearest free seats when another seat would do.
+func (s *TicketService) seatError(err error, section string, seatNumber int32) error {
+	metadata := map[string]string{"section": section, "seat_number": strconv.Itoa(int(seatNumber))}
+
+	switch _, reason := classify(err); reason {
+	case ticket.ErrorReason_SEAT_OCCUPIED, ticket.ErrorReason_INVALID_SEAT, ticket.ErrorReason_SECTION_OUT_OF_SERVICE:
+		suggestions := &ticket.SeatSuggestions{}
+		for _, seat := range s.store.NearbyFreeSeats(section, seatNumber, maxSeatSuggestions) {
+			suggestions.Seats = append(suggestions.Seats, convertSeat(seat))
+		}
+		return errorStatus(err, metadata, suggestions)
+	default:
+		return errorStatus(err, metadata)
+	}
+}
+
+func sectionError(err error, section string) error {
+	return errorStatus(err, map[string]string{"section": section})
+}
+
+func swapError(err error, swapID string) error {
+	var metadata map[string]string
+	if swapID != "" {
+		metadata = map[string]string{"swap_id": swapID}
+	}
+	return errorStatus(err, metadata)
+}
+
+func abuseError(err error, email string) error {
+	var metadata map[string]string
+	if errors.Is(err, abuse.ErrDomainCapReached) {
+		metadata = map[string]string{"domain": abuse.Domain(email)}
+	}
+	return errorStatus(err, metadata)
+}
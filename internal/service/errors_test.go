@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/config"
+	"github.com/cloudbees/train-ticket-service/internal/model"
+	"github.com/cloudbees/train-ticket-service/internal/store"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// errorDetails splits a status error into its ErrorInfo and seat suggestions.
+func errorDetails(t *testing.T, err error) (*errdetails.ErrorInfo, []*ticket.Seat) {
+	t.Helper()
+
+	var info *errdetails.ErrorInfo
+	var seats []*ticket.Seat
+	for _, d := range status.Convert(err).Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			info = d
+		case *ticket.SeatSuggestions:
+			seats = d.Seats
+		}
+	}
+	if info == nil {
+		t.Fatalf("Expected an ErrorInfo detail on %v", err)
+	}
+	if info.Domain != "ticket.TicketService" {
+		t.Errorf("Expected domain ticket.TicketService, got %q", info.Domain)
+	}
+	return info, seats
+}
+
+func TestModifyUserSeat_SeatOccupied(t *testing.T) {
+	s := store.NewStore()
+	service := newTestService(s)
+
+	for _, email := range []string{"first@example.com", "second@example.com"} {
+		user := model.User{Email: email, FirstName: "Test", LastName: "User"}
+		if _, err := s.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents); err != nil {
+			t.Fatalf("Failed to purchase ticket: %v", err)
+		}
+	}
+
+	token := createTestJWT("second@example.com", "Test", "User", "user")
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + token,
+	}))
+
+	_, err := service.ModifyUserSeat(ctx, &ticket.ModifyUserSeatRequest{Section: "A", SeatNumber: 1})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("Expected AlreadyExists, got %v", err)
+	}
+
+	info, seats := errorDetails(t, err)
+	if info.Reason != "SEAT_OCCUPIED" {
+		t.Errorf("Expected reason SEAT_OCCUPIED, got %q", info.Reason)
+	}
+	if info.Metadata["section"] != "A" || info.Metadata["seat_number"] != "1" {
+		t.Errorf("Expected the requested seat in metadata, got %v", info.Metadata)
+	}
+
+	// A-2 is the caller's own seat, so A-3 is the nearest free one
+	want := []string{"A-3", "A-4", "A-5"}
+	if len(seats) != len(want) {
+		t.Fatalf("Expected suggestions %v, got %v", want, seats)
+	}
+	for i, seat := range seats {
+		if got := fmt.Sprintf("%s-%d", seat.Section, seat.SeatNumber); got != want[i] {
+			t.Errorf("Expected suggestion %d to be %s, got %s", i, want[i], got)
+		}
+	}
+}
+
+func TestPurchaseTicket_ErrorReasons(t *testing.T) {
+	s := store.NewStoreWithLayout(model.Layout{Sections: []string{"A"}, SeatsPerSection: 1})
+	service := newTestService(s, WithPurchaseAuthMode(config.PurchaseAuthRequired))
+
+	purchase := func(email string) error {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+			"authorization": "Bearer " + createTestJWT(email, "Test", "User", "user"),
+		}))
+		_, err := service.PurchaseTicket(ctx, &ticket.PurchaseTicketRequest{FirstName: "Test", LastName: "User", Email: email})
+		return err
+	}
+
+	if err := purchase("jane@example.com"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	tests := []struct {
+		email    string
+		reason   string
+		key      string
+		keyValue string
+	}{
+		{"jane@example.com", "DUPLICATE_TICKET", "email", "jane@example.com"},
+		{"john@example.com", "TRAIN_FULL", "capacity", "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reason, func(t *testing.T) {
+			info, _ := errorDetails(t, purchase(tt.email))
+			if info.Reason != tt.reason {
+				t.Errorf("Expected reason %s, got %q", tt.reason, info.Reason)
+			}
+			if info.Metadata[tt.key] != tt.keyValue {
+				t.Errorf("Expected %s=%s in metadata, got %v", tt.key, tt.keyValue, info.Metadata)
+			}
+		})
+	}
+}
+
+// TestKnownErrorsHaveReasons fails when an error has no reason or two errors
+// share one, which would make the reason useless to branch on.
+func TestKnownErrorsHaveReasons(t *testing.T) {
+	seen := make(map[ticket.ErrorReason]bool)
+	for _, k := range knownErrors {
+		if k.reason == ticket.ErrorReason_ERROR_REASON_UNSPECIFIED {
+			t.Errorf("%v has no reason", k.err)
+		}
+		if seen[k.reason] {
+			t.Errorf("%s is used for more than one error", k.reason)
+		}
+		seen[k.reason] = true
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/audit"
+	"github.com/cloudbees/train-ticket-service/internal/metrics"
+	"github.com/cloudbees/train-ticket-service/internal/model"
+	"github.com/cloudbees/train-ticket-service/internal/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxImportRows bounds an import so a runaway client cannot exhaust memory.
+const maxImportRows = 10000
+
+// ImportBookings loads bookings made outside the service. Every row is
+// checked, so a dry run lists all conflicts at once; a commit books every
+// row only when none conflict.
+func (s *TicketService) ImportBookings(stream ticket.TicketService_ImportBookingsServer) error {
+	userClaims, err := s.authenticate(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	if !userClaims.IsAdmin() {
+		return status.Error(codes.PermissionDenied, "admin access required")
+	}
+
+	var commit bool
+	var rows []*ticket.BookingRow
+	for first := true; ; first = false {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if first {
+			commit = req.Commit
+		}
+		if len(rows)+len(req.Rows) > maxImportRows {
+			return status.Errorf(codes.InvalidArgument, "an import may have at most %d rows", maxImportRows)
+		}
+		rows = append(rows, req.Rows...)
+	}
+
+	rowNumber := func(i int) int32 {
+		if rows[i].Row != 0 {
+			return rows[i].Row
+		}
+		return int32(i + 1)
+	}
+
+	resp := &ticket.ImportBookingsResponse{TotalRows: int32(len(rows))}
+	price := s.quote(stream.Context())
+
+	// The store keys bookings by email, so rows without a valid one are not
+	// checked against it; index maps the rest back to their rows
+	var tickets []model.Ticket
+	var index []int
+	for i, row := range rows {
+		user, violations := checkUser("", row.FirstName, row.LastName, row.Email)
+		for _, v := range violations {
+			resp.Conflicts = append(resp.Conflicts, &ticket.ImportConflict{Row: rowNumber(i), Field: v.Field, Description: v.Description})
+		}
+		if user.Email == "" {
+			continue
+		}
+		tickets = append(tickets, model.Ticket{
+			From:        s.routeFrom,
+			To:          s.routeTo,
+			User:        user,
+			PricePaid:   price,
+			Seat:        model.Seat{Section: row.Section, SeatNumber: row.SeatNumber},
+			PurchasedBy: userClaims.Email,
+		})
+		index = append(index, i)
+	}
+
+	errs, committed := s.store.ImportBookings(stream.Context(), tickets, commit && len(resp.Conflicts) == 0)
+	for j, err := range errs {
+		if err != nil {
+			resp.Conflicts = append(resp.Conflicts, s.importConflict(err, rowNumber(index[j]), tickets[j].Seat, func(other int) int32 {
+				return rowNumber(index[other])
+			}))
+		}
+	}
+	slices.SortStableFunc(resp.Conflicts, func(a, b *ticket.ImportConflict) int { return cmp.Compare(a.Row, b.Row) })
+
+	resp.Committed = committed
+	resp.ValidRows = resp.TotalRows - int32(conflictedRows(resp.Conflicts))
+
+	if committed {
+		s.metrics.RecordPurchases(metrics.PurchaseImported, len(tickets))
+		s.audit.Record(audit.Entry{
+			Actor:     userClaims.Email,
+			ActorRole: userClaims.Role,
+			Subject:   fmt.Sprintf("%d bookings", len(tickets)),
+			Method:    ticket.TicketService_ImportBookings_FullMethodName,
+			Action:    "import_bookings",
+			Allowed:   true,
+		})
+	}
+
+	return stream.SendAndClose(resp)
+}
+
+// importConflict describes why the store refused a row; rowOf turns the index
+// of a clashing booking into its row number.
+func (s *TicketService) importConflict(err error, row int32, seat model.Seat, rowOf func(int) int32) *ticket.ImportConflict {
+	_, reason := classify(err)
+	c := &ticket.ImportConflict{Row: row, Reason: reason.String(), Description: err.Error()}
+	if reason == ticket.ErrorReason_ERROR_REASON_UNSPECIFIED {
+		c.Reason = ""
+	}
+
+	switch {
+	case errors.Is(err, store.ErrUserAlreadyHasTicket):
+		c.Field = "email"
+	case errors.Is(err, store.ErrSectionOutOfService), !s.store.Layout().HasSection(seat.Section):
+		c.Field = "section"
+	default:
+		c.Field = "seat_number"
+	}
+
+	var conflict *store.ConflictError
+	if errors.As(err, &conflict) {
+		c.ConflictingRow = rowOf(conflict.Other)
+		c.Description = fmt.Sprintf("%v (row %d)", conflict.Err, c.ConflictingRow)
+	}
+	return c
+}
+
+func conflictedRows(conflicts []*ticket.ImportConflict) int {
+	rows := make(map[int32]bool)
+	for _, c := range conflicts {
+		rows[c.Row] = true
+	}
+	return len(rows)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/config"
+	"github.com/cloudbees/train-ticket-service/internal/model"
+	"github.com/cloudbees/train-ticket-service/internal/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// importStream feeds requests to ImportBookings and keeps its response.
+type importStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	reqs []*ticket.ImportBookingsRequest
+	resp *ticket.ImportBookingsResponse
+}
+
+func (s *importStream) Context() context.Context { return s.ctx }
+
+func (s *importStream) Recv() (*ticket.ImportBookingsRequest, error) {
+	if len(s.reqs) == 0 {
+		return nil, io.EOF
+	}
+	req := s.reqs[0]
+	s.reqs = s.reqs[1:]
+	return req, nil
+}
+
+func (s *importStream) SendAndClose(resp *ticket.ImportBookingsResponse) error {
+	s.resp = resp
+	return nil
+}
+
+func TestImportBookings(t *testing.T) {
+	s := store.NewStore()
+	service := newTestService(s)
+	ctx := context.Background()
+
+	user := model.User{Email: "existing@example.com", FirstName: "Test", LastName: "User"}
+	if _, err := s.PurchaseTicket(ctx, user, config.RouteFrom, config.RouteTo, config.TicketPriceCents); err != nil {
+		t.Fatalf("Failed to purchase ticket: %v", err)
+	}
+
+	admin := metadata.NewIncomingContext(ctx, metadata.New(map[string]string{
+		"authorization": "Bearer " + createTestJWT("admin@example.com", "Admin", "User", "admin"),
+	}))
+
+	rows := []*ticket.BookingRow{
+		{Row: 2, FirstName: "Ann", LastName: "Lee", Email: "ann@example.com", Section: "B", SeatNumber: 1},
+		{Row: 3, FirstName: "Bo", LastName: "Ng", Email: "EXISTING@example.com", Section: "B", SeatNumber: 2},
+		{Row: 4, FirstName: "Cy", LastName: "Ro", Email: "cy@example.com", Section: "B", SeatNumber: 1},
+		{Row: 5, FirstName: "", LastName: "Ma", Email: "dee@example.com", Section: "B", SeatNumber: 3},
+	}
+	stream := &importStream{ctx: admin, reqs: []*ticket.ImportBookingsRequest{
+		{Commit: true, Rows: rows[:2]},
+		{Rows: rows[2:]},
+	}}
+	if err := service.ImportBookings(stream); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	resp := stream.resp
+	if resp.Committed || resp.TotalRows != 4 || resp.ValidRows != 1 {
+		t.Fatalf("Expected 1 of 4 valid rows and no commit, got %+v", resp)
+	}
+	want := []struct {
+		row            int32
+		field          string
+		reason         string
+		conflictingRow int32
+	}{
+		{3, "email", "DUPLICATE_TICKET", 0},
+		{4, "seat_number", "SEAT_OCCUPIED", 2},
+		{5, "first_name", "", 0},
+	}
+	if len(resp.Conflicts) != len(want) {
+		t.Fatalf("Expected %d conflicts, got %v", len(want), resp.Conflicts)
+	}
+	for i, w := range want {
+		c := resp.Conflicts[i]
+		if c.Row != w.row || c.Field != w.field || c.Reason != w.reason || c.ConflictingRow != w.conflictingRow {
+			t.Errorf("Conflict %d: expected %+v, got %v", i, w, c)
+		}
+	}
+	if len(s.GetAllAllocations("B")) != 0 {
+		t.Error("Expected a rejected import to book nobody")
+	}
+
+	stream = &importStream{ctx: admin, reqs: []*ticket.ImportBookingsRequest{{Commit: true, Rows: rows[:1]}}}
+	if err := service.ImportBookings(stream); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !stream.resp.Committed {
+		t.Fatalf("Expected the import to commit, got %v", stream.resp.Conflicts)
+	}
+	imported, err := s.GetTicketByEmail("ann@example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if imported.PurchasedBy != "admin@example.com" || imported.PricePaid != config.TicketPriceCents {
+		t.Errorf("Expected a ticket bought by the admin at the fare, got %+v", imported)
+	}
+
+	userCtx := metadata.NewIncomingContext(ctx, metadata.New(map[string]string{
+		"authorization": "Bearer " + createTestJWT("ann@example.com", "Ann", "Lee", "user"),
+	}))
+	if err := service.ImportBookings(&importStream{ctx: userCtx}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"time"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/audit"
+	"github.com/cloudbees/train-ticket-service/internal/manifest"
+	"github.com/cloudbees/train-ticket-service/internal/model"
+	"github.com/cloudbees/train-ticket-service/internal/store"
+	"github.com/cloudbees/train-ticket-service/internal/validation"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// manifestChunkSize bounds the data in each message of an export.
+const manifestChunkSize = 32 << 10
+
+// ExportManifest streams the confirmed passengers, sorted by section and
+// seat, as a file. Unverified holds are not passengers yet and are left out.
+func (s *TicketService) ExportManifest(req *ticket.ExportManifestRequest, stream ticket.TicketService_ExportManifestServer) error {
+	userClaims, err := s.authenticate(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	if !userClaims.IsAdmin() {
+		return status.Error(codes.PermissionDenied, "admin access required")
+	}
+
+	opts := manifest.Options{
+		Format:     req.Format,
+		Fields:     req.Fields,
+		MaskEmails: req.MaskEmails,
+		Title:      fmt.Sprintf("Passenger manifest, %s to %s, %s", s.routeFrom, s.routeTo, time.Now().UTC().Format(time.RFC3339)),
+	}
+	if opts.Format == "" {
+		opts.Format = manifest.FormatCSV
+	}
+	if len(opts.Fields) == 0 {
+		opts.Fields = manifest.DefaultFields
+	}
+
+	var violations validation.Violations
+	contentType, err := manifest.ContentType(opts.Format)
+	violations.Check("format", err)
+	violations.Check("fields", manifest.CheckFields(opts.Fields))
+	if req.Section != "" && !s.store.Layout().HasSection(req.Section) {
+		violations.Check("section", errors.New("is not a section of this train"))
+	}
+	if err := violations.Err(); err != nil {
+		return err
+	}
+
+	tickets := s.store.ListAllocations(store.AllocationQuery{
+		Section: req.Section,
+		Status:  model.TicketStatusConfirmed,
+	}).Allocations
+
+	subject := "manifest"
+	if req.Section != "" {
+		subject += " section " + req.Section
+	}
+	s.audit.Record(audit.Entry{
+		Actor:     userClaims.Email,
+		ActorRole: userClaims.Role,
+		Subject:   subject,
+		Method:    ticket.TicketService_ExportManifest_FullMethodName,
+		Action:    "export_manifest",
+		Allowed:   true,
+	})
+
+	cw := &chunkWriter{stream: stream, contentType: contentType}
+	w := bufio.NewWriterSize(cw, manifestChunkSize)
+	if err := manifest.Write(w, opts, tickets); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if !cw.sent {
+		// An empty file still tells the client its content type
+		return stream.Send(&httpbody.HttpBody{ContentType: contentType})
+	}
+	return nil
+}
+
+// chunkWriter sends each write as one HttpBody message.
+type chunkWriter struct {
+	stream      ticket.TicketService_ExportManifestServer
+	contentType string
+	sent        bool
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	if err := w.stream.Send(&httpbody.HttpBody{ContentType: w.contentType, Data: p}); err != nil {
+		return 0, err
+	}
+	w.sent = true
+	return len(p), nil
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/config"
+	"github.com/cloudbees/train-ticket-service/internal/model"
+	"github.com/cloudbees/train-ticket-service/internal/store"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// bodyStream collects the messages of an HttpBody stream.
+type bodyStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	bodies []*httpbody.HttpBody
+}
+
+func (s *bodyStream) Context() context.Context { return s.ctx }
+
+func (s *bodyStream) Send(b *httpbody.HttpBody) error {
+	s.bodies = append(s.bodies, b)
+	return nil
+}
+
+func (s *bodyStream) data() string {
+	var sb strings.Builder
+	for _, b := range s.bodies {
+		sb.Write(b.Data)
+	}
+	return sb.String()
+}
+
+func TestExportManifest(t *testing.T) {
+	s := store.NewStore()
+	service := newTestService(s)
+	ctx := context.Background()
+
+	for _, email := range []string{"first@example.com", "second@example.com"} {
+		user := model.User{Email: email, FirstName: "Test", LastName: "User"}
+		if _, err := s.PurchaseTicket(ctx, user, config.RouteFrom, config.RouteTo, config.TicketPriceCents); err != nil {
+			t.Fatalf("Failed to purchase ticket: %v", err)
+		}
+	}
+	if _, err := s.ModifySeat(context.Background(), "first@example.com", "B", 1); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := s.HoldTicket(ctx, model.User{Email: "held@example.com"}, config.RouteFrom, config.RouteTo, config.TicketPriceCents, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	admin := metadata.NewIncomingContext(ctx, metadata.New(map[string]string{
+		"authorization": "Bearer " + createTestJWT("admin@example.com", "Admin", "User", "admin"),
+	}))
+
+	stream := &bodyStream{ctx: admin}
+	req := &ticket.ExportManifestRequest{Fields: []string{"section", "seat_number", "email"}, MaskEmails: true}
+	if err := service.ExportManifest(req, stream); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	want := "section,seat_number,email\nA,2,s***@example.com\nB,1,f***@example.com\n"
+	if got := stream.data(); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if ct := stream.bodies[0].ContentType; ct != "text/csv; charset=utf-8" {
+		t.Errorf("Expected CSV content type, got %q", ct)
+	}
+
+	stream = &bodyStream{ctx: admin}
+	if err := service.ExportManifest(&ticket.ExportManifestRequest{Format: "jsonl", Section: "C"}, stream); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an unknown section, got %v", err)
+	}
+	if err := service.ExportManifest(&ticket.ExportManifestRequest{Format: "xlsx"}, stream); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an unknown format, got %v", err)
+	}
+
+	user := metadata.NewIncomingContext(ctx, metadata.New(map[string]string{
+		"authorization": "Bearer " + createTestJWT("first@example.com", "Test", "User", "user"),
+	}))
+	if err := service.ExportManifest(&ticket.ExportManifestRequest{}, &bodyStream{ctx: user}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied, got %v", err)
+	}
+}
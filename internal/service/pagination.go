@@ -0,0 +1,114 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/model"
+	"github.com/cloudbees/train-ticket-service/internal/store"
+	"github.com/cloudbees/train-ticket-service/internal/validation"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// pageToken resumes a ViewAllocations listing. It carries the filters and
+// ordering it was issued for, so it cannot be replayed against another query.
+type pageToken struct {
+	Section    string              `json:"section,omitempty"`
+	Search     string              `json:"search,omitempty"`
+	Status     string              `json:"status,omitempty"`
+	OrderBy    string              `json:"order_by"`
+	Descending bool                `json:"desc,omitempty"`
+	After      store.AllocationKey `json:"after"`
+}
+
+func encodePageToken(q store.AllocationQuery, last *model.Ticket) string {
+	data, _ := json.Marshal(pageToken{
+		Section:    q.Section,
+		Search:     q.Search,
+		Status:     q.Status,
+		OrderBy:    q.OrderBy,
+		Descending: q.Descending,
+		After:      store.KeyOf(last),
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// allocationQuery validates a ViewAllocations request and turns it into a store query.
+func allocationQuery(req *ticket.ViewAllocationsRequest) (store.AllocationQuery, error) {
+	q := store.AllocationQuery{
+		Section: req.Section,
+		Search:  strings.TrimSpace(req.Search),
+		Status:  req.Status,
+		Limit:   defaultPageSize,
+	}
+
+	var violations validation.Violations
+	switch {
+	case req.PageSize < 0:
+		violations.Check("page_size", errors.New("must not be negative"))
+	case req.PageSize > 0:
+		q.Limit = min(int(req.PageSize), maxPageSize)
+	}
+
+	var err error
+	q.OrderBy, q.Descending, err = parseOrderBy(req.OrderBy)
+	violations.Check("order_by", err)
+
+	switch q.Status {
+	case "", model.TicketStatusConfirmed, model.TicketStatusPendingVerification:
+	default:
+		violations.Check("status", fmt.Errorf("must be %q or %q", model.TicketStatusConfirmed, model.TicketStatusPendingVerification))
+	}
+
+	if req.PageToken != "" {
+		after, err := decodePageToken(req.PageToken, q)
+		violations.Check("page_token", err)
+		q.After = after
+	}
+
+	return q, violations.Err()
+}
+
+func decodePageToken(token string, q store.AllocationQuery) (*store.AllocationKey, error) {
+	var t pageToken
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(data, &t)
+	}
+	if err != nil {
+		return nil, errors.New("is not a valid page token")
+	}
+	if t.Section != q.Section || t.Search != q.Search || t.Status != q.Status || t.OrderBy != q.OrderBy || t.Descending != q.Descending {
+		return nil, errors.New("was issued for different filters or ordering")
+	}
+	return &t.After, nil
+}
+
+// parseOrderBy reads "field" or "field desc".
+func parseOrderBy(orderBy string) (string, bool, error) {
+	fields := strings.Fields(strings.ToLower(orderBy))
+	if len(fields) == 0 {
+		return store.OrderBySeat, false, nil
+	}
+
+	descending := false
+	if len(fields) == 2 && (fields[1] == "desc" || fields[1] == "asc") {
+		descending = fields[1] == "desc"
+		fields = fields[:1]
+	}
+	if len(fields) == 1 {
+		switch fields[0] {
+		case store.OrderBySeat, store.OrderByName, store.OrderByEmail:
+			return fields[0], descending, nil
+		}
+	}
+	return "", false, fmt.Errorf("must be %q, %q or %q, optionally followed by \" desc\"", store.OrderBySeat, store.OrderByName, store.OrderByEmail)
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"time"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/receipt"
+	"github.com/cloudbees/train-ticket-service/internal/validation"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DownloadReceipt renders the caller's e-ticket. A ticket still awaiting
+// email verification gets none, as a conductor would accept its QR code.
+func (s *TicketService) DownloadReceipt(ctx context.Context, req *ticket.DownloadReceiptRequest) (*httpbody.HttpBody, error) {
+	userClaims, err := s.authenticateWithImpersonation(ctx, ticket.TicketService_DownloadReceipt_FullMethodName)
+	if err != nil {
+		return nil, err
+	}
+
+	format := cmp.Or(req.Format, receipt.FormatHTML)
+	contentType, err := receipt.ContentType(format)
+	if err != nil {
+		var violations validation.Violations
+		violations.Check("format", err)
+		return nil, violations.Err()
+	}
+
+	t, err := s.store.GetTicketByEmail(userClaims.Email)
+	if err != nil {
+		return nil, errorStatus(err, nil)
+	}
+	if t.IsPending() {
+		return nil, reasonStatus(codes.FailedPrecondition, "ticket is awaiting email verification",
+			ticket.ErrorReason_TICKET_PENDING, map[string]string{"email": t.User.Email})
+	}
+
+	now := time.Now()
+	token, err := s.signer.Sign(t, s.train, now)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to sign ticket: %v", err)
+	}
+
+	layout := s.store.Layout()
+	var buf bytes.Buffer
+	err = s.receipts.Render(&buf, format, receipt.Data{
+		From:        t.From,
+		To:          t.To,
+		FirstName:   t.User.FirstName,
+		LastName:    t.User.LastName,
+		Email:       t.User.Email,
+		Section:     t.Seat.Section,
+		SeatNumber:  t.Seat.SeatNumber,
+		Row:         layout.Row(t.Seat.SeatNumber),
+		Attribute:   layout.SeatAttribute(t.Seat.SeatNumber),
+		PricePaid:   t.PricePaid,
+		PurchasedBy: t.PurchasedBy,
+		IssuedAt:    now,
+		Token:       token,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to render receipt: %v", err)
+	}
+
+	return &httpbody.HttpBody{ContentType: contentType, Data: buf.Bytes()}, nil
+}
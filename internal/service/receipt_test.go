@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/config"
+	"github.com/cloudbees/train-ticket-service/internal/model"
+	"github.com/cloudbees/train-ticket-service/internal/store"
+	"github.com/cloudbees/train-ticket-service/internal/tickettoken"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestDownloadReceipt(t *testing.T) {
+	s := store.NewStore()
+	signer := tickettoken.GenerateSigner()
+	service := newTestService(s, WithTicketSigner(signer))
+	ctx := context.Background()
+
+	user := model.User{Email: "john@example.com", FirstName: "John", LastName: "Doe"}
+	if _, err := s.PurchaseTicket(ctx, user, config.RouteFrom, config.RouteTo, config.TicketPriceCents); err != nil {
+		t.Fatalf("Failed to purchase ticket: %v", err)
+	}
+	userCtx := metadata.NewIncomingContext(ctx, metadata.New(map[string]string{
+		"authorization": "Bearer " + createTestJWT("john@example.com", "John", "Doe", "user"),
+	}))
+
+	body, err := service.DownloadReceipt(userCtx, &ticket.DownloadReceiptRequest{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if body.ContentType != "text/html; charset=utf-8" {
+		t.Errorf("Expected an HTML receipt, got %q", body.ContentType)
+	}
+
+	// The token printed on the receipt verifies with the signer's key
+	token := regexp.MustCompile(`[\w-]+\.[\w-]+\.[\w-]+`).FindString(string(body.Data[strings.Index(string(body.Data), `class="token"`):]))
+	var claims tickettoken.Claims
+	if _, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return signer.PublicKey(), nil
+	}); err != nil {
+		t.Fatalf("Expected a valid ticket token, got %q: %v", token, err)
+	}
+	if claims.Train != "London-France" || claims.Section != "A" || claims.SeatNumber != 1 || claims.Name != "John Doe" {
+		t.Errorf("Unexpected claims %+v", claims)
+	}
+
+	body, err = service.DownloadReceipt(userCtx, &ticket.DownloadReceiptRequest{Format: "pdf"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if body.ContentType != "application/pdf" || !strings.HasPrefix(string(body.Data), "%PDF-") {
+		t.Errorf("Expected a PDF receipt, got %q", body.ContentType)
+	}
+
+	if _, err := service.DownloadReceipt(userCtx, &ticket.DownloadReceiptRequest{Format: "png"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an unknown format, got %v", err)
+	}
+
+	if _, err := s.HoldTicket(ctx, model.User{Email: "held@example.com", FirstName: "Held", LastName: "User"}, config.RouteFrom, config.RouteTo, config.TicketPriceCents, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	heldCtx := metadata.NewIncomingContext(ctx, metadata.New(map[string]string{
+		"authorization": "Bearer " + createTestJWT("held@example.com", "Held", "User", "user"),
+	}))
+	if _, err := service.DownloadReceipt(heldCtx, &ticket.DownloadReceiptRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for an unverified ticket, got %v", err)
+	}
+}
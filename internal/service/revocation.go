@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/store"
+	"github.com/cloudbees/train-ticket-service/internal/tickettoken"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SyncRevocations gives conductors what they need to check ticket tokens
+// offline. Revocations name tickets by their random IDs only, so the call is
+// public like the seat map.
+func (s *TicketService) SyncRevocations(ctx context.Context, req *ticket.SyncRevocationsRequest) (*ticket.SyncRevocationsResponse, error) {
+	if req.Since < 0 {
+		return nil, status.Error(codes.InvalidArgument, "since must not be negative")
+	}
+
+	pub := s.signer.PublicKey()
+	revocations, cursor := s.store.Revocations(store.RevocationCursor{Epoch: req.Epoch, Seq: req.Since})
+
+	resp := &ticket.SyncRevocationsResponse{
+		Train: s.train,
+		Keys: []*ticket.TicketKey{{
+			KeyId:     tickettoken.KeyID(pub),
+			Algorithm: jwt.SigningMethodEdDSA.Alg(),
+			PublicKey: pub,
+		}},
+		Cursor: cursor.Seq,
+		Epoch:  cursor.Epoch,
+	}
+	for _, r := range revocations {
+		resp.Revocations = append(resp.Revocations, &ticket.Revocation{
+			Seq:       r.Seq,
+			TicketId:  r.TicketID,
+			Revision:  r.Revision,
+			Reason:    r.Reason,
+			RevokedAt: r.RevokedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return resp, nil
+}
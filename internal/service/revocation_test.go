@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/model"
+	"github.com/cloudbees/train-ticket-service/internal/store"
+	"github.com/cloudbees/train-ticket-service/internal/tickettoken"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestSyncRevocations(t *testing.T) {
+	service := newTestService(store.NewStore())
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + createTestJWT("john@example.com", "John", "Doe", "user"),
+	}))
+
+	purchase, err := service.PurchaseTicket(ctx, &ticket.PurchaseTicketRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if purchase.Receipt.TicketId == "" || purchase.Receipt.TicketToken == "" {
+		t.Fatalf("Expected a ticket id and token on the receipt, got %+v", purchase.Receipt)
+	}
+
+	var verifier tickettoken.Verifier
+	sync := func() {
+		t.Helper()
+		resp, err := service.SyncRevocations(context.Background(), &ticket.SyncRevocationsRequest{
+			Since: verifier.Revocations.Cursor,
+			Epoch: verifier.Revocations.Epoch,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		verifier.Train = resp.Train
+		for _, key := range resp.Keys {
+			verifier.AddKey(key.PublicKey)
+		}
+		for _, r := range resp.Revocations {
+			verifier.Revocations.Add(r.TicketId, r.Revision, r.Reason)
+		}
+		verifier.Revocations.Cursor = resp.Cursor
+		verifier.Revocations.Epoch = resp.Epoch
+	}
+
+	sync()
+	claims, err := verifier.VerifyTicket(purchase.Receipt.TicketToken)
+	if err != nil {
+		t.Fatalf("Expected the purchased ticket to verify, got: %v", err)
+	}
+	if claims.ID != purchase.Receipt.TicketId || claims.Train != "London-France" {
+		t.Errorf("Unexpected claims %+v", claims)
+	}
+
+	moved, err := service.ModifyUserSeat(ctx, &ticket.ModifyUserSeatRequest{Section: "B", SeatNumber: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	sync()
+	if _, err := verifier.VerifyTicket(purchase.Receipt.TicketToken); !errors.Is(err, tickettoken.ErrRevoked) {
+		t.Errorf("Expected the token for the old seat to be revoked, got %v", err)
+	}
+	if _, err := verifier.VerifyTicket(moved.Receipt.TicketToken); err != nil {
+		t.Errorf("Expected the token for the new seat to verify, got %v", err)
+	}
+
+	if _, err := service.RemoveUserFromTrain(ctx, &ticket.RemoveUserFromTrainRequest{}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	sync()
+	if _, err := verifier.VerifyTicket(moved.Receipt.TicketToken); !errors.Is(err, tickettoken.ErrRevoked) {
+		t.Errorf("Expected a removed ticket to be revoked, got %v", err)
+	}
+	if verifier.Revocations.Tickets[claims.ID].Reason != model.RevokedRemoved || verifier.Revocations.Cursor != 2 {
+		t.Errorf("Unexpected revocations %+v", verifier.Revocations)
+	}
+
+	if _, err := service.SyncRevocations(context.Background(), &ticket.SyncRevocationsRequest{Since: -1}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a negative cursor, got %v", err)
+	}
+}
@@ -2,88 +2,334 @@ package service
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
 
 	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/abuse"
+	"github.com/cloudbees/train-ticket-service/internal/audit"
 	"github.com/cloudbees/train-ticket-service/internal/auth"
 	"github.com/cloudbees/train-ticket-service/internal/config"
+	"github.com/cloudbees/train-ticket-service/internal/logging"
+	"github.com/cloudbees/train-ticket-service/internal/metrics"
 	"github.com/cloudbees/train-ticket-service/internal/model"
+	"github.com/cloudbees/train-ticket-service/internal/ratelimit"
+	"github.com/cloudbees/train-ticket-service/internal/receipt"
 	"github.com/cloudbees/train-ticket-service/internal/store"
+	"github.com/cloudbees/train-ticket-service/internal/tickettoken"
+	"github.com/cloudbees/train-ticket-service/internal/validation"
+	"github.com/cloudbees/train-ticket-service/internal/verification"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
+const tracerName = "github.com/cloudbees/train-ticket-service/internal/service"
+
 type TicketService struct {
 	ticket.UnimplementedTicketServiceServer
-	store *store.Store
+	store   *store.Store
+	audit   audit.Recorder
+	metrics *metrics.Metrics
+
+	routeFrom          string
+	routeTo            string
+	train              string
+	ticketPriceCents   int32
+	impersonationRoles []string
+	serviceIdentities  map[string]auth.ServiceIdentity
+	// tokens checks caller JWTs; nil rejects every bearer token
+	tokens *auth.Verifier
+
+	purchaseAuthMode    string
+	verificationHoldTTL time.Duration
+	verificationCodes   *verification.Codes
+	verificationSend    verification.Sender
+
+	// abuse guards the public purchase path; nil disables it
+	abuse *abuse.Guard
+
+	signer   *tickettoken.Signer
+	receipts *receipt.Renderer
+
+	closing   chan struct{}
+	closeOnce sync.Once
 }
 
-func NewTicketService(s *store.Store) *TicketService {
-	return &TicketService{
-		store: s,
+type Option func(*TicketService)
+
+// WithAuditRecorder sets where impersonated and on-behalf-of calls are recorded.
+func WithAuditRecorder(r audit.Recorder) Option {
+	return func(s *TicketService) {
+		s.audit = r
 	}
 }
 
-func (s *TicketService) PurchaseTicket(ctx context.Context, req *ticket.PurchaseTicketRequest) (*ticket.PurchaseTicketResponse, error) {
-	if req.FirstName == "" || req.LastName == "" || req.Email == "" {
-		return nil, status.Error(codes.InvalidArgument, "first_name, last_name, and email are required")
+// WithMetrics sets where purchase, removal and seat change counters are recorded.
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(s *TicketService) {
+		s.metrics = m
+	}
+}
+
+// WithTokenVerifier sets the key caller JWTs must be signed with.
+func WithTokenVerifier(v *auth.Verifier) Option {
+	return func(s *TicketService) {
+		s.tokens = v
+	}
+}
+
+// WithPurchaseAuthMode selects whether PurchaseTicket requires a JWT, see config.PurchaseAuth*.
+func WithPurchaseAuthMode(mode string) Option {
+	return func(s *TicketService) {
+		s.purchaseAuthMode = mode
+	}
+}
+
+// WithVerificationSender sets how verification codes for anonymous purchases are delivered.
+func WithVerificationSender(sender verification.Sender) Option {
+	return func(s *TicketService) {
+		s.verificationSend = sender
+	}
+}
+
+// WithAbuseGuard applies bot and scalping controls to PurchaseTicket.
+func WithAbuseGuard(g *abuse.Guard) Option {
+	return func(s *TicketService) {
+		s.abuse = g
+	}
+}
+
+// WithTicketSigner sets the key that signs the tokens on e-tickets.
+func WithTicketSigner(signer *tickettoken.Signer) Option {
+	return func(s *TicketService) {
+		s.signer = signer
 	}
+}
 
-	user := model.User{
-		FirstName: req.FirstName,
-		LastName:  req.LastName,
-		Email:     req.Email,
+// WithReceiptRenderer sets the templates e-tickets are rendered with.
+func WithReceiptRenderer(r *receipt.Renderer) Option {
+	return func(s *TicketService) {
+		s.receipts = r
 	}
+}
+
+// WithConfig applies the route, pricing and auth settings from cfg.
+func WithConfig(cfg *config.Config) Option {
+	return func(s *TicketService) {
+		s.routeFrom = cfg.Route.From
+		s.routeTo = cfg.Route.To
+		s.train = cfg.TrainName()
+		s.ticketPriceCents = cfg.Pricing.TicketPriceCents
+		s.impersonationRoles = cfg.Auth.ImpersonationRoles
+		s.purchaseAuthMode = cfg.Auth.PurchaseMode
+		s.verificationHoldTTL = cfg.Auth.VerificationHoldTTL
 
-	t, err := s.store.PurchaseTicket(user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+		s.serviceIdentities = make(map[string]auth.ServiceIdentity, len(cfg.TLS.ClientIdentities))
+		for subject, id := range cfg.TLS.ClientIdentities {
+			s.serviceIdentities[subject] = auth.ServiceIdentity{Email: id.Email, Role: id.Role}
+		}
+	}
+}
+
+func NewTicketService(s *store.Store, opts ...Option) *TicketService {
+	svc := &TicketService{
+		store:               s,
+		audit:               audit.NewLog(nil, logging.Redactor{}, nil),
+		metrics:             metrics.New(),
+		routeFrom:           config.RouteFrom,
+		routeTo:             config.RouteTo,
+		train:               config.Default().TrainName(),
+		ticketPriceCents:    config.TicketPriceCents,
+		impersonationRoles:  config.ImpersonationRoles,
+		purchaseAuthMode:    config.DefaultPurchaseAuthMode,
+		verificationHoldTTL: config.VerificationHoldTTL,
+		verificationSend:    verification.LogSender{Logger: slog.Default()},
+		closing:             make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	svc.verificationCodes = verification.NewCodes(svc.verificationHoldTTL)
+	if svc.signer == nil {
+		svc.signer = tickettoken.GenerateSigner()
+	}
+	if svc.receipts == nil {
+		svc.receipts = receipt.Default()
+	}
+	return svc
+}
+
+func (s *TicketService) PurchaseTicket(ctx context.Context, req *ticket.PurchaseTicketRequest) (*ticket.PurchaseTicketResponse, error) {
+	user, err := validateUser("", req.FirstName, req.LastName, req.Email)
 	if err != nil {
-		switch err {
-		case store.ErrUserAlreadyHasTicket:
-			return nil, status.Error(codes.AlreadyExists, err.Error())
-		case store.ErrTrainFull:
-			return nil, status.Error(codes.ResourceExhausted, err.Error())
-		default:
-			return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
+	}
+
+	anonymous := false
+	userClaims, err := s.extractUser(ctx)
+	switch {
+	case err == nil:
+		if userClaims.Email != user.Email {
+			return nil, status.Error(codes.PermissionDenied, "email does not match authenticated user")
+		}
+	case errors.Is(err, auth.ErrNoMetadata) || errors.Is(err, auth.ErrNoAuthHeader):
+		if s.purchaseAuthMode == config.PurchaseAuthRequired {
+			return nil, status.Error(codes.Unauthenticated, "authentication is required to purchase a ticket")
+		}
+		anonymous = true
+	default:
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	var reservation *abuse.Reservation
+	if s.abuse != nil {
+		domainTickets := s.store.CountByEmailDomain(abuse.Domain(user.Email))
+		reservation, err = s.abuse.Check(user.Email, ratelimit.ClientIP(ctx), domainTickets, req.ChallengeToken, req.ChallengeNonce)
+		if err != nil {
+			return nil, abuseError(err, user.Email)
 		}
 	}
 
+	if anonymous {
+		resp, err := s.holdAnonymousPurchase(ctx, user)
+		s.endReservation(reservation, err)
+		return resp, err
+	}
+
+	t, err := s.store.PurchaseTicket(ctx, user, s.routeFrom, s.routeTo, s.quote(ctx))
+	s.endReservation(reservation, err)
+	if err != nil {
+		return nil, s.purchaseError(err, user.Email)
+	}
+	s.metrics.RecordPurchase(metrics.PurchaseSelf)
+
+	r, err := s.issueReceipt(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ticket.PurchaseTicketResponse{
+		Receipt: r,
+	}, nil
+}
+
+// endReservation commits the abuse guard's reservation for a purchase that
+// went through, and releases it for one that failed.
+func (s *TicketService) endReservation(r *abuse.Reservation, err error) {
+	if s.abuse == nil {
+		return
+	}
+	if err != nil {
+		s.abuse.Release(r)
+		return
+	}
+	s.abuse.Commit(r)
+}
+
+// holdAnonymousPurchase reserves a seat for an unauthenticated purchase and
+// sends a verification code to the email. The seat is released if the code
+// is not confirmed through VerifyPurchase before the hold expires.
+func (s *TicketService) holdAnonymousPurchase(ctx context.Context, user model.User) (*ticket.PurchaseTicketResponse, error) {
+	expiresAt := time.Now().Add(s.verificationHoldTTL)
+
+	t, err := s.store.HoldTicket(ctx, user, s.routeFrom, s.routeTo, s.quote(ctx), expiresAt)
+	if err != nil {
+		return nil, s.purchaseError(err, user.Email)
+	}
+
+	code, _, err := s.verificationCodes.Issue(user.Email)
+	if err == nil {
+		err = s.verificationSend.SendVerificationCode(user.Email, code)
+	}
+	if err != nil {
+		s.store.RemoveTicket(ctx, user.Email)
+		return nil, status.Error(codes.Unavailable, "failed to send verification code")
+	}
+
+	r, err := s.issueReceipt(t)
+	if err != nil {
+		return nil, err
+	}
+
 	return &ticket.PurchaseTicketResponse{
-		Receipt: convertTicketToReceipt(t),
+		Receipt: r,
+	}, nil
+}
+
+func (s *TicketService) VerifyPurchase(ctx context.Context, req *ticket.VerifyPurchaseRequest) (*ticket.VerifyPurchaseResponse, error) {
+	email := validation.NormalizeEmail(req.Email)
+	if email == "" || req.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "email and code are required")
+	}
+
+	metadata := map[string]string{"email": email}
+	if err := s.verificationCodes.Check(email, req.Code); err != nil {
+		return nil, errorStatus(err, metadata)
+	}
+
+	t, err := s.store.ConfirmTicket(ctx, email)
+	if errors.Is(err, store.ErrTicketNotFound) {
+		return nil, reasonStatus(codes.NotFound, "ticket hold has expired or was removed", ticket.ErrorReason_HOLD_EXPIRED, metadata)
+	}
+	if err != nil {
+		return nil, errorStatus(err, metadata)
+	}
+	s.metrics.RecordPurchase(metrics.PurchaseVerified)
+
+	r, err := s.issueReceipt(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ticket.VerifyPurchaseResponse{
+		Receipt: r,
 	}, nil
 }
 
 func (s *TicketService) ViewUserReceipt(ctx context.Context, req *ticket.ViewUserReceiptRequest) (*ticket.ViewUserReceiptResponse, error) {
-	userClaims, err := auth.ExtractUserFromContext(ctx)
+	userClaims, err := s.authenticateWithImpersonation(ctx, ticket.TicketService_ViewUserReceipt_FullMethodName)
 	if err != nil {
-		return nil, status.Error(codes.Unauthenticated, err.Error())
+		return nil, err
 	}
 
 	t, err := s.store.GetTicketByEmail(userClaims.Email)
 	if err != nil {
-		if err == store.ErrTicketNotFound {
-			return nil, status.Error(codes.NotFound, "ticket not found")
-		}
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, errorStatus(err, nil)
+	}
+
+	r, err := s.issueReceipt(t)
+	if err != nil {
+		return nil, err
 	}
 
 	return &ticket.ViewUserReceiptResponse{
-		Receipt: convertTicketToReceipt(t),
+		Receipt: r,
 	}, nil
 }
 
 func (s *TicketService) ViewAllocations(ctx context.Context, req *ticket.ViewAllocationsRequest) (*ticket.ViewAllocationsResponse, error) {
-	userClaims, err := auth.ExtractUserFromContext(ctx)
+	userClaims, err := s.authenticate(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Unauthenticated, err.Error())
+		return nil, err
 	}
 
 	if !userClaims.IsAdmin() {
 		return nil, status.Error(codes.PermissionDenied, "admin access required")
 	}
 
-	allocations := s.store.GetAllAllocations(req.Section)
+	q, err := allocationQuery(req)
+	if err != nil {
+		return nil, err
+	}
+	page := s.store.ListAllocations(q)
 
-	protoAllocations := make([]*ticket.Allocation, 0, len(allocations))
-	for _, t := range allocations {
+	protoAllocations := make([]*ticket.Allocation, 0, len(page.Allocations))
+	for _, t := range page.Allocations {
 		protoAllocations = append(protoAllocations, &ticket.Allocation{
 			Section:    t.Seat.Section,
 			SeatNumber: t.Seat.SeatNumber,
@@ -92,18 +338,24 @@ func (s *TicketService) ViewAllocations(ctx context.Context, req *ticket.ViewAll
 				LastName:  t.User.LastName,
 				Email:     t.User.Email,
 			},
+			Status: t.Status,
 		})
 	}
 
-	return &ticket.ViewAllocationsResponse{
+	resp := &ticket.ViewAllocationsResponse{
 		Allocations: protoAllocations,
-	}, nil
+		TotalSize:   int32(page.Total),
+	}
+	if page.More {
+		resp.NextPageToken = encodePageToken(q, page.Allocations[len(page.Allocations)-1])
+	}
+	return resp, nil
 }
 
 func (s *TicketService) RemoveUserFromTrain(ctx context.Context, req *ticket.RemoveUserFromTrainRequest) (*ticket.RemoveUserFromTrainResponse, error) {
-	userClaims, err := auth.ExtractUserFromContext(ctx)
+	userClaims, err := s.authenticate(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Unauthenticated, err.Error())
+		return nil, err
 	}
 
 	targetEmail := userClaims.Email
@@ -111,16 +363,14 @@ func (s *TicketService) RemoveUserFromTrain(ctx context.Context, req *ticket.Rem
 		if !userClaims.IsAdmin() {
 			return nil, status.Error(codes.PermissionDenied, "only admin can remove other users")
 		}
-		targetEmail = req.Email
+		targetEmail = validation.NormalizeEmail(req.Email)
 	}
 
-	err = s.store.RemoveTicket(targetEmail)
+	err = s.store.RemoveTicket(ctx, targetEmail)
 	if err != nil {
-		if err == store.ErrTicketNotFound {
-			return nil, status.Error(codes.NotFound, err.Error())
-		}
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, errorStatus(err, nil)
 	}
+	s.metrics.RecordRemovals(1)
 
 	return &ticket.RemoveUserFromTrainResponse{
 		Success: true,
@@ -129,9 +379,9 @@ func (s *TicketService) RemoveUserFromTrain(ctx context.Context, req *ticket.Rem
 }
 
 func (s *TicketService) ModifyUserSeat(ctx context.Context, req *ticket.ModifyUserSeatRequest) (*ticket.ModifyUserSeatResponse, error) {
-	userClaims, err := auth.ExtractUserFromContext(ctx)
+	userClaims, err := s.authenticate(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Unauthenticated, err.Error())
+		return nil, err
 	}
 
 	if req.Section == "" || req.SeatNumber == 0 {
@@ -143,28 +393,485 @@ func (s *TicketService) ModifyUserSeat(ctx context.Context, req *ticket.ModifyUs
 		if !userClaims.IsAdmin() {
 			return nil, status.Error(codes.PermissionDenied, "only admin can modify other users' seats")
 		}
-		targetEmail = req.Email
+		targetEmail = validation.NormalizeEmail(req.Email)
 	}
 
-	t, err := s.store.ModifySeat(targetEmail, req.Section, req.SeatNumber)
+	t, err := s.store.ModifySeat(ctx, targetEmail, req.Section, req.SeatNumber)
 	if err != nil {
-		switch err {
-		case store.ErrTicketNotFound:
-			return nil, status.Error(codes.NotFound, err.Error())
-		case store.ErrSeatAlreadyOccupied:
-			return nil, status.Error(codes.AlreadyExists, err.Error())
-		case store.ErrInvalidSeat:
-			return nil, status.Error(codes.InvalidArgument, err.Error())
-		default:
-			return nil, status.Error(codes.Internal, err.Error())
-		}
+		return nil, s.seatError(err, req.Section, req.SeatNumber)
+	}
+	s.metrics.RecordModifications(1)
+
+	r, err := s.issueReceipt(t)
+	if err != nil {
+		return nil, err
 	}
 
 	return &ticket.ModifyUserSeatResponse{
-		Receipt: convertTicketToReceipt(t),
+		Receipt: r,
+	}, nil
+}
+
+func (s *TicketService) RequestSeatSwap(ctx context.Context, req *ticket.RequestSeatSwapRequest) (*ticket.RequestSeatSwapResponse, error) {
+	userClaims, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counterpartyEmail := validation.NormalizeEmail(req.CounterpartyEmail)
+	if counterpartyEmail == "" {
+		return nil, status.Error(codes.InvalidArgument, "counterparty_email is required")
+	}
+
+	requesterEmail := userClaims.Email
+	if req.Email != "" {
+		if !userClaims.IsAdmin() {
+			return nil, status.Error(codes.PermissionDenied, "only admin can request swaps for other users")
+		}
+		requesterEmail = validation.NormalizeEmail(req.Email)
+	}
+
+	var swap *model.SeatSwap
+	if req.Force {
+		if !userClaims.IsAdmin() {
+			return nil, status.Error(codes.PermissionDenied, "only admin can force a seat swap")
+		}
+		swap, err = s.store.SwapSeats(ctx, requesterEmail, counterpartyEmail)
+	} else {
+		swap, err = s.store.RequestSeatSwap(ctx, requesterEmail, counterpartyEmail)
+	}
+	if err != nil {
+		return nil, swapError(err, "")
+	}
+	if swap.Status == model.SwapStatusCompleted {
+		s.metrics.RecordModifications(2)
+	}
+
+	return &ticket.RequestSeatSwapResponse{
+		Swap: convertSeatSwap(swap),
+	}, nil
+}
+
+func (s *TicketService) AcceptSeatSwap(ctx context.Context, req *ticket.AcceptSeatSwapRequest) (*ticket.AcceptSeatSwapResponse, error) {
+	userClaims, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.SwapId == "" {
+		return nil, status.Error(codes.InvalidArgument, "swap_id is required")
+	}
+
+	pending, err := s.store.GetSeatSwap(req.SwapId)
+	if err != nil {
+		return nil, swapError(err, req.SwapId)
+	}
+
+	if pending.CounterpartyEmail != userClaims.Email && !userClaims.IsAdmin() {
+		return nil, status.Error(codes.PermissionDenied, "only the counterparty can accept this swap")
+	}
+
+	swap, err := s.store.AcceptSeatSwap(ctx, req.SwapId)
+	if err != nil {
+		return nil, swapError(err, req.SwapId)
+	}
+	s.metrics.RecordModifications(2)
+
+	return &ticket.AcceptSeatSwapResponse{
+		Swap: convertSeatSwap(swap),
+	}, nil
+}
+
+func (s *TicketService) BulkApply(ctx context.Context, req *ticket.BulkApplyRequest) (*ticket.BulkApplyResponse, error) {
+	userClaims, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !userClaims.IsAdmin() {
+		return nil, status.Error(codes.PermissionDenied, "admin access required")
+	}
+
+	if len(req.Operations) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one operation is required")
+	}
+	if len(req.Operations) > config.MaxBulkOperations {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d operations are allowed", config.MaxBulkOperations)
+	}
+
+	ops := make([]store.BulkOp, 0, len(req.Operations))
+	for i, op := range req.Operations {
+		storeOp, err := convertBulkOperation(op)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "operation %d: %v", i, err)
+		}
+		ops = append(ops, storeOp)
+	}
+
+	results, applied := s.store.BulkApply(ctx, ops, !req.BestEffort)
+	if applied {
+		var removed, moved int
+		for i, result := range results {
+			if result.Err != nil {
+				continue
+			}
+			if ops[i].Kind == store.BulkRemove {
+				removed++
+			} else {
+				moved++
+			}
+		}
+		s.metrics.RecordRemovals(removed)
+		s.metrics.RecordModifications(moved)
+	}
+
+	resp := &ticket.BulkApplyResponse{
+		Results: make([]*ticket.BulkResult, 0, len(results)),
+		Applied: applied,
+	}
+	for i, result := range results {
+		r := &ticket.BulkResult{
+			Index:   int32(i),
+			Success: applied && result.Err == nil,
+		}
+		if result.Err != nil {
+			code, reason := classify(result.Err)
+			r.Code = code.String()
+			if reason != ticket.ErrorReason_ERROR_REASON_UNSPECIFIED {
+				r.Reason = reason.String()
+			}
+			r.Message = result.Err.Error()
+		} else if result.Seat.Section != "" {
+			r.Seat = &ticket.Seat{
+				Section:    result.Seat.Section,
+				SeatNumber: result.Seat.SeatNumber,
+			}
+		}
+		resp.Results = append(resp.Results, r)
+	}
+
+	return resp, nil
+}
+
+func (s *TicketService) DecommissionSection(ctx context.Context, req *ticket.DecommissionSectionRequest) (*ticket.DecommissionSectionResponse, error) {
+	userClaims, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !userClaims.IsAdmin() {
+		return nil, status.Error(codes.PermissionDenied, "admin access required")
+	}
+
+	overflow := req.OverflowPolicy
+	if overflow == "" {
+		overflow = model.OverflowWaitlist
+	}
+	if overflow != model.OverflowWaitlist && overflow != model.OverflowRefund {
+		return nil, status.Error(codes.InvalidArgument, "overflow_policy must be waitlist or refund")
+	}
+
+	report, err := s.store.DecommissionSection(ctx, req.Section, overflow)
+	if err != nil {
+		return nil, sectionError(err, req.Section)
+	}
+	s.metrics.RecordRemovals(len(report.Refunded))
+	s.metrics.RecordModifications(len(report.Moved))
+
+	s.audit.Record(audit.Entry{
+		Actor:     userClaims.Email,
+		ActorRole: userClaims.Role,
+		Subject:   "section " + req.Section,
+		Method:    ticket.TicketService_DecommissionSection_FullMethodName,
+		Action:    "decommission_section",
+		Allowed:   true,
+	})
+
+	return &ticket.DecommissionSectionResponse{
+		Report: convertReaccommodationReport(report),
+	}, nil
+}
+
+func (s *TicketService) ReinstateSection(ctx context.Context, req *ticket.ReinstateSectionRequest) (*ticket.ReinstateSectionResponse, error) {
+	userClaims, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !userClaims.IsAdmin() {
+		return nil, status.Error(codes.PermissionDenied, "admin access required")
+	}
+
+	placed, err := s.store.ReinstateSection(ctx, req.Section)
+	if err != nil {
+		return nil, sectionError(err, req.Section)
+	}
+	s.metrics.RecordModifications(len(placed))
+
+	s.audit.Record(audit.Entry{
+		Actor:     userClaims.Email,
+		ActorRole: userClaims.Role,
+		Subject:   "section " + req.Section,
+		Method:    ticket.TicketService_ReinstateSection_FullMethodName,
+		Action:    "reinstate_section",
+		Allowed:   true,
+	})
+
+	return &ticket.ReinstateSectionResponse{
+		Placed: convertSeatMoves(placed),
+	}, nil
+}
+
+func (s *TicketService) AdminPurchaseTicket(ctx context.Context, req *ticket.AdminPurchaseTicketRequest) (*ticket.AdminPurchaseTicketResponse, error) {
+	userClaims, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !userClaims.IsAdmin() {
+		return nil, status.Error(codes.PermissionDenied, "admin access required")
+	}
+
+	p := req.GetPassenger()
+	passenger, err := validateUser("passenger.", p.GetFirstName(), p.GetLastName(), p.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := s.store.PurchaseTicketOnBehalf(ctx, passenger, userClaims.Email, s.routeFrom, s.routeTo, s.quote(ctx))
+	if err != nil {
+		return nil, s.purchaseError(err, passenger.Email)
+	}
+	s.metrics.RecordPurchase(metrics.PurchaseOnBehalf)
+
+	s.audit.Record(audit.Entry{
+		Actor:     userClaims.Email,
+		ActorRole: userClaims.Role,
+		Subject:   passenger.Email,
+		Method:    ticket.TicketService_AdminPurchaseTicket_FullMethodName,
+		Action:    "purchase_on_behalf",
+		Allowed:   true,
+	})
+
+	r, err := s.issueReceipt(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ticket.AdminPurchaseTicketResponse{
+		Receipt: r,
 	}, nil
 }
 
+// quote prices a ticket on the configured route. Fares are flat today; the
+// span keeps pricing visible in purchase traces.
+func (s *TicketService) quote(ctx context.Context) int32 {
+	_, span := otel.Tracer(tracerName).Start(ctx, "pricing.Quote")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("route.from", s.routeFrom),
+		attribute.String("route.to", s.routeTo),
+		attribute.Int("price.cents", int(s.ticketPriceCents)),
+	)
+	return s.ticketPriceCents
+}
+
+// extractUser returns the claims from the caller's verified JWT. A caller that
+// sends no authorization header falls back to the service identity of its mTLS
+// client certificate.
+func (s *TicketService) extractUser(ctx context.Context) (*auth.UserClaims, error) {
+	userClaims, err := s.tokens.ExtractUser(ctx)
+	if errors.Is(err, auth.ErrNoMetadata) || errors.Is(err, auth.ErrNoAuthHeader) {
+		if claims, ok := auth.ExtractServiceIdentity(ctx, s.serviceIdentities); ok {
+			userClaims, err = claims, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Tickets are keyed by normalized email, whatever case the token uses
+	userClaims.Email = validation.NormalizeEmail(userClaims.Email)
+	return userClaims, nil
+}
+
+// authenticate returns the caller's claims. Impersonation is only honoured by
+// authenticateWithImpersonation, so the header is rejected here rather than ignored.
+func (s *TicketService) authenticate(ctx context.Context) (*auth.UserClaims, error) {
+	userClaims, err := s.extractUser(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if auth.ImpersonatedEmail(ctx) != "" {
+		return nil, status.Error(codes.PermissionDenied, "impersonation is not supported for this method")
+	}
+
+	return userClaims, nil
+}
+
+// authenticateWithImpersonation returns the claims of the user the call acts as.
+// Every impersonation attempt is audited, whether or not it is allowed.
+func (s *TicketService) authenticateWithImpersonation(ctx context.Context, method string) (*auth.UserClaims, error) {
+	userClaims, err := s.extractUser(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	target := validation.NormalizeEmail(auth.ImpersonatedEmail(ctx))
+	if target == "" {
+		return userClaims, nil
+	}
+
+	allowed := userClaims.HasRole(s.impersonationRoles...)
+	s.audit.Record(audit.Entry{
+		Actor:     userClaims.Email,
+		ActorRole: userClaims.Role,
+		Subject:   target,
+		Method:    method,
+		Action:    "impersonate",
+		Allowed:   allowed,
+	})
+
+	if !allowed {
+		return nil, status.Error(codes.PermissionDenied, "role is not allowed to impersonate users")
+	}
+
+	return userClaims.Impersonate(target), nil
+}
+
+// validateUser normalizes a passenger's names and email, reporting every
+// invalid field. prefix qualifies the field names, e.g. "passenger.".
+func validateUser(prefix, firstName, lastName, email string) (model.User, error) {
+	user, violations := checkUser(prefix, firstName, lastName, email)
+	return user, violations.Err()
+}
+
+// checkUser normalizes a passenger, collecting what is wrong with each field.
+func checkUser(prefix, firstName, lastName, email string) (model.User, validation.Violations) {
+	var violations validation.Violations
+	var user model.User
+	var err error
+
+	user.FirstName, err = validation.Name(firstName)
+	violations.Check(prefix+"first_name", err)
+	user.LastName, err = validation.Name(lastName)
+	violations.Check(prefix+"last_name", err)
+	user.Email, err = validation.Email(email)
+	violations.Check(prefix+"email", err)
+
+	return user, violations
+}
+
+func convertBulkOperation(op *ticket.BulkOperation) (store.BulkOp, error) {
+	switch o := op.GetOperation().(type) {
+	case *ticket.BulkOperation_Remove:
+		if o.Remove.GetEmail() == "" {
+			return store.BulkOp{}, errors.New("remove requires email")
+		}
+		return store.BulkOp{Kind: store.BulkRemove, Email: validation.NormalizeEmail(o.Remove.Email)}, nil
+	case *ticket.BulkOperation_Move:
+		if o.Move.GetEmail() == "" || o.Move.GetSection() == "" || o.Move.GetSeatNumber() == 0 {
+			return store.BulkOp{}, errors.New("move requires email, section and seat_number")
+		}
+		return store.BulkOp{Kind: store.BulkMove, Email: validation.NormalizeEmail(o.Move.Email), Section: o.Move.Section, SeatNumber: o.Move.SeatNumber}, nil
+	case *ticket.BulkOperation_Reassign:
+		if o.Reassign.GetEmail() == "" {
+			return store.BulkOp{}, errors.New("reassign requires email")
+		}
+		return store.BulkOp{Kind: store.BulkReassign, Email: validation.NormalizeEmail(o.Reassign.Email), Section: o.Reassign.Section}, nil
+	default:
+		return store.BulkOp{}, errors.New("one of remove, move or reassign must be set")
+	}
+}
+
+// convertSeatSwap leaves out the counterparty's seat until they have accepted,
+// so a proposal cannot be used to find out where someone sits.
+func convertSeatSwap(swap *model.SeatSwap) *ticket.SeatSwap {
+	converted := &ticket.SeatSwap{
+		Id:             swap.ID,
+		RequesterEmail: swap.RequesterEmail,
+		RequesterSeat: &ticket.Seat{
+			Section:    swap.RequesterSeat.Section,
+			SeatNumber: swap.RequesterSeat.SeatNumber,
+		},
+		CounterpartyEmail: swap.CounterpartyEmail,
+		Status:            swap.Status,
+	}
+	if swap.Status == model.SwapStatusCompleted {
+		converted.CounterpartySeat = &ticket.Seat{
+			Section:    swap.CounterpartySeat.Section,
+			SeatNumber: swap.CounterpartySeat.SeatNumber,
+		}
+	}
+	return converted
+}
+
+func convertReaccommodationReport(report *model.ReaccommodationReport) *ticket.ReaccommodationReport {
+	return &ticket.ReaccommodationReport{
+		Section:    report.Section,
+		Moved:      convertSeatMoves(report.Moved),
+		Waitlisted: convertDisplacedPassengers(report.Waitlisted),
+		Refunded:   convertDisplacedPassengers(report.Refunded),
+	}
+}
+
+func convertSeatMoves(moves []model.SeatMove) []*ticket.SeatMove {
+	converted := make([]*ticket.SeatMove, 0, len(moves))
+	for _, m := range moves {
+		converted = append(converted, &ticket.SeatMove{
+			User: convertUser(m.User),
+			From: convertSeat(m.From),
+			To:   convertSeat(m.To),
+		})
+	}
+	return converted
+}
+
+func convertDisplacedPassengers(passengers []model.DisplacedPassenger) []*ticket.DisplacedPassenger {
+	converted := make([]*ticket.DisplacedPassenger, 0, len(passengers))
+	for _, p := range passengers {
+		converted = append(converted, &ticket.DisplacedPassenger{
+			User:        convertUser(p.User),
+			From:        convertSeat(p.From),
+			RefundCents: p.RefundCents,
+		})
+	}
+	return converted
+}
+
+func convertUser(u model.User) *ticket.User {
+	return &ticket.User{
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Email:     u.Email,
+	}
+}
+
+func convertSeat(seat model.Seat) *ticket.Seat {
+	return &ticket.Seat{
+		Section:    seat.Section,
+		SeatNumber: seat.SeatNumber,
+	}
+}
+
+// issueReceipt converts t and, once it is confirmed, signs the token a
+// conductor checks it by.
+func (s *TicketService) issueReceipt(t *model.Ticket) (*ticket.Receipt, error) {
+	r := convertTicketToReceipt(t)
+	if t.IsPending() {
+		return r, nil
+	}
+
+	token, err := s.signer.Sign(t, s.train, time.Now())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to sign ticket: %v", err)
+	}
+	r.TicketToken = token
+	return r, nil
+}
+
 func convertTicketToReceipt(t *model.Ticket) *ticket.Receipt {
 	return &ticket.Receipt{
 		From: t.From,
@@ -179,6 +886,156 @@ func convertTicketToReceipt(t *model.Ticket) *ticket.Receipt {
 			Section:    t.Seat.Section,
 			SeatNumber: t.Seat.SeatNumber,
 		},
+		PurchasedBy: t.PurchasedBy,
+		Status:      t.Status,
+		TicketId:    t.ID,
+	}
+}
+
+// occupancyRefresh bounds how stale a watcher's view can get when nothing
+// writes to the store, e.g. while an unverified hold expires.
+const occupancyRefresh = 5 * time.Second
+
+// WatchOccupancy streams seat counts per section until the client goes away
+// or the service is closed. Updates are only sent when the counts change.
+func (s *TicketService) WatchOccupancy(req *ticket.WatchOccupancyRequest, stream ticket.TicketService_WatchOccupancyServer) error {
+	updates, cancel := s.store.Subscribe()
+	defer cancel()
+
+	ticker := time.NewTicker(occupancyRefresh)
+	defer ticker.Stop()
+
+	var last *ticket.OccupancyUpdate
+	for {
+		update := s.occupancyUpdate()
+		if !proto.Equal(update, last) {
+			if err := stream.Send(update); err != nil {
+				return err
+			}
+			last = update
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-s.closing:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case <-updates:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *TicketService) occupancyUpdate() *ticket.OccupancyUpdate {
+	occ := s.store.Occupancy()
+	layout := s.store.Layout()
+
+	update := &ticket.OccupancyUpdate{
+		Holds:          int32(occ.Holds),
+		WaitlistLength: int32(occ.Waitlist),
+	}
+	for _, section := range layout.Sections {
+		update.Sections = append(update.Sections, &ticket.SectionOccupancy{
+			Section:   section,
+			Occupied:  int32(occ.Occupied[section]),
+			Capacity:  layout.SeatsPerSection,
+			InService: !occ.OutOfService[section],
+		})
+	}
+	return update
+}
+
+// Close ends open WatchOccupancy streams so a graceful stop does not wait
+// on them. Unary calls are unaffected.
+func (s *TicketService) Close() {
+	s.closeOnce.Do(func() { close(s.closing) })
+}
+
+func (s *TicketService) RequestPurchaseChallenge(ctx context.Context, req *ticket.RequestPurchaseChallengeRequest) (*ticket.RequestPurchaseChallengeResponse, error) {
+	var violations validation.Violations
+	email, err := validation.Email(req.Email)
+	violations.Check("email", err)
+	if err := violations.Err(); err != nil {
+		return nil, err
+	}
+	if s.abuse == nil {
+		return nil, status.Error(codes.FailedPrecondition, "purchase challenges are disabled")
+	}
+
+	c, err := s.abuse.IssueChallenge(email, ratelimit.ClientIP(ctx))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &ticket.RequestPurchaseChallengeResponse{
+		Token:      c.Token,
+		Difficulty: int32(c.Difficulty),
+		ExpiresAt:  c.ExpiresAt.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+func (s *TicketService) ListFlaggedPurchases(ctx context.Context, req *ticket.ListFlaggedPurchasesRequest) (*ticket.ListFlaggedPurchasesResponse, error) {
+	userClaims, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !userClaims.IsAdmin() {
+		return nil, status.Error(codes.PermissionDenied, "admin access required")
 	}
+
+	resp := &ticket.ListFlaggedPurchasesResponse{}
+	if s.abuse == nil {
+		return resp, nil
+	}
+	for _, f := range s.abuse.Flags() {
+		resp.Purchases = append(resp.Purchases, &ticket.FlaggedPurchase{
+			Email:     f.Email,
+			Ip:        f.IP,
+			Reason:    f.Reason,
+			Action:    f.Action,
+			FlaggedAt: f.At.UTC().Format(time.RFC3339),
+		})
+	}
+	return resp, nil
 }
 
+// GetSeatMap is public. A caller that sends a JWT also learns which seat is
+// theirs; nobody learns who holds the others.
+func (s *TicketService) GetSeatMap(ctx context.Context, req *ticket.GetSeatMapRequest) (*ticket.GetSeatMapResponse, error) {
+	var email string
+	userClaims, err := s.extractUser(ctx)
+	switch {
+	case err == nil:
+		email = userClaims.Email
+	case errors.Is(err, auth.ErrNoMetadata) || errors.Is(err, auth.ErrNoAuthHeader):
+	default:
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	states, err := s.store.SeatMap(req.Section)
+	if err != nil {
+		return nil, sectionError(err, req.Section)
+	}
+
+	layout := s.store.Layout()
+	resp := &ticket.GetSeatMapResponse{
+		SeatsPerRow: layout.RowWidth(),
+		AisleAfter:  layout.AisleAfter(),
+	}
+	var section *ticket.SeatMapSection
+	for _, st := range states {
+		if section == nil || section.Section != st.Seat.Section {
+			section = &ticket.SeatMapSection{Section: st.Seat.Section, InService: st.Status != store.SeatBlocked}
+			resp.Sections = append(resp.Sections, section)
+		}
+		section.Seats = append(section.Seats, &ticket.SeatMapSeat{
+			SeatNumber: st.Seat.SeatNumber,
+			Row:        layout.Row(st.Seat.SeatNumber),
+			Status:     st.Status,
+			Attribute:  layout.SeatAttribute(st.Seat.SeatNumber),
+			Yours:      email != "" && st.Email == email,
+		})
+	}
+	return resp, nil
+}
@@ -2,15 +2,28 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/abuse"
+	"github.com/cloudbees/train-ticket-service/internal/audit"
+	"github.com/cloudbees/train-ticket-service/internal/auth"
 	"github.com/cloudbees/train-ticket-service/internal/config"
+	"github.com/cloudbees/train-ticket-service/internal/logging"
+	"github.com/cloudbees/train-ticket-service/internal/metrics"
 	"github.com/cloudbees/train-ticket-service/internal/model"
 	"github.com/cloudbees/train-ticket-service/internal/store"
 	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
@@ -27,9 +40,17 @@ func createTestJWT(email, firstName, lastName, role string) string {
 	return tokenString
 }
 
+// testTokens verifies the tokens createTestJWT signs.
+var testTokens = auth.NewVerifier([]byte("test-secret"), []string{"HS256"})
+
+// newTestService is NewTicketService trusting the tokens createTestJWT signs.
+func newTestService(s *store.Store, opts ...Option) *TicketService {
+	return NewTicketService(s, append([]Option{WithTokenVerifier(testTokens)}, opts...)...)
+}
+
 func TestPurchaseTicket(t *testing.T) {
 	s := store.NewStore()
-	service := NewTicketService(s)
+	service := newTestService(s)
 
 	req := &ticket.PurchaseTicketRequest{
 		FirstName: "John",
@@ -61,7 +82,7 @@ func TestPurchaseTicket(t *testing.T) {
 
 func TestPurchaseTicket_InvalidInput(t *testing.T) {
 	s := store.NewStore()
-	service := NewTicketService(s)
+	service := newTestService(s)
 
 	tests := []struct {
 		name string
@@ -95,7 +116,7 @@ func TestPurchaseTicket_InvalidInput(t *testing.T) {
 
 func TestPurchaseTicket_Duplicate(t *testing.T) {
 	s := store.NewStore()
-	service := NewTicketService(s)
+	service := newTestService(s)
 
 	req := &ticket.PurchaseTicketRequest{
 		FirstName: "John",
@@ -127,13 +148,67 @@ func TestPurchaseTicket_Duplicate(t *testing.T) {
 	}
 }
 
+func TestPurchaseTicket_FieldViolations(t *testing.T) {
+	service := newTestService(store.NewStore())
+
+	_, err := service.PurchaseTicket(context.Background(), &ticket.PurchaseTicketRequest{
+		FirstName: "John",
+		LastName:  strings.Repeat("x", 10_000),
+		Email:     "@@",
+	})
+
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, got %v", err)
+	}
+
+	var fields []string
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, fv := range br.FieldViolations {
+				fields = append(fields, fv.Field)
+			}
+		}
+	}
+	if want := []string{"last_name", "email"}; !slices.Equal(fields, want) {
+		t.Errorf("Expected violations for %v, got %v", want, fields)
+	}
+}
+
+func TestPurchaseTicket_NormalizesEmail(t *testing.T) {
+	s := store.NewStore()
+	service := newTestService(s)
+
+	token := createTestJWT("John@Example.com", "John", "Doe", "user")
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + token,
+	}))
+
+	resp, err := service.PurchaseTicket(ctx, &ticket.PurchaseTicketRequest{FirstName: " John ", LastName: "Doe", Email: " JOHN@example.com"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if resp.Receipt.User.Email != "john@example.com" || resp.Receipt.User.FirstName != "John" {
+		t.Errorf("Expected normalized user, got %v", resp.Receipt.User)
+	}
+
+	_, err = service.PurchaseTicket(ctx, &ticket.PurchaseTicketRequest{FirstName: "John", LastName: "Doe", Email: "john@EXAMPLE.com"})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("Expected AlreadyExists for the same email in another case, got %v", err)
+	}
+
+	if _, err := service.ViewUserReceipt(ctx, &ticket.ViewUserReceiptRequest{}); err != nil {
+		t.Errorf("Expected receipt for the token's email, got: %v", err)
+	}
+}
+
 func TestViewUserReceipt(t *testing.T) {
 	s := store.NewStore()
-	service := NewTicketService(s)
+	service := newTestService(s)
 
 	// Purchase ticket first
 	user := model.User{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com"}
-	_, err := s.PurchaseTicket(user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	_, err := s.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
 	if err != nil {
 		t.Fatalf("Failed to purchase ticket: %v", err)
 	}
@@ -162,7 +237,7 @@ func TestViewUserReceipt(t *testing.T) {
 
 func TestViewUserReceipt_NoAuth(t *testing.T) {
 	s := store.NewStore()
-	service := NewTicketService(s)
+	service := newTestService(s)
 
 	ctx := context.Background()
 	req := &ticket.ViewUserReceiptRequest{}
@@ -186,13 +261,13 @@ func TestViewUserReceipt_NoAuth(t *testing.T) {
 
 func TestViewAllocations_Admin(t *testing.T) {
 	s := store.NewStore()
-	service := NewTicketService(s)
+	service := newTestService(s)
 
 	// Purchase some tickets
 	user1 := model.User{Email: "user1@example.com", FirstName: "User1", LastName: "One"}
 	user2 := model.User{Email: "user2@example.com", FirstName: "User2", LastName: "Two"}
-	s.PurchaseTicket(user1, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
-	s.PurchaseTicket(user2, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	s.PurchaseTicket(context.Background(), user1, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	s.PurchaseTicket(context.Background(), user2, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
 
 	// Create context with admin JWT
 	token := createTestJWT("admin@example.com", "Admin", "User", "admin")
@@ -212,9 +287,71 @@ func TestViewAllocations_Admin(t *testing.T) {
 	}
 }
 
+func TestViewAllocations_Pagination(t *testing.T) {
+	s := store.NewStore()
+	service := newTestService(s)
+
+	for i := 1; i <= 5; i++ {
+		user := model.User{Email: fmt.Sprintf("user%d@example.com", i), FirstName: "Test", LastName: "User"}
+		if _, err := s.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents); err != nil {
+			t.Fatalf("Failed to purchase ticket: %v", err)
+		}
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + createTestJWT("admin@example.com", "Admin", "User", "admin"),
+	}))
+
+	var seats []int32
+	req := &ticket.ViewAllocationsRequest{PageSize: 2, OrderBy: "seat desc"}
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("Expected the listing to end after 3 pages")
+		}
+		resp, err := service.ViewAllocations(ctx, req)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if resp.TotalSize != 5 {
+			t.Errorf("Expected total size 5, got %d", resp.TotalSize)
+		}
+		for _, a := range resp.Allocations {
+			seats = append(seats, a.SeatNumber)
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+	if want := []int32{5, 4, 3, 2, 1}; !slices.Equal(seats, want) {
+		t.Errorf("Expected seats %v, got %v", want, seats)
+	}
+
+	// A token only works with the filters it was issued for
+	first, err := service.ViewAllocations(ctx, &ticket.ViewAllocationsRequest{PageSize: 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	_, err = service.ViewAllocations(ctx, &ticket.ViewAllocationsRequest{PageSize: 1, PageToken: first.NextPageToken, OrderBy: "email"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a reused page token, got %v", err)
+	}
+
+	for _, req := range []*ticket.ViewAllocationsRequest{
+		{OrderBy: "price"},
+		{Status: "cancelled"},
+		{PageSize: -1},
+		{PageToken: "not-a-token"},
+	} {
+		if _, err := service.ViewAllocations(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument for %v, got %v", req, err)
+		}
+	}
+}
+
 func TestViewAllocations_NonAdmin(t *testing.T) {
 	s := store.NewStore()
-	service := NewTicketService(s)
+	service := newTestService(s)
 
 	// Create context with non-admin JWT
 	token := createTestJWT("user@example.com", "User", "Test", "user")
@@ -243,11 +380,11 @@ func TestViewAllocations_NonAdmin(t *testing.T) {
 
 func TestRemoveUserFromTrain(t *testing.T) {
 	s := store.NewStore()
-	service := NewTicketService(s)
+	service := newTestService(s)
 
 	// Purchase ticket first
 	user := model.User{Email: "remove@example.com", FirstName: "Remove", LastName: "Me"}
-	_, err := s.PurchaseTicket(user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	_, err := s.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
 	if err != nil {
 		t.Fatalf("Failed to purchase ticket: %v", err)
 	}
@@ -278,11 +415,11 @@ func TestRemoveUserFromTrain(t *testing.T) {
 
 func TestModifyUserSeat(t *testing.T) {
 	s := store.NewStore()
-	service := NewTicketService(s)
+	service := newTestService(s)
 
 	// Purchase ticket first
 	user := model.User{Email: "modify@example.com", FirstName: "Modify", LastName: "Seat"}
-	_, err := s.PurchaseTicket(user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	_, err := s.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
 	if err != nil {
 		t.Fatalf("Failed to purchase ticket: %v", err)
 	}
@@ -315,7 +452,7 @@ func TestModifyUserSeat(t *testing.T) {
 
 func TestModifyUserSeat_InvalidInput(t *testing.T) {
 	s := store.NewStore()
-	service := NewTicketService(s)
+	service := newTestService(s)
 
 	token := createTestJWT("test@example.com", "Test", "User", "user")
 	md := metadata.New(map[string]string{
@@ -352,3 +489,683 @@ func TestModifyUserSeat_InvalidInput(t *testing.T) {
 	}
 }
 
+func TestAdminPurchaseTicket(t *testing.T) {
+	s := store.NewStore()
+	log := audit.NewLog(nil, logging.Redactor{}, nil)
+	service := newTestService(s, WithAuditRecorder(log))
+
+	token := createTestJWT("admin@example.com", "Admin", "User", "admin")
+	md := metadata.New(map[string]string{
+		"authorization": "Bearer " + token,
+	})
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	req := &ticket.AdminPurchaseTicketRequest{
+		Passenger: &ticket.User{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com"},
+	}
+
+	resp, err := service.AdminPurchaseTicket(ctx, req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if resp.Receipt.User.Email != "jane@example.com" {
+		t.Errorf("Expected passenger jane@example.com, got %s", resp.Receipt.User.Email)
+	}
+
+	if resp.Receipt.PurchasedBy != "admin@example.com" {
+		t.Errorf("Expected purchased_by admin@example.com, got %s", resp.Receipt.PurchasedBy)
+	}
+
+	entries := log.Entries()
+	if len(entries) != 1 || entries[0].Actor != "admin@example.com" || entries[0].Subject != "jane@example.com" {
+		t.Errorf("Expected one audit entry for the on-behalf purchase, got %+v", entries)
+	}
+}
+
+func TestAdminPurchaseTicket_NonAdmin(t *testing.T) {
+	s := store.NewStore()
+	service := newTestService(s)
+
+	token := createTestJWT("user@example.com", "User", "Test", "user")
+	md := metadata.New(map[string]string{
+		"authorization": "Bearer " + token,
+	})
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	req := &ticket.AdminPurchaseTicketRequest{
+		Passenger: &ticket.User{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com"},
+	}
+
+	_, err := service.AdminPurchaseTicket(ctx, req)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied, got %v", err)
+	}
+}
+
+func TestViewUserReceipt_Impersonation(t *testing.T) {
+	s := store.NewStore()
+	log := audit.NewLog(nil, logging.Redactor{}, nil)
+	service := newTestService(s, WithAuditRecorder(log))
+
+	user := model.User{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com"}
+	_, err := s.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	if err != nil {
+		t.Fatalf("Failed to purchase ticket: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		role     string
+		wantCode codes.Code
+	}{
+		{"support allowed", "support", codes.OK},
+		{"admin allowed", "admin", codes.OK},
+		{"user denied", "user", codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := createTestJWT("staff@example.com", "Staff", "Member", tt.role)
+			md := metadata.New(map[string]string{
+				"authorization":          "Bearer " + token,
+				auth.ImpersonationHeader: "jane@example.com",
+			})
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+
+			resp, err := service.ViewUserReceipt(ctx, &ticket.ViewUserReceiptRequest{})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Expected %v, got %v", tt.wantCode, err)
+			}
+
+			if err == nil && resp.Receipt.User.Email != "jane@example.com" {
+				t.Errorf("Expected receipt for jane@example.com, got %s", resp.Receipt.User.Email)
+			}
+		})
+	}
+
+	entries := log.Entries()
+	if len(entries) != len(tests) {
+		t.Fatalf("Expected %d audit entries, got %d", len(tests), len(entries))
+	}
+
+	if entries[2].Allowed {
+		t.Error("Expected denied impersonation to be audited as not allowed")
+	}
+}
+
+func TestImpersonation_ForgedRoleDenied(t *testing.T) {
+	s := store.NewStore()
+	log := audit.NewLog(nil, logging.Redactor{}, nil)
+	service := newTestService(s, WithAuditRecorder(log))
+
+	user := model.User{FirstName: "Jane", LastName: "Smith", Email: "jane@example.com"}
+	if _, err := s.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents); err != nil {
+		t.Fatalf("Failed to purchase ticket: %v", err)
+	}
+
+	forged, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"email": "mallory@example.com",
+		"role":  "support",
+	}).SignedString([]byte("attacker-secret"))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization":          "Bearer " + forged,
+		auth.ImpersonationHeader: "jane@example.com",
+	}))
+
+	if _, err := service.ViewUserReceipt(ctx, &ticket.ViewUserReceiptRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated for a forged support role, got %v", err)
+	}
+	if _, err := service.DownloadReceipt(ctx, &ticket.DownloadReceiptRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated for a forged support role, got %v", err)
+	}
+
+	// A forged identity never reaches the audit log as an actor
+	if entries := log.Entries(); len(entries) != 0 {
+		t.Errorf("Expected no audit entries for forged tokens, got %+v", entries)
+	}
+}
+
+func TestRemoveUserFromTrain_RejectsImpersonation(t *testing.T) {
+	s := store.NewStore()
+	service := newTestService(s)
+
+	token := createTestJWT("support@example.com", "Support", "Agent", "support")
+	md := metadata.New(map[string]string{
+		"authorization":          "Bearer " + token,
+		auth.ImpersonationHeader: "jane@example.com",
+	})
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	_, err := service.RemoveUserFromTrain(ctx, &ticket.RemoveUserFromTrainRequest{})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied, got %v", err)
+	}
+}
+
+// recordingSender captures verification codes instead of emailing them
+type recordingSender struct {
+	codes map[string]string
+}
+
+func (r *recordingSender) SendVerificationCode(email, code string) error {
+	r.codes[email] = code
+	return nil
+}
+
+func TestPurchaseTicket_AnonymousRequiresVerification(t *testing.T) {
+	s := store.NewStore()
+	sender := &recordingSender{codes: map[string]string{}}
+	service := newTestService(s, WithVerificationSender(sender))
+
+	req := &ticket.PurchaseTicketRequest{
+		FirstName: "John",
+		LastName:  "Doe",
+		Email:     "john@example.com",
+	}
+
+	resp, err := service.PurchaseTicket(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if resp.Receipt.Status != model.TicketStatusPendingVerification {
+		t.Errorf("Expected status %s, got %s", model.TicketStatusPendingVerification, resp.Receipt.Status)
+	}
+
+	_, err = service.VerifyPurchase(context.Background(), &ticket.VerifyPurchaseRequest{Email: "john@example.com", Code: "000000x"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for wrong code, got %v", err)
+	}
+
+	verified, err := service.VerifyPurchase(context.Background(), &ticket.VerifyPurchaseRequest{
+		Email: "john@example.com",
+		Code:  sender.codes["john@example.com"],
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if verified.Receipt.Status != model.TicketStatusConfirmed {
+		t.Errorf("Expected status %s, got %s", model.TicketStatusConfirmed, verified.Receipt.Status)
+	}
+}
+
+func TestPurchaseTicket_AuthRequired(t *testing.T) {
+	s := store.NewStore()
+	service := newTestService(s, WithPurchaseAuthMode(config.PurchaseAuthRequired))
+
+	req := &ticket.PurchaseTicketRequest{
+		FirstName: "John",
+		LastName:  "Doe",
+		Email:     "john@example.com",
+	}
+
+	_, err := service.PurchaseTicket(context.Background(), req)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated without JWT, got %v", err)
+	}
+
+	token := createTestJWT("mallory@example.com", "Mallory", "X", "user")
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + token,
+	}))
+	_, err = service.PurchaseTicket(ctx, req)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for mismatched email, got %v", err)
+	}
+
+	token = createTestJWT("john@example.com", "John", "Doe", "user")
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + token,
+	}))
+	resp, err := service.PurchaseTicket(ctx, req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if resp.Receipt.Status != model.TicketStatusConfirmed {
+		t.Errorf("Expected status %s, got %s", model.TicketStatusConfirmed, resp.Receipt.Status)
+	}
+}
+
+func TestPurchaseTicket_ForgedToken(t *testing.T) {
+	forged, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"email": "john@example.com",
+		"role":  "user",
+	}).SignedString([]byte("attacker-secret"))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + forged,
+	}))
+	req := &ticket.PurchaseTicketRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com"}
+
+	for _, mode := range []string{config.PurchaseAuthRequired, config.PurchaseAuthAnonymous} {
+		t.Run(mode, func(t *testing.T) {
+			s := store.NewStore()
+			service := newTestService(s, WithPurchaseAuthMode(mode))
+
+			// John's own anonymous hold must not be replaced by the forged purchase
+			if mode == config.PurchaseAuthAnonymous {
+				if _, err := service.PurchaseTicket(context.Background(), req); err != nil {
+					t.Fatalf("Anonymous purchase failed: %v", err)
+				}
+			}
+
+			if _, err := service.PurchaseTicket(ctx, req); status.Code(err) != codes.Unauthenticated {
+				t.Errorf("Expected Unauthenticated for a forged signature, got %v", err)
+			}
+			if held, err := s.GetTicketByEmail("john@example.com"); err == nil && !held.IsPending() {
+				t.Errorf("Expected no confirmed ticket from a forged token, got %+v", held)
+			}
+		})
+	}
+
+	// Without a verification key no token is trusted at all
+	service := NewTicketService(store.NewStore(), WithPurchaseAuthMode(config.PurchaseAuthRequired))
+	token := createTestJWT("john@example.com", "John", "Doe", "user")
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + token,
+	}))
+	if _, err := service.PurchaseTicket(ctx, req); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated without a verification key, got %v", err)
+	}
+}
+
+func TestPurchaseTicket_AuthenticatedSupersedesUnverifiedHold(t *testing.T) {
+	s := store.NewStore()
+	sender := &recordingSender{codes: map[string]string{}}
+	service := newTestService(s, WithVerificationSender(sender))
+
+	req := &ticket.PurchaseTicketRequest{
+		FirstName: "John",
+		LastName:  "Doe",
+		Email:     "john@example.com",
+	}
+
+	// Someone else squats on John's email anonymously
+	if _, err := service.PurchaseTicket(context.Background(), req); err != nil {
+		t.Fatalf("Anonymous purchase failed: %v", err)
+	}
+
+	token := createTestJWT("john@example.com", "John", "Doe", "user")
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + token,
+	}))
+	resp, err := service.PurchaseTicket(ctx, req)
+	if err != nil {
+		t.Fatalf("Expected authenticated purchase to succeed, got: %v", err)
+	}
+
+	if resp.Receipt.Status != model.TicketStatusConfirmed {
+		t.Errorf("Expected status %s, got %s", model.TicketStatusConfirmed, resp.Receipt.Status)
+	}
+}
+
+func TestPurchaseTicket_AbuseGuard(t *testing.T) {
+	guard, err := abuse.NewGuard(abuse.Config{
+		MaxPerEmailDomain: 3,
+		Window:            time.Hour,
+		ChallengeAfter:    2,
+		BlockAfter:        5,
+		Difficulty:        8,
+		ChallengeTTL:      time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	service := newTestService(store.NewStore(), WithVerificationSender(&recordingSender{codes: map[string]string{}}), WithAbuseGuard(guard))
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 4000}})
+	purchase := func(email, token, nonce string) error {
+		_, err := service.PurchaseTicket(ctx, &ticket.PurchaseTicketRequest{
+			FirstName: "Bot", LastName: "Buyer", Email: email,
+			ChallengeToken: token, ChallengeNonce: nonce,
+		})
+		return err
+	}
+
+	for _, email := range []string{"a@example.com", "b@example.com"} {
+		if err := purchase(email, "", ""); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	if err := purchase("c@example.com", "", ""); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Expected FailedPrecondition without a challenge, got %v", err)
+	}
+
+	challenge, err := service.RequestPurchaseChallenge(ctx, &ticket.RequestPurchaseChallengeRequest{Email: "c@example.com"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	nonce := abuse.Solve(challenge.Token, int(challenge.Difficulty))
+	if err := purchase("c@example.com", challenge.Token, nonce); err != nil {
+		t.Fatalf("Expected solved challenge to be accepted, got: %v", err)
+	}
+
+	// example.com now holds three tickets, the domain cap
+	if err := purchase("d@example.com", "", ""); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted for the domain cap, got %v", err)
+	}
+
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + createTestJWT("admin@example.com", "Admin", "User", "admin"),
+	}))
+	flagged, err := service.ListFlaggedPurchases(adminCtx, &ticket.ListFlaggedPurchasesRequest{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	var actions []string
+	for _, f := range flagged.Purchases {
+		actions = append(actions, f.Action)
+	}
+	if want := []string{abuse.ActionBlocked, abuse.ActionSolved, abuse.ActionChallenged}; !slices.Equal(actions, want) {
+		t.Errorf("Expected flagged actions %v, got %v", want, actions)
+	}
+
+	_, err = service.ListFlaggedPurchases(ctx, &ticket.ListFlaggedPurchasesRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated without a JWT, got %v", err)
+	}
+}
+
+func TestSeatSwap(t *testing.T) {
+	s := store.NewStore()
+	service := newTestService(s)
+
+	alice := model.User{Email: "alice@example.com", FirstName: "Alice", LastName: "A"}
+	bob := model.User{Email: "bob@example.com", FirstName: "Bob", LastName: "B"}
+	s.PurchaseTicket(context.Background(), alice, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	s.PurchaseTicket(context.Background(), bob, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+
+	aliceCtx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + createTestJWT(alice.Email, "Alice", "A", "user"),
+	}))
+	bobCtx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + createTestJWT(bob.Email, "Bob", "B", "user"),
+	}))
+
+	_, err := service.RequestSeatSwap(aliceCtx, &ticket.RequestSeatSwapRequest{CounterpartyEmail: bob.Email, Force: true})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for non-admin force, got %v", err)
+	}
+
+	resp, err := service.RequestSeatSwap(aliceCtx, &ticket.RequestSeatSwapRequest{CounterpartyEmail: bob.Email})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if resp.Swap.CounterpartySeat != nil {
+		t.Errorf("Expected counterparty seat hidden until accepted, got %v", resp.Swap.CounterpartySeat)
+	}
+
+	// The requester cannot approve their own proposal
+	_, err = service.AcceptSeatSwap(aliceCtx, &ticket.AcceptSeatSwapRequest{SwapId: resp.Swap.Id})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for requester accept, got %v", err)
+	}
+
+	accepted, err := service.AcceptSeatSwap(bobCtx, &ticket.AcceptSeatSwapRequest{SwapId: resp.Swap.Id})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if accepted.Swap.Status != model.SwapStatusCompleted {
+		t.Errorf("Expected completed swap, got %s", accepted.Swap.Status)
+	}
+
+	aliceTicket, _ := s.GetTicketByEmail(alice.Email)
+	if aliceTicket.Seat.SeatNumber != accepted.Swap.CounterpartySeat.SeatNumber {
+		t.Errorf("Expected alice in seat %d, got %d", accepted.Swap.CounterpartySeat.SeatNumber, aliceTicket.Seat.SeatNumber)
+	}
+}
+
+func TestSeatSwap_AdminForce(t *testing.T) {
+	s := store.NewStore()
+	service := newTestService(s)
+
+	alice := model.User{Email: "alice@example.com", FirstName: "Alice", LastName: "A"}
+	bob := model.User{Email: "bob@example.com", FirstName: "Bob", LastName: "B"}
+	s.PurchaseTicket(context.Background(), alice, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	s.PurchaseTicket(context.Background(), bob, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + createTestJWT("admin@example.com", "Admin", "User", "admin"),
+	}))
+
+	resp, err := service.RequestSeatSwap(adminCtx, &ticket.RequestSeatSwapRequest{
+		Email:             alice.Email,
+		CounterpartyEmail: bob.Email,
+		Force:             true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if resp.Swap.Status != model.SwapStatusCompleted {
+		t.Errorf("Expected completed swap, got %s", resp.Swap.Status)
+	}
+
+	bobTicket, _ := s.GetTicketByEmail(bob.Email)
+	if bobTicket.Seat.SeatNumber != resp.Swap.RequesterSeat.SeatNumber {
+		t.Errorf("Expected bob in seat %d, got %d", resp.Swap.RequesterSeat.SeatNumber, bobTicket.Seat.SeatNumber)
+	}
+}
+
+func TestBulkApply(t *testing.T) {
+	s := store.NewStore()
+	service := newTestService(s)
+
+	user1 := model.User{Email: "user1@example.com", FirstName: "User1", LastName: "One"}
+	user2 := model.User{Email: "user2@example.com", FirstName: "User2", LastName: "Two"}
+	s.PurchaseTicket(context.Background(), user1, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	s.PurchaseTicket(context.Background(), user2, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + createTestJWT("admin@example.com", "Admin", "User", "admin"),
+	}))
+
+	req := &ticket.BulkApplyRequest{
+		Operations: []*ticket.BulkOperation{
+			{Operation: &ticket.BulkOperation_Reassign{Reassign: &ticket.ReassignOperation{Email: user1.Email, Section: "B"}}},
+			{Operation: &ticket.BulkOperation_Move{Move: &ticket.MoveOperation{Email: user2.Email, Section: "B", SeatNumber: 1}}},
+		},
+		BestEffort: true,
+	}
+
+	resp, err := service.BulkApply(adminCtx, req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !resp.Results[0].Success || resp.Results[0].Seat.Section != "B" {
+		t.Errorf("Expected reassign to succeed into B, got %+v", resp.Results[0])
+	}
+
+	// user1 took B-1, so the explicit move must fail without undoing the reassign
+	if resp.Results[1].Success || resp.Results[1].Code != codes.AlreadyExists.String() {
+		t.Errorf("Expected move to fail with AlreadyExists, got %+v", resp.Results[1])
+	}
+}
+
+func TestBulkApply_InvalidOperation(t *testing.T) {
+	s := store.NewStore()
+	service := newTestService(s)
+
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + createTestJWT("admin@example.com", "Admin", "User", "admin"),
+	}))
+
+	req := &ticket.BulkApplyRequest{
+		Operations: []*ticket.BulkOperation{{}},
+	}
+
+	_, err := service.BulkApply(adminCtx, req)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestDecommissionSection(t *testing.T) {
+	s := store.NewStore()
+	service := newTestService(s)
+
+	user := model.User{Email: "user1@example.com", FirstName: "User1", LastName: "One"}
+	s.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+
+	userCtx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + createTestJWT(user.Email, "User1", "One", "user"),
+	}))
+	_, err := service.DecommissionSection(userCtx, &ticket.DecommissionSectionRequest{Section: "A"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied, got %v", err)
+	}
+
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + createTestJWT("admin@example.com", "Admin", "User", "admin"),
+	}))
+	resp, err := service.DecommissionSection(adminCtx, &ticket.DecommissionSectionRequest{Section: "A"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(resp.Report.Moved) != 1 || resp.Report.Moved[0].To.Section != "B" {
+		t.Errorf("Expected passenger moved to B, got %+v", resp.Report)
+	}
+
+	_, err = service.ModifyUserSeat(userCtx, &ticket.ModifyUserSeatRequest{Section: "A", SeatNumber: 1})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition moving into decommissioned section, got %v", err)
+	}
+
+	_, err = service.DecommissionSection(adminCtx, &ticket.DecommissionSectionRequest{Section: "B", OverflowPolicy: "discard"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for unknown policy, got %v", err)
+	}
+}
+
+func TestPurchaseTicket_Config(t *testing.T) {
+	cfg := config.Default()
+	cfg.Route.From = "Paris"
+	cfg.Pricing.TicketPriceCents = 3500
+	cfg.Layout.Sections = []string{"C"}
+
+	s := store.NewStoreWithLayout(cfg.SeatLayout())
+	service := newTestService(s, WithConfig(cfg))
+
+	token := createTestJWT("john@example.com", "John", "Doe", "user")
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + token,
+	}))
+
+	resp, err := service.PurchaseTicket(ctx, &ticket.PurchaseTicketRequest{
+		FirstName: "John",
+		LastName:  "Doe",
+		Email:     "john@example.com",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if resp.Receipt.From != "Paris" || resp.Receipt.PricePaid != 3500 || resp.Receipt.Seat.Section != "C" {
+		t.Errorf("Expected configured route, price and layout, got %+v", resp.Receipt)
+	}
+}
+
+func TestMetrics_CountsPurchasesAndRemovals(t *testing.T) {
+	m := metrics.New()
+	service := newTestService(store.NewStore(), WithMetrics(m))
+
+	token := createTestJWT("john@example.com", "John", "Doe", "user")
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + token,
+	}))
+
+	_, err := service.PurchaseTicket(ctx, &ticket.PurchaseTicketRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := service.RemoveUserFromTrain(ctx, &ticket.RemoveUserFromTrainRequest{}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+
+	for _, want := range []string{`ticket_purchases_total{kind="self"} 1`, `ticket_removals_total 1`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected metrics to contain %s", want)
+		}
+	}
+}
+
+func TestMetrics_CountsAdminBookingChanges(t *testing.T) {
+	m := metrics.New()
+	service := newTestService(store.NewStore(), WithMetrics(m))
+
+	admin := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + createTestJWT("admin@example.com", "Admin", "User", "admin"),
+	}))
+
+	stream := &importStream{ctx: admin, reqs: []*ticket.ImportBookingsRequest{{Commit: true, Rows: []*ticket.BookingRow{
+		{Row: 2, FirstName: "Ann", LastName: "Lee", Email: "ann@example.com", Section: "B", SeatNumber: 1},
+	}}}}
+	if err := service.ImportBookings(stream); err != nil || !stream.resp.Committed {
+		t.Fatalf("Expected the import to commit, got %v, %v", err, stream.resp)
+	}
+	if _, err := service.DecommissionSection(admin, &ticket.DecommissionSectionRequest{Section: "B"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+
+	for _, want := range []string{`ticket_purchases_total{kind="imported"} 1`, `ticket_seat_modifications_total 1`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected metrics to contain %s", want)
+		}
+	}
+}
+
+func TestGetSeatMap(t *testing.T) {
+	s := store.NewStore()
+	service := newTestService(s)
+
+	for _, email := range []string{"first@example.com", "second@example.com"} {
+		user := model.User{Email: email, FirstName: "Test", LastName: "User"}
+		if _, err := s.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents); err != nil {
+			t.Fatalf("Failed to purchase ticket: %v", err)
+		}
+	}
+
+	resp, err := service.GetSeatMap(context.Background(), &ticket.GetSeatMapRequest{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(resp.Sections) != 2 || len(resp.Sections[0].Seats) != 10 || resp.SeatsPerRow != 4 || resp.AisleAfter != 2 {
+		t.Fatalf("Expected two sections of 10 seats in rows of 4, got %v", resp)
+	}
+	seats := resp.Sections[0].Seats
+	if seats[0].Status != store.SeatOccupied || seats[0].Yours || seats[2].Status != store.SeatFree {
+		t.Errorf("Expected A-1 occupied by someone else and A-3 free, got %v and %v", seats[0], seats[2])
+	}
+	if seats[0].Attribute != model.SeatAttributeWindow || seats[1].Attribute != model.SeatAttributeAisle || seats[4].Row != 2 {
+		t.Errorf("Expected window, aisle and row 2 attributes, got %v, %v and %v", seats[0], seats[1], seats[4])
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+		"authorization": "Bearer " + createTestJWT("Second@Example.com", "Test", "User", "user"),
+	}))
+	resp, err = service.GetSeatMap(ctx, &ticket.GetSeatMapRequest{Section: "A"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(resp.Sections) != 1 || resp.Sections[0].Seats[0].Yours || !resp.Sections[0].Seats[1].Yours {
+		t.Errorf("Expected only A-2 marked as the caller's seat, got %v", resp.Sections)
+	}
+
+	if _, err := service.GetSeatMap(context.Background(), &ticket.GetSeatMapRequest{Section: "Z"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an unknown section, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/config"
+	"github.com/cloudbees/train-ticket-service/internal/store"
+	"github.com/cloudbees/train-ticket-service/internal/tlsutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+func TestServiceIdentity_ClientCertificate(t *testing.T) {
+	certs, err := tlsutil.GenerateDevCerts(t.TempDir(), []string{"localhost"}, "billing")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	cfg := config.Default()
+	cfg.TLS.ClientIdentities = map[string]config.ServiceIdentity{
+		"CN=billing": {Email: "billing@services.example.com", Role: "admin"},
+	}
+
+	serverConfig, err := tlsutil.ServerConfig(tlsutil.ServerOptions{
+		CertFile:     certs.ServerCertFile,
+		KeyFile:      certs.ServerKeyFile,
+		ClientCAFile: certs.CAFile,
+		ClientAuth:   tlsutil.ClientAuthOptional,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(serverConfig)))
+	ticket.RegisterTicketServiceServer(server, newTestService(store.NewStore(), WithConfig(cfg)))
+	go server.Serve(lis)
+	defer server.Stop()
+
+	dial := func(opts tlsutil.ClientOptions) ticket.TicketServiceClient {
+		opts.CAFile = certs.CAFile
+		opts.ServerName = "localhost"
+		clientConfig, err := tlsutil.ClientConfig(opts)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(clientConfig)))
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return ticket.NewTicketServiceClient(conn)
+	}
+
+	// The mapped certificate acts as an admin without a JWT
+	withCert := dial(tlsutil.ClientOptions{CertFile: certs.ClientCertFile, KeyFile: certs.ClientKeyFile})
+	if _, err := withCert.ViewAllocations(context.Background(), &ticket.ViewAllocationsRequest{}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Without a certificate or JWT the caller is anonymous
+	withoutCert := dial(tlsutil.ClientOptions{})
+	_, err = withoutCert.ViewAllocations(context.Background(), &ticket.ViewAllocationsRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated, got: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/store"
+	"github.com/cloudbees/train-ticket-service/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestTracing_PurchaseSpanTree(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := tracing.NewProvider(exporter, 1)
+	previous := otel.GetTracerProvider()
+	tracing.Install(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	service := newTestService(store.NewStore())
+	token := createTestJWT("john@example.com", "John", "Doe", "user")
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	info := &grpc.UnaryServerInfo{FullMethod: ticket.TicketService_PurchaseTicket_FullMethodName}
+
+	_, err := tracing.UnaryServerInterceptor()(ctx, &ticket.PurchaseTicketRequest{
+		FirstName: "John", LastName: "Doe", Email: "john@example.com",
+	}, info, func(ctx context.Context, req any) (any, error) {
+		return service.PurchaseTicket(ctx, req.(*ticket.PurchaseTicketRequest))
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	tp.ForceFlush(context.Background())
+
+	// Map each span to its parent's name to check the shape of the tree
+	names := make(map[string]string)
+	parents := make(map[string]string)
+	for _, span := range exporter.GetSpans() {
+		names[span.SpanContext.SpanID().String()] = span.Name
+	}
+	for _, span := range exporter.GetSpans() {
+		parents[span.Name] = names[span.Parent.SpanID().String()]
+	}
+
+	want := map[string]string{
+		ticket.TicketService_PurchaseTicket_FullMethodName: "",
+		"pricing.Quote":        ticket.TicketService_PurchaseTicket_FullMethodName,
+		"store.PurchaseTicket": ticket.TicketService_PurchaseTicket_FullMethodName,
+		"store.lock_wait":      "store.PurchaseTicket",
+		"store.seat_search":    "store.PurchaseTicket",
+	}
+	for span, parent := range want {
+		got, ok := parents[span]
+		if !ok {
+			t.Errorf("Expected span %s", span)
+			continue
+		}
+		if got != parent {
+			t.Errorf("Expected %s to be a child of %q, got %q", span, parent, got)
+		}
+	}
+}
+
+func TestTracing_WritersRecordLockWait(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := tracing.NewProvider(exporter, 1)
+	previous := otel.GetTracerProvider()
+	tracing.Install(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	service := newTestService(store.NewStore())
+	token := createTestJWT("john@example.com", "John", "Doe", "user")
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	if _, err := service.PurchaseTicket(ctx, &ticket.PurchaseTicketRequest{FirstName: "John", LastName: "Doe", Email: "john@example.com"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	tp.ForceFlush(context.Background())
+	exporter.Reset()
+
+	info := &grpc.UnaryServerInfo{FullMethod: ticket.TicketService_RemoveUserFromTrain_FullMethodName}
+	_, err := tracing.UnaryServerInterceptor()(ctx, &ticket.RemoveUserFromTrainRequest{}, info, func(ctx context.Context, req any) (any, error) {
+		return service.RemoveUserFromTrain(ctx, req.(*ticket.RemoveUserFromTrainRequest))
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	tp.ForceFlush(context.Background())
+
+	names := make(map[string]string)
+	for _, span := range exporter.GetSpans() {
+		names[span.SpanContext.SpanID().String()] = span.Name
+	}
+	for _, span := range exporter.GetSpans() {
+		if span.Name == "store.lock_wait" {
+			if parent := names[span.Parent.SpanID().String()]; parent != info.FullMethod {
+				t.Errorf("Expected store.lock_wait to be a child of %q, got %q", info.FullMethod, parent)
+			}
+			return
+		}
+	}
+	t.Error("Expected a store.lock_wait span")
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	ticket "github.com/cloudbees/train-ticket-service/api"
+	"github.com/cloudbees/train-ticket-service/internal/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestWatchOccupancy(t *testing.T) {
+	s := store.NewStore()
+	service := newTestService(s)
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	ticket.RegisterTicketServiceServer(server, service)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := ticket.NewTicketServiceClient(conn).WatchOccupancy(ctx, &ticket.WatchOccupancyRequest{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	initial, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(initial.Sections) != 2 || initial.Sections[0].Occupied != 0 || initial.Sections[0].Capacity != 10 {
+		t.Fatalf("Expected two empty sections of 10 seats, got %v", initial.Sections)
+	}
+
+	md := metadata.New(map[string]string{
+		"authorization": "Bearer " + createTestJWT("john@example.com", "John", "Doe", "user"),
+	})
+	purchaseCtx := metadata.NewIncomingContext(context.Background(), md)
+	if _, err := service.PurchaseTicket(purchaseCtx, &ticket.PurchaseTicketRequest{
+		FirstName: "John", LastName: "Doe", Email: "john@example.com",
+	}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	update, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if update.Sections[0].Occupied != 1 {
+		t.Errorf("Expected 1 occupied seat in section A, got %d", update.Sections[0].Occupied)
+	}
+
+	service.Close()
+	if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable after Close, got: %v", err)
+	}
+}
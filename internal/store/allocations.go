@@ -0,0 +1,143 @@
+package store
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/cloudbees/train-ticket-service/internal/model"
+)
+
+// Orderings for ListAllocations.
+const (
+	OrderBySeat  = "seat"
+	OrderByName  = "name"
+	OrderByEmail = "email"
+)
+
+// AllocationQuery selects a page of allocations.
+type AllocationQuery struct {
+	Section string
+	// Search is a case-insensitive substring of the email or full name.
+	Search string
+	// Status is a model.TicketStatus, empty for all.
+	Status string
+
+	OrderBy    string
+	Descending bool
+
+	// After resumes the listing after the allocation with this key.
+	After *AllocationKey
+	// Limit caps the page; 0 means no cap.
+	Limit int
+}
+
+// AllocationKey is where an allocation sorts under every ordering. A listing
+// resumes after a key rather than an offset, so allocations added or removed
+// on earlier pages do not shift later ones.
+type AllocationKey struct {
+	Section    string
+	SeatNumber int32
+	LastName   string
+	FirstName  string
+	Email      string
+}
+
+func KeyOf(t *model.Ticket) AllocationKey {
+	return AllocationKey{
+		Section:    t.Seat.Section,
+		SeatNumber: t.Seat.SeatNumber,
+		LastName:   t.User.LastName,
+		FirstName:  t.User.FirstName,
+		Email:      t.User.Email,
+	}
+}
+
+// AllocationPage is one page of ListAllocations.
+type AllocationPage struct {
+	Allocations []*model.Ticket
+	// Total counts every allocation matching the filters, across pages.
+	Total int
+	More  bool
+}
+
+// ListAllocations returns copies of the live allocations matching q in a
+// total order: ties are broken by email, which is unique.
+func (s *Store) ListAllocations(q AllocationQuery) AllocationPage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := s.now()
+	search := strings.ToLower(q.Search)
+
+	var matched []*model.Ticket
+	for _, ticket := range s.tickets {
+		switch {
+		case ticket.HoldExpired(now):
+		case q.Section != "" && ticket.Seat.Section != q.Section:
+		case q.Status != "" && ticket.Status != q.Status:
+		case search != "" && !matchesSearch(ticket.User, search):
+		default:
+			matched = append(matched, ticket)
+		}
+	}
+
+	compare := s.allocationOrder(q.OrderBy, q.Descending)
+	slices.SortFunc(matched, func(a, b *model.Ticket) int { return compare(KeyOf(a), KeyOf(b)) })
+
+	page := AllocationPage{Total: len(matched)}
+	start := 0
+	if q.After != nil {
+		start, _ = slices.BinarySearchFunc(matched, *q.After, func(t *model.Ticket, key AllocationKey) int {
+			if c := compare(KeyOf(t), key); c != 0 {
+				return c
+			}
+			// The key's own allocation belongs to the previous page
+			return -1
+		})
+	}
+	matched = matched[start:]
+	if q.Limit > 0 && len(matched) > q.Limit {
+		matched, page.More = matched[:q.Limit], true
+	}
+	page.Allocations = make([]*model.Ticket, len(matched))
+	for i, ticket := range matched {
+		page.Allocations[i], _ = copied(ticket, nil)
+	}
+	return page
+}
+
+func matchesSearch(user model.User, search string) bool {
+	name := strings.ToLower(user.FirstName + " " + user.LastName)
+	return strings.Contains(user.Email, search) || strings.Contains(name, search)
+}
+
+// allocationOrder returns the comparison for orderBy; unknown orderings sort by seat.
+func (s *Store) allocationOrder(orderBy string, descending bool) func(a, b AllocationKey) int {
+	var compare func(a, b AllocationKey) int
+	switch orderBy {
+	case OrderByName:
+		compare = func(a, b AllocationKey) int {
+			return cmp.Or(
+				cmp.Compare(strings.ToLower(a.LastName), strings.ToLower(b.LastName)),
+				cmp.Compare(strings.ToLower(a.FirstName), strings.ToLower(b.FirstName)),
+				cmp.Compare(a.Email, b.Email),
+			)
+		}
+	case OrderByEmail:
+		compare = func(a, b AllocationKey) int { return cmp.Compare(a.Email, b.Email) }
+	default:
+		compare = func(a, b AllocationKey) int {
+			return cmp.Or(
+				cmp.Compare(slices.Index(s.layout.Sections, a.Section), slices.Index(s.layout.Sections, b.Section)),
+				cmp.Compare(a.SeatNumber, b.SeatNumber),
+				cmp.Compare(a.Email, b.Email),
+			)
+		}
+	}
+
+	if descending {
+		return func(a, b AllocationKey) int { return compare(b, a) }
+	}
+	return compare
+}
@@ -0,0 +1,178 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudbees/train-ticket-service/internal/model"
+)
+
+var ErrRolledBack = errors.New("not applied: batch was rolled back")
+
+type BulkOpKind int
+
+const (
+	BulkRemove BulkOpKind = iota
+	BulkMove
+	BulkReassign
+)
+
+// BulkOp is a single admin operation. Section and SeatNumber are the target
+// seat for BulkMove; for BulkReassign an optional Section limits where the
+// passenger is placed.
+type BulkOp struct {
+	Kind       BulkOpKind
+	Email      string
+	Section    string
+	SeatNumber int32
+}
+
+type BulkResult struct {
+	Err  error
+	Seat model.Seat
+}
+
+// BulkApply runs ops in order under a single lock. When atomic is set, the
+// first failure restores the store to its state before the call and every
+// operation that had succeeded reports ErrRolledBack. It reports whether the
+// changes were kept; kept changes seat waitlisted passengers in any freed seats.
+func (s *Store) BulkApply(ctx context.Context, ops []BulkOp, atomic bool) ([]BulkResult, bool) {
+	s.lock(ctx)
+	defer s.unlock()
+
+	s.releaseExpiredHoldsLocked()
+
+	var snap snapshot
+	if atomic {
+		snap = s.snapshotLocked()
+	}
+
+	results := make([]BulkResult, len(ops))
+	failed := false
+	for i, op := range ops {
+		results[i] = s.applyLocked(op)
+		if results[i].Err != nil {
+			failed = true
+			if atomic {
+				break
+			}
+		}
+	}
+
+	if !atomic || !failed {
+		s.promoteWaitlistLocked()
+		return results, true
+	}
+
+	s.restoreLocked(snap)
+	for i := range results {
+		if results[i].Err == nil {
+			results[i] = BulkResult{Err: ErrRolledBack}
+		}
+	}
+
+	return results, false
+}
+
+func (s *Store) applyLocked(op BulkOp) BulkResult {
+	switch op.Kind {
+	case BulkRemove:
+		ticket, exists := s.tickets[op.Email]
+		if !exists {
+			return BulkResult{Err: ErrTicketNotFound}
+		}
+		s.removeLocked(ticket)
+		return BulkResult{}
+
+	case BulkMove:
+		ticket, err := s.moveLocked(op.Email, op.Section, op.SeatNumber)
+		if err != nil {
+			return BulkResult{Err: err}
+		}
+		return BulkResult{Seat: ticket.Seat}
+
+	case BulkReassign:
+		ticket, err := s.reassignLocked(op.Email, op.Section)
+		if err != nil {
+			return BulkResult{Err: err}
+		}
+		return BulkResult{Seat: ticket.Seat}
+
+	default:
+		return BulkResult{Err: fmt.Errorf("unknown bulk operation %d", op.Kind)}
+	}
+}
+
+// reassignLocked moves a passenger to the first free seat, within section if given.
+func (s *Store) reassignLocked(email, section string) (*model.Ticket, error) {
+	ticket, exists := s.tickets[email]
+	if !exists {
+		return nil, ErrTicketNotFound
+	}
+
+	var seat *model.Seat
+	if section != "" {
+		if !s.layout.HasSection(section) {
+			return nil, fmt.Errorf("%w: invalid section %s", ErrInvalidSeat, section)
+		}
+		if s.outOfService[section] {
+			return nil, ErrSectionOutOfService
+		}
+		if ticket.Seat.Section == section {
+			return ticket, nil
+		}
+		seat = s.findAvailableSeatInSection(section)
+		if seat == nil {
+			return nil, ErrSectionFull
+		}
+	} else {
+		var err error
+		if seat, err = s.findNextAvailableSeat(); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.moveLocked(email, seat.Section, seat.SeatNumber)
+}
+
+type snapshot struct {
+	tickets     map[string]model.Ticket
+	seats       map[string]bool
+	waitlist    []model.WaitlistEntry
+	swaps       map[string]*model.SeatSwap
+	revocations int
+}
+
+func (s *Store) snapshotLocked() snapshot {
+	snap := snapshot{
+		tickets:  make(map[string]model.Ticket, len(s.tickets)),
+		seats:    make(map[string]bool, len(s.seats)),
+		waitlist: append([]model.WaitlistEntry(nil), s.waitlist...),
+		swaps:    make(map[string]*model.SeatSwap, len(s.swaps)),
+
+		revocations: len(s.revocations),
+	}
+	for email, ticket := range s.tickets {
+		snap.tickets[email] = *ticket
+	}
+	for key, occupied := range s.seats {
+		snap.seats[key] = occupied
+	}
+	for id, swap := range s.swaps {
+		snap.swaps[id] = swap
+	}
+	return snap
+}
+
+func (s *Store) restoreLocked(snap snapshot) {
+	s.tickets = make(map[string]*model.Ticket, len(snap.tickets))
+	for email, ticket := range snap.tickets {
+		t := ticket
+		s.tickets[email] = &t
+	}
+	s.seats = snap.seats
+	s.waitlist = snap.waitlist
+	s.swaps = snap.swaps
+	s.revocations = s.revocations[:snap.revocations]
+}
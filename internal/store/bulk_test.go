@@ -0,0 +1,140 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cloudbees/train-ticket-service/internal/model"
+)
+
+func TestBulkApply_ReassignSection(t *testing.T) {
+	store := NewStore()
+
+	// Fill A-1..A-3 and B-1..B-2, then free A so section B can move over
+	emails := []string{"a1@example.com", "a2@example.com", "a3@example.com"}
+	purchaseTestTickets(t, store, emails...)
+	for i := 0; i < 2; i++ {
+		email := fmt.Sprintf("b%d@example.com", i+1)
+		purchaseTestTickets(t, store, email)
+		if _, err := store.ModifySeat(context.Background(), email, "B", int32(i+1)); err != nil {
+			t.Fatalf("Failed to seat %s in B: %v", email, err)
+		}
+	}
+
+	ops := []BulkOp{
+		{Kind: BulkReassign, Email: "b1@example.com", Section: "A"},
+		{Kind: BulkReassign, Email: "b2@example.com", Section: "A"},
+	}
+
+	results, applied := store.BulkApply(context.Background(), ops, true)
+	if !applied {
+		t.Fatalf("Expected batch to be applied, got %+v", results)
+	}
+
+	for i, result := range results {
+		if result.Err != nil || result.Seat.Section != "A" {
+			t.Errorf("Operation %d: expected seat in A, got %+v", i, result)
+		}
+	}
+
+	if len(store.GetAllAllocations("B")) != 0 {
+		t.Error("Expected section B to be empty")
+	}
+}
+
+func TestBulkApply_AllOrNothingRollsBack(t *testing.T) {
+	store := NewStore()
+	purchaseTestTickets(t, store, "a@example.com", "b@example.com")
+
+	ops := []BulkOp{
+		{Kind: BulkRemove, Email: "a@example.com"},
+		{Kind: BulkMove, Email: "b@example.com", Section: "B", SeatNumber: 3},
+		{Kind: BulkRemove, Email: "missing@example.com"},
+	}
+
+	results, applied := store.BulkApply(context.Background(), ops, true)
+	if applied {
+		t.Fatal("Expected batch to be rolled back")
+	}
+
+	if results[0].Err != ErrRolledBack || results[1].Err != ErrRolledBack {
+		t.Errorf("Expected successful operations to report ErrRolledBack, got %+v", results)
+	}
+
+	if results[2].Err != ErrTicketNotFound {
+		t.Errorf("Expected ErrTicketNotFound, got %v", results[2].Err)
+	}
+
+	if _, err := store.GetTicketByEmail("a@example.com"); err != nil {
+		t.Errorf("Expected removed ticket to be restored, got: %v", err)
+	}
+
+	b, _ := store.GetTicketByEmail("b@example.com")
+	if b.Seat.Section != "A" {
+		t.Errorf("Expected move to be rolled back, got %v", b.Seat)
+	}
+
+	// The restored seat map must still reject double booking
+	if _, err := store.ModifySeat(context.Background(), "b@example.com", "A", 1); err != ErrSeatAlreadyOccupied {
+		t.Errorf("Expected ErrSeatAlreadyOccupied, got: %v", err)
+	}
+}
+
+func TestBulkApply_BestEffort(t *testing.T) {
+	store := NewStore()
+	purchaseTestTickets(t, store, "a@example.com", "b@example.com")
+
+	ops := []BulkOp{
+		{Kind: BulkRemove, Email: "missing@example.com"},
+		{Kind: BulkRemove, Email: "a@example.com"},
+	}
+
+	results, applied := store.BulkApply(context.Background(), ops, false)
+	if !applied {
+		t.Fatal("Expected best-effort batch to be applied")
+	}
+
+	if results[0].Err != ErrTicketNotFound || results[1].Err != nil {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+
+	if _, err := store.GetTicketByEmail("a@example.com"); err != ErrTicketNotFound {
+		t.Errorf("Expected a@example.com to be removed, got: %v", err)
+	}
+}
+
+func TestBulkApply_SeatsWaitlist(t *testing.T) {
+	store := NewStore()
+	for i := 1; i <= 15; i++ {
+		purchaseTestTickets(t, store, fmt.Sprintf("user%d@example.com", i))
+	}
+
+	report, err := store.DecommissionSection(context.Background(), "A", model.OverflowWaitlist)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// A rolled-back batch must leave the waitlist alone
+	ops := []BulkOp{
+		{Kind: BulkRemove, Email: report.Moved[0].User.Email},
+		{Kind: BulkRemove, Email: "missing@example.com"},
+	}
+	if _, applied := store.BulkApply(context.Background(), ops, true); applied {
+		t.Fatal("Expected batch to be rolled back")
+	}
+	if len(store.Waitlist()) != len(report.Waitlisted) {
+		t.Fatalf("Expected %d waitlisted passengers after rollback, got %d", len(report.Waitlisted), len(store.Waitlist()))
+	}
+
+	if _, applied := store.BulkApply(context.Background(), ops[:1], true); !applied {
+		t.Fatal("Expected batch to be applied")
+	}
+	first := report.Waitlisted[0].User.Email
+	if _, err := store.GetTicketByEmail(first); err != nil {
+		t.Errorf("Expected %s to be seated from the waitlist, got: %v", first, err)
+	}
+	if len(store.Waitlist()) != len(report.Waitlisted)-1 {
+		t.Errorf("Expected %d waitlisted passengers, got %d", len(report.Waitlisted)-1, len(store.Waitlist()))
+	}
+}
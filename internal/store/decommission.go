@@ -0,0 +1,134 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cloudbees/train-ticket-service/internal/model"
+)
+
+// DecommissionSection takes a section out of service and reseats its
+// passengers into free seats elsewhere, in seat order. Passengers who cannot
+// be placed lose their seat and are waitlisted or refunded according to
+// overflow.
+func (s *Store) DecommissionSection(ctx context.Context, section, overflow string) (*model.ReaccommodationReport, error) {
+	s.lock(ctx)
+	defer s.unlock()
+
+	if !s.layout.HasSection(section) {
+		return nil, fmt.Errorf("%w: invalid section %s", ErrInvalidSeat, section)
+	}
+	if overflow != model.OverflowWaitlist && overflow != model.OverflowRefund {
+		return nil, fmt.Errorf("unknown overflow policy %q", overflow)
+	}
+	if s.outOfService[section] {
+		return nil, ErrSectionOutOfService
+	}
+
+	s.releaseExpiredHoldsLocked()
+	s.outOfService[section] = true
+
+	var affected []*model.Ticket
+	for _, ticket := range s.tickets {
+		if ticket.Seat.Section == section {
+			affected = append(affected, ticket)
+		}
+	}
+	sort.Slice(affected, func(i, j int) bool {
+		return affected[i].Seat.SeatNumber < affected[j].Seat.SeatNumber
+	})
+
+	report := &model.ReaccommodationReport{Section: section}
+	for _, ticket := range affected {
+		from := ticket.Seat
+
+		if seat, err := s.findNextAvailableSeat(); err == nil {
+			if _, err := s.moveLocked(ticket.User.Email, seat.Section, seat.SeatNumber); err != nil {
+				return nil, err
+			}
+			report.Moved = append(report.Moved, model.SeatMove{User: ticket.User, From: from, To: *seat})
+			continue
+		}
+
+		s.removeLocked(ticket)
+		displaced := model.DisplacedPassenger{User: ticket.User, From: from}
+		if overflow == model.OverflowRefund {
+			displaced.RefundCents = ticket.PricePaid
+			report.Refunded = append(report.Refunded, displaced)
+			continue
+		}
+
+		s.waitlist = append(s.waitlist, model.WaitlistEntry{Ticket: *ticket, Since: s.now()})
+		report.Waitlisted = append(report.Waitlisted, displaced)
+	}
+
+	return report, nil
+}
+
+// ReinstateSection returns a section to service and seats waitlisted
+// passengers in the order they were displaced.
+func (s *Store) ReinstateSection(ctx context.Context, section string) ([]model.SeatMove, error) {
+	s.lock(ctx)
+	defer s.unlock()
+
+	if !s.layout.HasSection(section) {
+		return nil, fmt.Errorf("%w: invalid section %s", ErrInvalidSeat, section)
+	}
+	if !s.outOfService[section] {
+		return nil, ErrSectionInService
+	}
+
+	s.releaseExpiredHoldsLocked()
+	delete(s.outOfService, section)
+
+	return s.promoteWaitlistLocked(), nil
+}
+
+func (s *Store) IsSectionInService(section string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.layout.HasSection(section) && !s.outOfService[section]
+}
+
+func (s *Store) Waitlist() []model.WaitlistEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]model.WaitlistEntry, len(s.waitlist))
+	copy(entries, s.waitlist)
+	return entries
+}
+
+// promoteWaitlistLocked reissues waitlisted tickets while free seats remain.
+func (s *Store) promoteWaitlistLocked() []model.SeatMove {
+	var placed []model.SeatMove
+	for len(s.waitlist) > 0 {
+		seat, err := s.findNextAvailableSeat()
+		if err != nil {
+			break
+		}
+
+		entry := s.waitlist[0]
+		s.waitlist = s.waitlist[1:]
+
+		ticket := entry.Ticket
+		from := ticket.Seat
+		ticket.Seat = *seat
+		s.tickets[ticket.User.Email] = &ticket
+		s.seats[seatKey(seat.Section, seat.SeatNumber)] = true
+
+		placed = append(placed, model.SeatMove{User: ticket.User, From: from, To: *seat})
+	}
+	return placed
+}
+
+func (s *Store) removeFromWaitlistLocked(email string) {
+	for i, entry := range s.waitlist {
+		if entry.Ticket.User.Email == email {
+			s.waitlist = append(s.waitlist[:i], s.waitlist[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cloudbees/train-ticket-service/internal/config"
+	"github.com/cloudbees/train-ticket-service/internal/model"
+)
+
+func TestDecommissionSection_Reseats(t *testing.T) {
+	store := NewStore()
+	purchaseTestTickets(t, store, "a1@example.com", "a2@example.com")
+
+	report, err := store.DecommissionSection(context.Background(), "A", model.OverflowWaitlist)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(report.Moved) != 2 || len(report.Waitlisted) != 0 {
+		t.Fatalf("Expected 2 moved passengers, got %+v", report)
+	}
+
+	for _, move := range report.Moved {
+		if move.From.Section != "A" || move.To.Section != "B" {
+			t.Errorf("Expected move from A to B, got %+v", move)
+		}
+	}
+
+	if len(store.GetAllAllocations("A")) != 0 {
+		t.Error("Expected section A to be empty")
+	}
+
+	// New purchases and moves must avoid the out-of-service section
+	purchaseTestTickets(t, store, "new@example.com")
+	ticket, _ := store.GetTicketByEmail("new@example.com")
+	if ticket.Seat.Section != "B" {
+		t.Errorf("Expected new purchase in B, got %v", ticket.Seat)
+	}
+
+	if _, err := store.ModifySeat(context.Background(), "new@example.com", "A", 5); err != ErrSectionOutOfService {
+		t.Errorf("Expected ErrSectionOutOfService, got: %v", err)
+	}
+
+	if _, err := store.DecommissionSection(context.Background(), "A", model.OverflowWaitlist); err != ErrSectionOutOfService {
+		t.Errorf("Expected ErrSectionOutOfService, got: %v", err)
+	}
+}
+
+func TestDecommissionSection_Overflow(t *testing.T) {
+	tests := []struct {
+		policy         string
+		wantWaitlisted int
+		wantRefunded   int
+	}{
+		{model.OverflowWaitlist, 5, 0},
+		{model.OverflowRefund, 0, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			store := NewStore()
+			// 10 passengers in A and 5 in B leaves room for only 5 of A's passengers
+			for i := 1; i <= 15; i++ {
+				purchaseTestTickets(t, store, fmt.Sprintf("user%d@example.com", i))
+			}
+
+			report, err := store.DecommissionSection(context.Background(), "A", tt.policy)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if len(report.Moved) != 5 || len(report.Waitlisted) != tt.wantWaitlisted || len(report.Refunded) != tt.wantRefunded {
+				t.Errorf("Unexpected report: moved=%d waitlisted=%d refunded=%d",
+					len(report.Moved), len(report.Waitlisted), len(report.Refunded))
+			}
+
+			for _, p := range report.Refunded {
+				if p.RefundCents == 0 {
+					t.Errorf("Expected refund amount for %s", p.User.Email)
+				}
+			}
+
+			if len(store.Waitlist()) != tt.wantWaitlisted {
+				t.Errorf("Expected waitlist of %d, got %d", tt.wantWaitlisted, len(store.Waitlist()))
+			}
+		})
+	}
+}
+
+func TestReinstateSection_SeatsWaitlist(t *testing.T) {
+	store := NewStore()
+	for i := 1; i <= 15; i++ {
+		purchaseTestTickets(t, store, fmt.Sprintf("user%d@example.com", i))
+	}
+
+	report, err := store.DecommissionSection(context.Background(), "A", model.OverflowWaitlist)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Removing a passenger frees a seat for the first waitlisted passenger
+	if err := store.RemoveTicket(context.Background(), report.Moved[0].User.Email); err != nil {
+		t.Fatalf("Failed to remove ticket: %v", err)
+	}
+
+	first := report.Waitlisted[0].User.Email
+	if _, err := store.GetTicketByEmail(first); err != nil {
+		t.Errorf("Expected %s to be seated from the waitlist, got: %v", first, err)
+	}
+
+	placed, err := store.ReinstateSection(context.Background(), "A")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(placed) != 4 || len(store.Waitlist()) != 0 {
+		t.Errorf("Expected remaining 4 waitlisted passengers to be seated, got %d placed, %d waiting",
+			len(placed), len(store.Waitlist()))
+	}
+
+	if _, err := store.ReinstateSection(context.Background(), "A"); err != ErrSectionInService {
+		t.Errorf("Expected ErrSectionInService, got: %v", err)
+	}
+}
+
+func TestWaitlist_PromotedOverReleasedHolds(t *testing.T) {
+	store := NewStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// 10 passengers in A, 3 confirmed and 2 held in B
+	for i := 1; i <= 13; i++ {
+		purchaseTestTickets(t, store, fmt.Sprintf("user%d@example.com", i))
+	}
+	for _, email := range []string{"held1@example.com", "held2@example.com"} {
+		if _, err := store.HoldTicket(ctx, model.User{Email: email}, config.RouteFrom, config.RouteTo, config.TicketPriceCents, now.Add(time.Minute)); err != nil {
+			t.Fatalf("Failed to hold ticket: %v", err)
+		}
+	}
+
+	report, err := store.DecommissionSection(ctx, "A", model.OverflowWaitlist)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(report.Waitlisted) != 5 {
+		t.Fatalf("Expected 5 waitlisted passengers, got %d", len(report.Waitlisted))
+	}
+
+	// Confirming over a hold gives its seat to the waitlist first
+	_, err = store.PurchaseTicket(ctx, model.User{Email: "held1@example.com"}, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	if err != ErrTrainFull {
+		t.Errorf("Expected ErrTrainFull, got: %v", err)
+	}
+	if _, err := store.GetTicketByEmail(report.Waitlisted[0].User.Email); err != nil {
+		t.Errorf("Expected %s to be seated from the waitlist, got: %v", report.Waitlisted[0].User.Email, err)
+	}
+
+	// So does an expired hold, ahead of the next purchaser
+	now = now.Add(2 * time.Minute)
+	_, err = store.PurchaseTicket(ctx, model.User{Email: "late@example.com"}, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	if err != ErrTrainFull {
+		t.Errorf("Expected ErrTrainFull, got: %v", err)
+	}
+	if _, err := store.GetTicketByEmail(report.Waitlisted[1].User.Email); err != nil {
+		t.Errorf("Expected %s to be seated from the waitlist, got: %v", report.Waitlisted[1].User.Email, err)
+	}
+	if n := len(store.Waitlist()); n != 3 {
+		t.Errorf("Expected 3 passengers still waiting, got %d", n)
+	}
+}
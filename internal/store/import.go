@@ -0,0 +1,90 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudbees/train-ticket-service/internal/model"
+)
+
+// ConflictError is a booking that clashes with an earlier one in the same
+// import, at index Other.
+type ConflictError struct {
+	Err   error
+	Other int
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%v: conflicts with booking %d of the import", e.Err, e.Other)
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// ImportBookings checks that each ticket can have its seat: the seat exists,
+// is in service and is free, and neither its email nor its seat is already
+// taken in the store or by an earlier ticket. errs[i] is ticket i's conflict,
+// nil if it has none.
+//
+// With commit, and only when no ticket has a conflict, every ticket is booked
+// as confirmed. It reports whether they were.
+func (s *Store) ImportBookings(ctx context.Context, tickets []model.Ticket, commit bool) (errs []error, committed bool) {
+	s.lock(ctx)
+	defer s.unlock()
+
+	s.releaseExpiredHoldsLocked()
+
+	errs = make([]error, len(tickets))
+	emails := make(map[string]int, len(tickets))
+	seats := make(map[string]int, len(tickets))
+	failed := false
+	for i, t := range tickets {
+		errs[i] = s.checkImportLocked(t, emails, seats)
+		if errs[i] != nil {
+			failed = true
+			continue
+		}
+		emails[t.User.Email] = i
+		seats[seatKey(t.Seat.Section, t.Seat.SeatNumber)] = i
+	}
+
+	if !commit || failed {
+		return errs, false
+	}
+
+	for _, t := range tickets {
+		ticket := t
+		ticket.ID = randomID()
+		ticket.Status = model.TicketStatusConfirmed
+		s.tickets[ticket.User.Email] = &ticket
+		s.seats[seatKey(ticket.Seat.Section, ticket.Seat.SeatNumber)] = true
+		s.removeFromWaitlistLocked(ticket.User.Email)
+	}
+	return errs, true
+}
+
+func (s *Store) checkImportLocked(t model.Ticket, emails, seats map[string]int) error {
+	if !s.layout.HasSection(t.Seat.Section) || !s.layout.HasSeatNumber(t.Seat.SeatNumber) {
+		return fmt.Errorf("%w: %s-%d is not in the layout", ErrInvalidSeat, t.Seat.Section, t.Seat.SeatNumber)
+	}
+	if s.outOfService[t.Seat.Section] {
+		return ErrSectionOutOfService
+	}
+
+	if other, ok := emails[t.User.Email]; ok {
+		return &ConflictError{Err: ErrUserAlreadyHasTicket, Other: other}
+	}
+	if _, exists := s.tickets[t.User.Email]; exists {
+		return ErrUserAlreadyHasTicket
+	}
+
+	key := seatKey(t.Seat.Section, t.Seat.SeatNumber)
+	if other, ok := seats[key]; ok {
+		return &ConflictError{Err: ErrSeatAlreadyOccupied, Other: other}
+	}
+	if s.seats[key] {
+		return ErrSeatAlreadyOccupied
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cloudbees/train-ticket-service/internal/model"
+)
+
+func booking(email, section string, seatNumber int32) model.Ticket {
+	return model.Ticket{
+		User: model.User{Email: email, FirstName: "Test", LastName: "User"},
+		Seat: model.Seat{Section: section, SeatNumber: seatNumber},
+	}
+}
+
+func TestImportBookings_Conflicts(t *testing.T) {
+	store := NewStore()
+	purchaseTestTickets(t, store, "existing@example.com") // A-1
+
+	tickets := []model.Ticket{
+		booking("new@example.com", "B", 1),
+		booking("existing@example.com", "B", 2),
+		booking("other@example.com", "A", 1),
+		booking("new@example.com", "B", 3),
+		booking("third@example.com", "B", 1),
+		booking("fourth@example.com", "C", 1),
+	}
+
+	errs, committed := store.ImportBookings(context.Background(), tickets, true)
+	if committed {
+		t.Fatal("Expected nothing to be committed")
+	}
+
+	want := []error{nil, ErrUserAlreadyHasTicket, ErrSeatAlreadyOccupied, ErrUserAlreadyHasTicket, ErrSeatAlreadyOccupied, ErrInvalidSeat}
+	for i, err := range errs {
+		if !errors.Is(err, want[i]) {
+			t.Errorf("Booking %d: expected %v, got %v", i, want[i], err)
+		}
+	}
+
+	var conflict *ConflictError
+	if !errors.As(errs[4], &conflict) || conflict.Other != 0 {
+		t.Errorf("Expected booking 4 to clash with booking 0, got %v", errs[4])
+	}
+	if errors.As(errs[2], &conflict) {
+		t.Errorf("Expected booking 2 to clash with the store, got %v", errs[2])
+	}
+
+	if len(store.GetAllAllocations("B")) != 0 {
+		t.Error("Expected a failed import to book nobody")
+	}
+}
+
+func TestImportBookings_Commit(t *testing.T) {
+	store := NewStore()
+	tickets := []model.Ticket{booking("a@example.com", "B", 5), booking("b@example.com", "A", 2)}
+
+	if _, committed := store.ImportBookings(context.Background(), tickets, false); committed {
+		t.Fatal("Expected a dry run not to commit")
+	}
+	if len(store.GetAllAllocations("")) != 0 {
+		t.Fatal("Expected a dry run to book nobody")
+	}
+
+	errs, committed := store.ImportBookings(context.Background(), tickets, true)
+	if !committed {
+		t.Fatalf("Expected the import to commit, got %v", errs)
+	}
+	ticket, err := store.GetTicketByEmail("a@example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if ticket.Seat != (model.Seat{Section: "B", SeatNumber: 5}) || ticket.Status != model.TicketStatusConfirmed {
+		t.Errorf("Expected a confirmed ticket in B-5, got %+v", ticket)
+	}
+}
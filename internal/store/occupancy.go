@@ -0,0 +1,41 @@
+package store
+
+// Occupancy is a point-in-time summary of the train for monitoring.
+type Occupancy struct {
+	// Occupied counts confirmed tickets per section, including empty sections.
+	Occupied map[string]int
+	// Holds counts unexpired tickets awaiting email verification.
+	Holds        int
+	Waitlist     int
+	OutOfService map[string]bool
+}
+
+func (s *Store) Occupancy() Occupancy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	occ := Occupancy{
+		Occupied:     make(map[string]int, len(s.layout.Sections)),
+		Waitlist:     len(s.waitlist),
+		OutOfService: make(map[string]bool),
+	}
+	for _, section := range s.layout.Sections {
+		occ.Occupied[section] = 0
+		if s.outOfService[section] {
+			occ.OutOfService[section] = true
+		}
+	}
+
+	now := s.now()
+	for _, ticket := range s.tickets {
+		switch {
+		case ticket.HoldExpired(now):
+		case ticket.IsPending():
+			occ.Holds++
+		default:
+			occ.Occupied[ticket.Seat.Section]++
+		}
+	}
+
+	return occ
+}
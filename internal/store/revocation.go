@@ -0,0 +1,52 @@
+package store
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/cloudbees/train-ticket-service/internal/model"
+)
+
+func randomID() string {
+	b := make([]byte, 9)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// revokeLocked revokes the tokens issued for ticket's current seat. Holds
+// never get a token, so only confirmed tickets are recorded.
+func (s *Store) revokeLocked(ticket *model.Ticket, reason string) {
+	if !ticket.IsPending() {
+		s.revocations = append(s.revocations, model.Revocation{
+			Seq:       int64(len(s.revocations)) + 1,
+			TicketID:  ticket.ID,
+			Revision:  ticket.Revision,
+			Reason:    reason,
+			RevokedAt: s.now(),
+		})
+	}
+	ticket.Revision++
+}
+
+// RevocationCursor is where a reader's last sync ended. Sequence numbers
+// restart with the store, so Epoch names the store they count in.
+type RevocationCursor struct {
+	Epoch string
+	Seq   int64
+}
+
+// Revocations returns the revocations after since and the cursor to pass
+// next time. A cursor from another epoch, as kept from before a restart,
+// starts over from the beginning.
+func (s *Store) Revocations(since RevocationCursor) ([]model.Revocation, RevocationCursor) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seq := since.Seq
+	if since.Epoch != s.epoch || seq < 0 || seq > int64(len(s.revocations)) {
+		seq = 0
+	}
+	revocations := make([]model.Revocation, len(s.revocations)-int(seq))
+	copy(revocations, s.revocations[seq:])
+	return revocations, RevocationCursor{Epoch: s.epoch, Seq: int64(len(s.revocations))}
+}
@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudbees/train-ticket-service/internal/config"
+	"github.com/cloudbees/train-ticket-service/internal/model"
+)
+
+func TestRevocations(t *testing.T) {
+	store := NewStore()
+	purchaseTestTickets(t, store, "a@example.com", "b@example.com")
+	a, _ := store.GetTicketByEmail("a@example.com")
+	b, _ := store.GetTicketByEmail("b@example.com")
+	if a.ID == "" || a.ID == b.ID {
+		t.Fatalf("Expected distinct ticket ids, got %q and %q", a.ID, b.ID)
+	}
+
+	if _, err := store.ModifySeat(context.Background(), "a@example.com", "B", 1); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := store.RemoveTicket(context.Background(), "b@example.com"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if moved, _ := store.GetTicketByEmail("a@example.com"); moved.Revision != 1 {
+		t.Errorf("Expected a seat change to bump the revision, got %d", moved.Revision)
+	}
+
+	revocations, cursor := store.Revocations(RevocationCursor{})
+	if cursor.Seq != 2 || len(revocations) != 2 {
+		t.Fatalf("Expected 2 revocations, got %+v (cursor %+v)", revocations, cursor)
+	}
+	if r := revocations[0]; r.TicketID != a.ID || r.Revision != 0 || r.Reason != model.RevokedSeatChanged {
+		t.Errorf("Expected a's first seat to be revoked, got %+v", r)
+	}
+	if r := revocations[1]; r.TicketID != b.ID || r.Reason != model.RevokedRemoved {
+		t.Errorf("Expected b to be revoked as removed, got %+v", r)
+	}
+
+	if revocations, _ := store.Revocations(RevocationCursor{Epoch: cursor.Epoch, Seq: 1}); len(revocations) != 1 || revocations[0].Seq != 2 {
+		t.Errorf("Expected only the revocation after 1, got %+v", revocations)
+	}
+	if revocations, _ := store.Revocations(RevocationCursor{Epoch: cursor.Epoch, Seq: 10}); len(revocations) != 2 {
+		t.Errorf("Expected a cursor from the future to start over, got %+v", revocations)
+	}
+}
+
+func TestRevocations_StaleCursorAfterRestart(t *testing.T) {
+	before := NewStore()
+	purchaseTestTickets(t, before, "a@example.com")
+	if err := before.RemoveTicket(context.Background(), "a@example.com"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	_, stale := before.Revocations(RevocationCursor{})
+
+	// The restarted store's log grows past the stale cursor
+	after := NewStore()
+	purchaseTestTickets(t, after, "b@example.com", "c@example.com")
+	for _, email := range []string{"b@example.com", "c@example.com"} {
+		if err := after.RemoveTicket(context.Background(), email); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	revocations, cursor := after.Revocations(stale)
+	if len(revocations) != 2 {
+		t.Errorf("Expected a cursor from before the restart to get every revocation, got %+v", revocations)
+	}
+	if cursor.Epoch == stale.Epoch || cursor.Seq != 2 {
+		t.Errorf("Expected a cursor in the new epoch, got %+v", cursor)
+	}
+}
+
+func TestRevocations_RolledBackAndHolds(t *testing.T) {
+	store := NewStore()
+	purchaseTestTickets(t, store, "a@example.com")
+
+	ops := []BulkOp{
+		{Kind: BulkMove, Email: "a@example.com", Section: "B", SeatNumber: 1},
+		{Kind: BulkRemove, Email: "missing@example.com"},
+	}
+	if _, applied := store.BulkApply(context.Background(), ops, true); applied {
+		t.Fatal("Expected the batch to be rolled back")
+	}
+
+	held := model.User{Email: "held@example.com", FirstName: "Test", LastName: "User"}
+	if _, err := store.HoldTicket(context.Background(), held, config.RouteFrom, config.RouteTo, config.TicketPriceCents, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := store.RemoveTicket(context.Background(), "held@example.com"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if revocations, cursor := store.Revocations(RevocationCursor{}); len(revocations) != 0 || cursor.Seq != 0 {
+		t.Errorf("Expected no revocations, got %+v", revocations)
+	}
+	if a, _ := store.GetTicketByEmail("a@example.com"); a.Revision != 0 {
+		t.Errorf("Expected the rollback to restore the revision, got %d", a.Revision)
+	}
+}
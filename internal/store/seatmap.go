@@ -0,0 +1,60 @@
+package store
+
+import "github.com/cloudbees/train-ticket-service/internal/model"
+
+// Seat statuses on the seat map.
+const (
+	SeatFree     = "free"
+	SeatHeld     = "held"
+	SeatOccupied = "occupied"
+	SeatBlocked  = "blocked"
+)
+
+// SeatState is one seat on the seat map. Email is the holder of a held or
+// occupied seat so callers can recognise their own; it is not for display.
+type SeatState struct {
+	Seat   model.Seat
+	Status string
+	Email  string
+}
+
+// SeatMap returns every seat in section, or in all sections when section is
+// empty, in layout order. Seats in out-of-service sections are blocked.
+func (s *Store) SeatMap(section string) ([]SeatState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if section != "" && !s.layout.HasSection(section) {
+		return nil, ErrInvalidSeat
+	}
+
+	now := s.now()
+	taken := make(map[string]*model.Ticket)
+	for _, ticket := range s.tickets {
+		if !ticket.HoldExpired(now) {
+			taken[seatKey(ticket.Seat.Section, ticket.Seat.SeatNumber)] = ticket
+		}
+	}
+
+	var states []SeatState
+	for _, sec := range s.layout.Sections {
+		if section != "" && sec != section {
+			continue
+		}
+		for i := int32(1); i <= s.layout.SeatsPerSection; i++ {
+			state := SeatState{Seat: model.Seat{Section: sec, SeatNumber: i}, Status: SeatFree}
+			if ticket := taken[seatKey(sec, i)]; ticket != nil {
+				state.Email = ticket.User.Email
+				state.Status = SeatOccupied
+				if ticket.IsPending() {
+					state.Status = SeatHeld
+				}
+			}
+			if s.outOfService[sec] {
+				state.Status = SeatBlocked
+			}
+			states = append(states, state)
+		}
+	}
+	return states, nil
+}
@@ -1,59 +1,179 @@
 package store
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/cloudbees/train-ticket-service/internal/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
 )
 
+const tracerName = "github.com/cloudbees/train-ticket-service/internal/store"
+
 var (
 	ErrTicketNotFound       = errors.New("ticket not found")
 	ErrSeatAlreadyOccupied  = errors.New("seat is already occupied")
 	ErrTrainFull            = errors.New("train is full")
+	ErrSectionFull          = errors.New("section is full")
 	ErrInvalidSeat          = errors.New("invalid seat")
 	ErrUserAlreadyHasTicket = errors.New("user already has a ticket")
+	ErrTicketNotPending     = errors.New("ticket is not awaiting verification")
+	ErrTicketPending        = errors.New("ticket is awaiting verification")
+	ErrSwapNotFound         = errors.New("seat swap not found")
+	ErrSwapWithSelf         = errors.New("cannot swap seats with yourself")
+	ErrSwapStale            = errors.New("seats changed since the swap was requested")
+	ErrSectionOutOfService  = errors.New("section is out of service")
+	ErrSectionInService     = errors.New("section is already in service")
+	ErrStoreClosed          = errors.New("store is closed")
 )
 
 type Store struct {
 	mu      sync.RWMutex
-	tickets map[string]*model.Ticket 
-	seats   map[string]bool          
+	layout  model.Layout
+	tickets map[string]*model.Ticket
+	seats   map[string]bool
+	swaps   map[string]*model.SeatSwap
+	nextID  int
+	now     func() time.Time
+
+	outOfService map[string]bool
+	waitlist     []model.WaitlistEntry
+	revocations  []model.Revocation
+	// epoch is random per store, so revocation cursors from another
+	// process are recognised.
+	epoch string
+
+	closed bool
+
+	subMu       sync.Mutex
+	subscribers map[chan struct{}]struct{}
 }
 
 func NewStore() *Store {
+	return NewStoreWithLayout(model.DefaultLayout())
+}
+
+func NewStoreWithLayout(layout model.Layout) *Store {
 	return &Store{
+		layout:  layout,
 		tickets: make(map[string]*model.Ticket),
 		seats:   make(map[string]bool),
+		swaps:   make(map[string]*model.SeatSwap),
+		now:     time.Now,
+
+		outOfService: make(map[string]bool),
+		epoch:        randomID(),
+		subscribers:  make(map[chan struct{}]struct{}),
 	}
 }
 
-func (s *Store) PurchaseTicket(user model.User, from, to string, pricePaid int32) (*model.Ticket, error) {
+// Ready reports whether the store can take writes. It takes the write lock,
+// so a store wedged by a stuck writer never reports ready.
+func (s *Store) Ready() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.closed {
+		return ErrStoreClosed
+	}
+	return nil
+}
+
+// Close marks the store as shutting down so readiness checks fail.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	return nil
+}
+
+func (s *Store) PurchaseTicket(ctx context.Context, user model.User, from, to string, pricePaid int32) (*model.Ticket, error) {
+	return s.PurchaseTicketOnBehalf(ctx, user, "", from, to, pricePaid)
+}
+
+// PurchaseTicketOnBehalf books a ticket for user and records purchasedBy as the acting admin.
+// A confirmed purchase supersedes an unverified hold on the same email.
+func (s *Store) PurchaseTicketOnBehalf(ctx context.Context, user model.User, purchasedBy, from, to string, pricePaid int32) (*model.Ticket, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "store.PurchaseTicket")
+	defer span.End()
+
+	s.lock(ctx)
+	defer s.unlock()
+
+	if existing, exists := s.tickets[user.Email]; exists {
+		if !existing.IsPending() {
+			return nil, ErrUserAlreadyHasTicket
+		}
+		s.removeLocked(existing)
+		s.promoteWaitlistLocked()
+	}
+
+	return copied(s.bookLocked(ctx, &model.Ticket{
+		From:        from,
+		To:          to,
+		User:        user,
+		PricePaid:   pricePaid,
+		PurchasedBy: purchasedBy,
+		Status:      model.TicketStatusConfirmed,
+	}))
+}
+
+// HoldTicket books a seat for an unverified purchase. The seat is released
+// automatically if ConfirmTicket is not called before expiresAt.
+func (s *Store) HoldTicket(ctx context.Context, user model.User, from, to string, pricePaid int32, expiresAt time.Time) (*model.Ticket, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "store.HoldTicket")
+	defer span.End()
+
+	s.lock(ctx)
+	defer s.unlock()
+
+	s.releaseExpiredHoldsLocked()
+
 	if _, exists := s.tickets[user.Email]; exists {
 		return nil, ErrUserAlreadyHasTicket
 	}
 
-	seat, err := s.findNextAvailableSeat()
-	if err != nil {
-		return nil, err
+	return copied(s.bookLocked(ctx, &model.Ticket{
+		From:          from,
+		To:            to,
+		User:          user,
+		PricePaid:     pricePaid,
+		Status:        model.TicketStatusPendingVerification,
+		HoldExpiresAt: expiresAt,
+	}))
+}
+
+// ConfirmTicket marks an unverified ticket as confirmed.
+func (s *Store) ConfirmTicket(ctx context.Context, email string) (*model.Ticket, error) {
+	s.lock(ctx)
+	defer s.unlock()
+
+	s.releaseExpiredHoldsLocked()
+
+	ticket, exists := s.tickets[email]
+	if !exists {
+		return nil, ErrTicketNotFound
 	}
 
-	ticket := &model.Ticket{
-		From:      from,
-		To:        to,
-		User:      user,
-		PricePaid: pricePaid,
-		Seat:      *seat,
+	if !ticket.IsPending() {
+		return nil, ErrTicketNotPending
 	}
 
-	s.tickets[user.Email] = ticket
-	s.seats[seatKey(seat.Section, seat.SeatNumber)] = true
+	ticket.Status = model.TicketStatusConfirmed
+	ticket.HoldExpiresAt = time.Time{}
 
-	return ticket, nil
+	return copied(ticket, nil)
+}
+
+func (s *Store) Layout() model.Layout {
+	return s.layout
 }
 
 func (s *Store) GetTicketByEmail(email string) (*model.Ticket, error) {
@@ -61,54 +181,77 @@ func (s *Store) GetTicketByEmail(email string) (*model.Ticket, error) {
 	defer s.mu.RUnlock()
 
 	ticket, exists := s.tickets[email]
-	if !exists {
+	if !exists || ticket.HoldExpired(s.now()) {
 		return nil, ErrTicketNotFound
 	}
 
-	return ticket, nil
+	return copied(ticket, nil)
 }
 
+// GetAllAllocations returns the live allocations in sectionFilter, or in every
+// section when it is empty, ordered by section and seat.
 func (s *Store) GetAllAllocations(sectionFilter string) []*model.Ticket {
+	return s.ListAllocations(AllocationQuery{Section: sectionFilter}).Allocations
+}
+
+// CountByEmailDomain returns how many live tickets, held or confirmed, belong
+// to emails at domain.
+func (s *Store) CountByEmailDomain(domain string) int {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	var allocations []*model.Ticket
-	for _, ticket := range s.tickets {
-		if sectionFilter == "" || ticket.Seat.Section == sectionFilter {
-			allocations = append(allocations, ticket)
+	now := s.now()
+
+	count := 0
+	for email, ticket := range s.tickets {
+		if ticket.HoldExpired(now) {
+			continue
+		}
+		if i := strings.LastIndexByte(email, '@'); i >= 0 && strings.EqualFold(email[i+1:], domain) {
+			count++
 		}
 	}
-
-	return allocations
+	return count
 }
 
-func (s *Store) RemoveTicket(email string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+func (s *Store) RemoveTicket(ctx context.Context, email string) error {
+	s.lock(ctx)
+	defer s.unlock()
+
+	s.releaseExpiredHoldsLocked()
 
 	ticket, exists := s.tickets[email]
 	if !exists {
 		return ErrTicketNotFound
 	}
 
-	delete(s.seats, seatKey(ticket.Seat.Section, ticket.Seat.SeatNumber))
-
-	delete(s.tickets, email)
+	s.removeLocked(ticket)
+	s.promoteWaitlistLocked()
 
 	return nil
 }
 
-func (s *Store) ModifySeat(email, newSection string, newSeatNumber int32) (*model.Ticket, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+func (s *Store) ModifySeat(ctx context.Context, email, newSection string, newSeatNumber int32) (*model.Ticket, error) {
+	s.lock(ctx)
+	defer s.unlock()
 
-	if !model.IsValidSection(newSection) {
+	s.releaseExpiredHoldsLocked()
+
+	return copied(s.moveLocked(email, newSection, newSeatNumber))
+}
+
+func (s *Store) moveLocked(email, newSection string, newSeatNumber int32) (*model.Ticket, error) {
+	if !s.layout.HasSection(newSection) {
 		return nil, fmt.Errorf("%w: invalid section %s", ErrInvalidSeat, newSection)
 	}
-	if !model.IsValidSeatNumber(newSeatNumber) {
+	if !s.layout.HasSeatNumber(newSeatNumber) {
 		return nil, fmt.Errorf("%w: invalid seat number %d", ErrInvalidSeat, newSeatNumber)
 	}
 
+	if s.outOfService[newSection] {
+		return nil, ErrSectionOutOfService
+	}
+
 	ticket, exists := s.tickets[email]
 	if !exists {
 		return nil, ErrTicketNotFound
@@ -121,6 +264,8 @@ func (s *Store) ModifySeat(email, newSection string, newSeatNumber int32) (*mode
 
 	oldSeatKey := seatKey(ticket.Seat.Section, ticket.Seat.SeatNumber)
 	delete(s.seats, oldSeatKey)
+	s.revokeLocked(ticket, model.RevokedSeatChanged)
+	s.dropSwapsLocked(email)
 
 	ticket.Seat.Section = newSection
 	ticket.Seat.SeatNumber = newSeatNumber
@@ -129,25 +274,94 @@ func (s *Store) ModifySeat(email, newSection string, newSeatNumber int32) (*mode
 	return ticket, nil
 }
 
+// lock takes the write lock, tracing how long the caller waited for it.
+func (s *Store) lock(ctx context.Context) {
+	_, span := otel.Tracer(tracerName).Start(ctx, "store.lock_wait")
+	s.mu.Lock()
+	span.End()
+}
+
+func (s *Store) bookLocked(ctx context.Context, ticket *model.Ticket) (*model.Ticket, error) {
+	s.releaseExpiredHoldsLocked()
+
+	_, span := otel.Tracer(tracerName).Start(ctx, "store.seat_search")
+	seat, err := s.findNextAvailableSeat()
+	if err != nil {
+		span.SetStatus(otelcodes.Error, err.Error())
+		span.End()
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("seat.section", seat.Section), attribute.Int("seat.number", int(seat.SeatNumber)))
+	span.End()
+
+	if ticket.ID == "" {
+		ticket.ID = randomID()
+	}
+	ticket.Seat = *seat
+	s.tickets[ticket.User.Email] = ticket
+	s.seats[seatKey(seat.Section, seat.SeatNumber)] = true
+	s.removeFromWaitlistLocked(ticket.User.Email)
+
+	return ticket, nil
+}
+
+// copied returns a copy of ticket, which callers can read after the lock is
+// released while writers change the original.
+func copied(ticket *model.Ticket, err error) (*model.Ticket, error) {
+	if err != nil {
+		return nil, err
+	}
+	c := *ticket
+	return &c, nil
+}
+
+func (s *Store) removeLocked(ticket *model.Ticket) {
+	s.revokeLocked(ticket, model.RevokedRemoved)
+	s.dropSwapsLocked(ticket.User.Email)
+	delete(s.seats, seatKey(ticket.Seat.Section, ticket.Seat.SeatNumber))
+	delete(s.tickets, ticket.User.Email)
+}
+
+// releaseExpiredHoldsLocked frees the seats of expired holds, offering them
+// to waitlisted passengers first.
+func (s *Store) releaseExpiredHoldsLocked() {
+	now := s.now()
+	released := false
+	for _, ticket := range s.tickets {
+		if ticket.HoldExpired(now) {
+			s.removeLocked(ticket)
+			released = true
+		}
+	}
+	if released {
+		s.promoteWaitlistLocked()
+	}
+}
+
 func (s *Store) findNextAvailableSeat() (*model.Seat, error) {
-	for i := int32(1); i <= 10; i++ {
-		key := seatKey("A", i)
-		if !s.seats[key] {
-			return &model.Seat{Section: "A", SeatNumber: i}, nil
+	for _, section := range s.layout.Sections {
+		if s.outOfService[section] {
+			continue
+		}
+		if seat := s.findAvailableSeatInSection(section); seat != nil {
+			return seat, nil
 		}
 	}
 
-	for i := int32(1); i <= 10; i++ {
-		key := seatKey("B", i)
+	return nil, ErrTrainFull
+}
+
+func (s *Store) findAvailableSeatInSection(section string) *model.Seat {
+	for i := int32(1); i <= s.layout.SeatsPerSection; i++ {
+		key := seatKey(section, i)
 		if !s.seats[key] {
-			return &model.Seat{Section: "B", SeatNumber: i}, nil
+			return &model.Seat{Section: section, SeatNumber: i}
 		}
 	}
 
-	return nil, ErrTrainFull
+	return nil
 }
 
 func seatKey(section string, seatNumber int32) string {
 	return fmt.Sprintf("%s-%d", section, seatNumber)
 }
-
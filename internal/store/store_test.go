@@ -1,8 +1,12 @@
 package store
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cloudbees/train-ticket-service/internal/config"
 	"github.com/cloudbees/train-ticket-service/internal/model"
@@ -18,7 +22,7 @@ func TestPurchaseTicket(t *testing.T) {
 	}
 
 	// Test successful purchase
-	ticket, err := store.PurchaseTicket(user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	ticket, err := store.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
@@ -49,7 +53,7 @@ func TestPurchaseTicket(t *testing.T) {
 	}
 
 	// Test duplicate purchase
-	_, err = store.PurchaseTicket(user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	_, err = store.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
 	if err != ErrUserAlreadyHasTicket {
 		t.Errorf("Expected ErrUserAlreadyHasTicket, got: %v", err)
 	}
@@ -65,7 +69,7 @@ func TestGetTicketByEmail(t *testing.T) {
 	}
 
 	// Purchase ticket first
-	_, err := store.PurchaseTicket(user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	_, err := store.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
 	if err != nil {
 		t.Fatalf("Failed to purchase ticket: %v", err)
 	}
@@ -94,8 +98,8 @@ func TestGetAllAllocations(t *testing.T) {
 	user1 := model.User{Email: "user1@example.com", FirstName: "User1", LastName: "One"}
 	user2 := model.User{Email: "user2@example.com", FirstName: "User2", LastName: "Two"}
 
-	store.PurchaseTicket(user1, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
-	store.PurchaseTicket(user2, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	store.PurchaseTicket(context.Background(), user1, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	store.PurchaseTicket(context.Background(), user2, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
 
 	// Test get all allocations
 	allocations := store.GetAllAllocations("")
@@ -110,6 +114,170 @@ func TestGetAllAllocations(t *testing.T) {
 	}
 }
 
+func TestListAllocations(t *testing.T) {
+	store := NewStore()
+	ctx := context.Background()
+
+	names := []string{"Dana", "Ann", "Cara", "Bea", "Eve"}
+	for _, name := range names {
+		user := model.User{Email: strings.ToLower(name) + "@example.com", FirstName: name, LastName: "Smith"}
+		if _, err := store.PurchaseTicket(ctx, user, config.RouteFrom, config.RouteTo, config.TicketPriceCents); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	emails := func(page AllocationPage) []string {
+		var got []string
+		for _, ticket := range page.Allocations {
+			got = append(got, ticket.User.Email)
+		}
+		return got
+	}
+
+	first := store.ListAllocations(AllocationQuery{OrderBy: OrderByName, Limit: 2})
+	if want := []string{"ann@example.com", "bea@example.com"}; !slices.Equal(emails(first), want) || !first.More || first.Total != 5 {
+		t.Fatalf("Expected %v with more of 5, got %v (more %v, total %d)", want, emails(first), first.More, first.Total)
+	}
+
+	// Changes around the cursor must not skip or repeat anyone
+	if err := store.RemoveTicket(context.Background(), "bea@example.com"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := store.PurchaseTicket(ctx, model.User{Email: "abe@example.com", FirstName: "Abe", LastName: "Smith"}, config.RouteFrom, config.RouteTo, config.TicketPriceCents); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	after := KeyOf(first.Allocations[1])
+	second := store.ListAllocations(AllocationQuery{OrderBy: OrderByName, After: &after, Limit: 2})
+	if want := []string{"cara@example.com", "dana@example.com"}; !slices.Equal(emails(second), want) || !second.More {
+		t.Errorf("Expected %v with more, got %v", want, emails(second))
+	}
+
+	bySeat := store.ListAllocations(AllocationQuery{Descending: true, Limit: 1})
+	if len(bySeat.Allocations) != 1 || bySeat.Allocations[0].Seat != (model.Seat{Section: "A", SeatNumber: 5}) {
+		t.Errorf("Expected A-5 first in descending seat order, got %v", bySeat.Allocations)
+	}
+
+	if got := emails(store.ListAllocations(AllocationQuery{Search: "CAR"})); !slices.Equal(got, []string{"cara@example.com"}) {
+		t.Errorf("Expected search to match cara, got %v", got)
+	}
+	if page := store.ListAllocations(AllocationQuery{Status: model.TicketStatusPendingVerification}); page.Total != 0 {
+		t.Errorf("Expected no pending allocations, got %d", page.Total)
+	}
+}
+
+func TestListAllocations_ReturnsCopies(t *testing.T) {
+	store := NewStore()
+	purchaseTestTickets(t, store, "a@example.com")
+
+	listed := store.GetAllAllocations("")[0]
+	ticket, err := store.GetTicketByEmail("a@example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Readers outside the lock must not see later writes
+	if _, err := store.ModifySeat(context.Background(), "a@example.com", "B", 4); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	for _, got := range []*model.Ticket{listed, ticket} {
+		if got.Seat != (model.Seat{Section: "A", SeatNumber: 1}) || got.Revision != 0 {
+			t.Errorf("Expected the ticket as it was read, got %+v", got)
+		}
+	}
+}
+
+func TestCountByEmailDomain(t *testing.T) {
+	store := NewStore()
+
+	for _, email := range []string{"a@corp.example", "b@CORP.example", "c@other.example"} {
+		user := model.User{Email: email, FirstName: "Test", LastName: "User"}
+		if _, err := store.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	if got := store.CountByEmailDomain("corp.example"); got != 2 {
+		t.Errorf("Expected 2 tickets for corp.example, got %d", got)
+	}
+	if got := store.CountByEmailDomain("nobody.example"); got != 0 {
+		t.Errorf("Expected 0 tickets for nobody.example, got %d", got)
+	}
+}
+
+func TestNearbyFreeSeats(t *testing.T) {
+	store := NewStoreWithLayout(model.Layout{Sections: []string{"A", "B", "C"}, SeatsPerSection: 4})
+
+	// Fill A-1 to A-3 and B-1
+	for i := 0; i < 4; i++ {
+		user := model.User{Email: fmt.Sprintf("user%d@example.com", i), FirstName: "Test", LastName: "User"}
+		if _, err := store.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if _, err := store.ModifySeat(context.Background(), "user3@example.com", "B", 1); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := store.DecommissionSection(context.Background(), "C", model.OverflowWaitlist); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	got := store.NearbyFreeSeats("A", 2, 4)
+	want := []model.Seat{{Section: "A", SeatNumber: 4}, {Section: "B", SeatNumber: 2}, {Section: "B", SeatNumber: 3}, {Section: "B", SeatNumber: 4}}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	if got := store.NearbyFreeSeats("A", 2, 1); len(got) != 1 {
+		t.Errorf("Expected 1 suggestion, got %v", got)
+	}
+}
+
+func TestSeatMap(t *testing.T) {
+	store := NewStoreWithLayout(model.Layout{Sections: []string{"A", "B"}, SeatsPerSection: 3})
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	if _, err := store.PurchaseTicket(ctx, model.User{Email: "occupied@example.com"}, config.RouteFrom, config.RouteTo, config.TicketPriceCents); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := store.HoldTicket(ctx, model.User{Email: "held@example.com"}, config.RouteFrom, config.RouteTo, config.TicketPriceCents, now.Add(time.Minute)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := store.HoldTicket(ctx, model.User{Email: "expired@example.com"}, config.RouteFrom, config.RouteTo, config.TicketPriceCents, now); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := store.DecommissionSection(context.Background(), "B", model.OverflowWaitlist); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	states, err := store.SeatMap("")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	var got []string
+	for _, st := range states {
+		got = append(got, fmt.Sprintf("%s-%d %s %s", st.Seat.Section, st.Seat.SeatNumber, st.Status, st.Email))
+	}
+	want := []string{
+		"A-1 occupied occupied@example.com",
+		"A-2 held held@example.com",
+		"A-3 free ",
+		"B-1 blocked ",
+		"B-2 blocked ",
+		"B-3 blocked ",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	if states, _ := store.SeatMap("B"); len(states) != 3 {
+		t.Errorf("Expected 3 seats in section B, got %d", len(states))
+	}
+	if _, err := store.SeatMap("Z"); err != ErrInvalidSeat {
+		t.Errorf("Expected ErrInvalidSeat, got: %v", err)
+	}
+}
+
 func TestRemoveTicket(t *testing.T) {
 	store := NewStore()
 
@@ -120,7 +288,7 @@ func TestRemoveTicket(t *testing.T) {
 	}
 
 	// Purchase ticket
-	_, err := store.PurchaseTicket(user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	_, err := store.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
 	if err != nil {
 		t.Fatalf("Failed to purchase ticket: %v", err)
 	}
@@ -132,7 +300,7 @@ func TestRemoveTicket(t *testing.T) {
 	}
 
 	// Remove ticket
-	err = store.RemoveTicket(user.Email)
+	err = store.RemoveTicket(context.Background(), user.Email)
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
@@ -154,7 +322,7 @@ func TestModifySeat(t *testing.T) {
 	}
 
 	// Purchase ticket
-	ticket, err := store.PurchaseTicket(user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	ticket, err := store.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
 	if err != nil {
 		t.Fatalf("Failed to purchase ticket: %v", err)
 	}
@@ -169,7 +337,7 @@ func TestModifySeat(t *testing.T) {
 	newSeatNumber := int32(5)
 
 	// Modify seat
-	updatedTicket, err := store.ModifySeat(user.Email, newSection, newSeatNumber)
+	updatedTicket, err := store.ModifySeat(context.Background(), user.Email, newSection, newSeatNumber)
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
@@ -183,13 +351,13 @@ func TestModifySeat(t *testing.T) {
 	}
 
 	// Test invalid section
-	_, err = store.ModifySeat(user.Email, "C", 1)
+	_, err = store.ModifySeat(context.Background(), user.Email, "C", 1)
 	if err == nil {
 		t.Error("Expected error for invalid section")
 	}
 
 	// Test invalid seat number
-	_, err = store.ModifySeat(user.Email, "A", 11)
+	_, err = store.ModifySeat(context.Background(), user.Email, "A", 11)
 	if err == nil {
 		t.Error("Expected error for invalid seat number")
 	}
@@ -205,7 +373,7 @@ func TestSeatAllocationOrder(t *testing.T) {
 			FirstName: "User",
 			LastName:  fmt.Sprintf("%d", i),
 		}
-		ticket, err := store.PurchaseTicket(user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+		ticket, err := store.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
 		if err != nil {
 			t.Fatalf("Failed to purchase ticket %d: %v", i, err)
 		}
@@ -223,7 +391,7 @@ func TestSeatAllocationOrder(t *testing.T) {
 
 	// Next ticket should be in section B
 	user11 := model.User{Email: "user11@example.com", FirstName: "User", LastName: "11"}
-	ticket11, err := store.PurchaseTicket(user11, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	ticket11, err := store.PurchaseTicket(context.Background(), user11, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
 	if err != nil {
 		t.Fatalf("Failed to purchase ticket 11: %v", err)
 	}
@@ -247,7 +415,7 @@ func TestTrainFull(t *testing.T) {
 			FirstName: "User",
 			LastName:  fmt.Sprintf("%d", i),
 		}
-		_, err := store.PurchaseTicket(user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+		_, err := store.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
 		if err != nil {
 			t.Fatalf("Failed to purchase ticket %d: %v", i, err)
 		}
@@ -255,9 +423,166 @@ func TestTrainFull(t *testing.T) {
 
 	// Try to purchase one more - should fail
 	user21 := model.User{Email: "user21@example.com", FirstName: "User", LastName: "21"}
-	_, err := store.PurchaseTicket(user21, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	_, err := store.PurchaseTicket(context.Background(), user21, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
 	if err != ErrTrainFull {
 		t.Errorf("Expected ErrTrainFull, got: %v", err)
 	}
 }
 
+func TestHoldTicket(t *testing.T) {
+	store := NewStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	user := model.User{Email: "hold@example.com", FirstName: "Hold", LastName: "Me"}
+
+	ticket, err := store.HoldTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !ticket.IsPending() {
+		t.Errorf("Expected pending ticket, got status %s", ticket.Status)
+	}
+
+	confirmed, err := store.ConfirmTicket(context.Background(), user.Email)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if confirmed.Status != model.TicketStatusConfirmed {
+		t.Errorf("Expected confirmed ticket, got status %s", confirmed.Status)
+	}
+
+	if _, err := store.ConfirmTicket(context.Background(), user.Email); err != ErrTicketNotPending {
+		t.Errorf("Expected ErrTicketNotPending, got: %v", err)
+	}
+}
+
+func TestHoldTicket_Expires(t *testing.T) {
+	store := NewStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	user := model.User{Email: "hold@example.com", FirstName: "Hold", LastName: "Me"}
+	held, err := store.HoldTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	if _, err := store.GetTicketByEmail(user.Email); err != ErrTicketNotFound {
+		t.Errorf("Expected expired hold to be hidden, got: %v", err)
+	}
+
+	if _, err := store.ConfirmTicket(context.Background(), user.Email); err != ErrTicketNotFound {
+		t.Errorf("Expected ErrTicketNotFound, got: %v", err)
+	}
+
+	// The released seat goes to the next purchaser
+	other := model.User{Email: "other@example.com", FirstName: "Other", LastName: "User"}
+	ticket, err := store.PurchaseTicket(context.Background(), other, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if ticket.Seat != held.Seat {
+		t.Errorf("Expected released seat %v, got %v", held.Seat, ticket.Seat)
+	}
+}
+
+func TestCustomLayout(t *testing.T) {
+	store := NewStoreWithLayout(model.Layout{Sections: []string{"X", "Y", "Z"}, SeatsPerSection: 1})
+
+	for i, want := range []string{"X", "Y", "Z"} {
+		user := model.User{Email: fmt.Sprintf("user%d@example.com", i), FirstName: "User", LastName: "Test"}
+		ticket, err := store.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+		if err != nil {
+			t.Fatalf("Failed to purchase ticket %d: %v", i, err)
+		}
+		if ticket.Seat.Section != want || ticket.Seat.SeatNumber != 1 {
+			t.Errorf("Ticket %d: expected %s-1, got %v", i, want, ticket.Seat)
+		}
+	}
+
+	user := model.User{Email: "late@example.com", FirstName: "Late", LastName: "Comer"}
+	if _, err := store.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents); err != ErrTrainFull {
+		t.Errorf("Expected ErrTrainFull, got: %v", err)
+	}
+
+	if _, err := store.ModifySeat(context.Background(), "user0@example.com", "A", 1); err == nil {
+		t.Error("Expected error for section outside the layout")
+	}
+}
+
+func TestReady(t *testing.T) {
+	store := NewStore()
+
+	if err := store.Ready(); err != nil {
+		t.Fatalf("Expected new store to be ready, got: %v", err)
+	}
+
+	store.Close()
+
+	if err := store.Ready(); err != ErrStoreClosed {
+		t.Errorf("Expected ErrStoreClosed, got: %v", err)
+	}
+}
+
+func TestOccupancy(t *testing.T) {
+	store := NewStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	store.PurchaseTicket(context.Background(), model.User{Email: "a@example.com"}, config.RouteFrom, config.RouteTo, config.TicketPriceCents)
+	store.HoldTicket(context.Background(), model.User{Email: "held@example.com"}, config.RouteFrom, config.RouteTo, config.TicketPriceCents, now.Add(time.Minute))
+	store.HoldTicket(context.Background(), model.User{Email: "expired@example.com"}, config.RouteFrom, config.RouteTo, config.TicketPriceCents, now.Add(-time.Minute))
+
+	occ := store.Occupancy()
+
+	if occ.Occupied["A"] != 1 || occ.Occupied["B"] != 0 {
+		t.Errorf("Expected A=1 B=0, got %v", occ.Occupied)
+	}
+	if _, ok := occ.Occupied["B"]; !ok {
+		t.Error("Expected empty sections to be reported")
+	}
+	if occ.Holds != 1 {
+		t.Errorf("Expected 1 hold, got %d", occ.Holds)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	s := NewStore()
+	updates, cancel := s.Subscribe()
+
+	user := model.User{FirstName: "John", LastName: "Doe", Email: "john@example.com"}
+	if _, err := s.PurchaseTicket(context.Background(), user, "London", "France", 2000); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := s.RemoveTicket(context.Background(), user.Email); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Two writes coalesce into a single pending notification
+	select {
+	case <-updates:
+	default:
+		t.Fatal("Expected a notification after a write")
+	}
+	select {
+	case <-updates:
+		t.Fatal("Expected notifications to be coalesced")
+	default:
+	}
+
+	cancel()
+	if _, err := s.PurchaseTicket(context.Background(), user, "London", "France", 2000); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	select {
+	case <-updates:
+		t.Fatal("Expected no notification after cancel")
+	default:
+	}
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/cloudbees/train-ticket-service/internal/model"
+)
+
+// NearbyFreeSeats returns up to n free seats to offer instead of
+// section-seatNumber: seats in the same section closest first, then the same
+// position in the other in-service sections, in layout order.
+func (s *Store) NearbyFreeSeats(section string, seatNumber int32, n int) []model.Seat {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Expired holds are only released on the next write, but their seats are free
+	now := s.now()
+	released := make(map[string]bool)
+	for _, ticket := range s.tickets {
+		if ticket.HoldExpired(now) {
+			released[seatKey(ticket.Seat.Section, ticket.Seat.SeatNumber)] = true
+		}
+	}
+
+	type candidate struct {
+		seat     model.Seat
+		other    bool
+		distance int32
+		order    int
+	}
+	var candidates []candidate
+	for order, sec := range s.layout.Sections {
+		if s.outOfService[sec] {
+			continue
+		}
+		for i := int32(1); i <= s.layout.SeatsPerSection; i++ {
+			key := seatKey(sec, i)
+			if (s.seats[key] && !released[key]) || (sec == section && i == seatNumber) {
+				continue
+			}
+			distance := i - seatNumber
+			if distance < 0 {
+				distance = -distance
+			}
+			candidates = append(candidates, candidate{model.Seat{Section: sec, SeatNumber: i}, sec != section, distance, order})
+		}
+	}
+
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		if a.other != b.other {
+			if a.other {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Or(cmp.Compare(a.distance, b.distance), cmp.Compare(a.order, b.order), cmp.Compare(a.seat.SeatNumber, b.seat.SeatNumber))
+	})
+
+	seats := make([]model.Seat, 0, min(n, len(candidates)))
+	for _, c := range candidates[:min(n, len(candidates))] {
+		seats = append(seats, c.seat)
+	}
+	return seats
+}
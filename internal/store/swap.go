@@ -0,0 +1,161 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudbees/train-ticket-service/internal/model"
+)
+
+// RequestSeatSwap records a pending proposal for requester and counterparty to
+// exchange their current seats, replacing any earlier proposal from requester.
+// A counterparty whose ticket is still pending
+// gets ErrTicketNotFound, the same as one without a ticket, so the requester
+// learns nothing about them.
+func (s *Store) RequestSeatSwap(ctx context.Context, requesterEmail, counterpartyEmail string) (*model.SeatSwap, error) {
+	s.lock(ctx)
+	defer s.unlock()
+
+	s.releaseExpiredHoldsLocked()
+
+	requester, counterparty, err := s.swapPartiesLocked(requesterEmail, counterpartyEmail)
+	if err == ErrTicketPending && !s.tickets[requesterEmail].IsPending() {
+		return nil, ErrTicketNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for id, swap := range s.swaps {
+		if swap.RequesterEmail == requesterEmail {
+			delete(s.swaps, id)
+		}
+	}
+
+	s.nextID++
+	swap := &model.SeatSwap{
+		ID:                fmt.Sprintf("swap-%d", s.nextID),
+		RequesterEmail:    requesterEmail,
+		RequesterSeat:     requester.Seat,
+		CounterpartyEmail: counterpartyEmail,
+		CounterpartySeat:  counterparty.Seat,
+		Status:            model.SwapStatusPending,
+		CreatedAt:         s.now(),
+	}
+	s.swaps[swap.ID] = swap
+
+	copied := *swap
+	return &copied, nil
+}
+
+func (s *Store) GetSeatSwap(id string) (*model.SeatSwap, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	swap, exists := s.swaps[id]
+	if !exists {
+		return nil, ErrSwapNotFound
+	}
+
+	copied := *swap
+	return &copied, nil
+}
+
+// AcceptSeatSwap exchanges the seats of a pending swap. Swaps are discarded
+// when either passenger moves or loses their ticket, so the ErrSwapStale check
+// only guards against a seat change that bypassed dropSwapsLocked. After any
+// other failure the swap can be accepted again.
+func (s *Store) AcceptSeatSwap(ctx context.Context, id string) (*model.SeatSwap, error) {
+	s.lock(ctx)
+	defer s.unlock()
+
+	s.releaseExpiredHoldsLocked()
+
+	swap, exists := s.swaps[id]
+	if !exists {
+		return nil, ErrSwapNotFound
+	}
+
+	requester, counterparty, err := s.swapPartiesLocked(swap.RequesterEmail, swap.CounterpartyEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(s.swaps, id)
+	if requester.Seat != swap.RequesterSeat || counterparty.Seat != swap.CounterpartySeat {
+		return nil, ErrSwapStale
+	}
+
+	requester.Seat, counterparty.Seat = counterparty.Seat, requester.Seat
+	s.revokeLocked(requester, model.RevokedSeatChanged)
+	s.revokeLocked(counterparty, model.RevokedSeatChanged)
+	s.dropSwapsLocked(requester.User.Email)
+	s.dropSwapsLocked(counterparty.User.Email)
+	swap.Status = model.SwapStatusCompleted
+
+	return swap, nil
+}
+
+// SwapSeats immediately exchanges the seats of two passengers.
+func (s *Store) SwapSeats(ctx context.Context, emailA, emailB string) (*model.SeatSwap, error) {
+	s.lock(ctx)
+	defer s.unlock()
+
+	s.releaseExpiredHoldsLocked()
+
+	a, b, err := s.swapPartiesLocked(emailA, emailB)
+	if err != nil {
+		return nil, err
+	}
+
+	s.nextID++
+	swap := &model.SeatSwap{
+		ID:                fmt.Sprintf("swap-%d", s.nextID),
+		RequesterEmail:    emailA,
+		RequesterSeat:     a.Seat,
+		CounterpartyEmail: emailB,
+		CounterpartySeat:  b.Seat,
+		Status:            model.SwapStatusCompleted,
+		CreatedAt:         s.now(),
+	}
+
+	a.Seat, b.Seat = b.Seat, a.Seat
+	s.revokeLocked(a, model.RevokedSeatChanged)
+	s.revokeLocked(b, model.RevokedSeatChanged)
+	s.dropSwapsLocked(emailA)
+	s.dropSwapsLocked(emailB)
+
+	return swap, nil
+}
+
+// swapPartiesLocked returns the tickets of both passengers in a swap. Seats
+// held for unverified purchases cannot be swapped.
+func (s *Store) swapPartiesLocked(emailA, emailB string) (*model.Ticket, *model.Ticket, error) {
+	if emailA == emailB {
+		return nil, nil, ErrSwapWithSelf
+	}
+
+	var parties [2]*model.Ticket
+	for i, email := range []string{emailA, emailB} {
+		ticket, exists := s.tickets[email]
+		if !exists {
+			return nil, nil, ErrTicketNotFound
+		}
+		if ticket.IsPending() {
+			return nil, nil, ErrTicketPending
+		}
+		parties[i] = ticket
+	}
+
+	return parties[0], parties[1], nil
+}
+
+// dropSwapsLocked discards the pending swaps involving email, whose seat no
+// longer matches what was proposed.
+func (s *Store) dropSwapsLocked(email string) {
+	for id, swap := range s.swaps {
+		if swap.RequesterEmail == email || swap.CounterpartyEmail == email {
+			delete(s.swaps, id)
+		}
+	}
+}
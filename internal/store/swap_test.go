@@ -0,0 +1,159 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudbees/train-ticket-service/internal/config"
+	"github.com/cloudbees/train-ticket-service/internal/model"
+)
+
+func purchaseTestTickets(t *testing.T, store *Store, emails ...string) {
+	t.Helper()
+	for _, email := range emails {
+		user := model.User{Email: email, FirstName: "Test", LastName: "User"}
+		if _, err := store.PurchaseTicket(context.Background(), user, config.RouteFrom, config.RouteTo, config.TicketPriceCents); err != nil {
+			t.Fatalf("Failed to purchase ticket for %s: %v", email, err)
+		}
+	}
+}
+
+func TestAcceptSeatSwap(t *testing.T) {
+	store := NewStore()
+	purchaseTestTickets(t, store, "a@example.com", "b@example.com")
+
+	swap, err := store.RequestSeatSwap(context.Background(), "a@example.com", "b@example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if swap.Status != model.SwapStatusPending {
+		t.Errorf("Expected pending swap, got %s", swap.Status)
+	}
+
+	completed, err := store.AcceptSeatSwap(context.Background(), swap.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if completed.Status != model.SwapStatusCompleted {
+		t.Errorf("Expected completed swap, got %s", completed.Status)
+	}
+
+	a, _ := store.GetTicketByEmail("a@example.com")
+	b, _ := store.GetTicketByEmail("b@example.com")
+	if a.Seat != swap.CounterpartySeat || b.Seat != swap.RequesterSeat {
+		t.Errorf("Expected seats to be exchanged, got a=%v b=%v", a.Seat, b.Seat)
+	}
+
+	if _, err := store.AcceptSeatSwap(context.Background(), swap.ID); err != ErrSwapNotFound {
+		t.Errorf("Expected ErrSwapNotFound on second accept, got: %v", err)
+	}
+}
+
+func TestAcceptSeatSwap_Stale(t *testing.T) {
+	store := NewStore()
+	purchaseTestTickets(t, store, "a@example.com", "b@example.com")
+
+	swap, err := store.RequestSeatSwap(context.Background(), "a@example.com", "b@example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if _, err := store.ModifySeat(context.Background(), "b@example.com", "B", 7); err != nil {
+		t.Fatalf("Failed to modify seat: %v", err)
+	}
+
+	if _, err := store.AcceptSeatSwap(context.Background(), swap.ID); err != ErrSwapNotFound {
+		t.Errorf("Expected a stale swap to be discarded, got: %v", err)
+	}
+}
+
+func TestSeatSwap_DroppedWithTicket(t *testing.T) {
+	store := NewStore()
+	purchaseTestTickets(t, store, "a@example.com", "b@example.com", "c@example.com")
+	ctx := context.Background()
+
+	removed, err := store.RequestSeatSwap(ctx, "a@example.com", "b@example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	kept, err := store.RequestSeatSwap(ctx, "c@example.com", "a@example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if err := store.RemoveTicket(ctx, "b@example.com"); err != nil {
+		t.Fatalf("Failed to remove ticket: %v", err)
+	}
+	if _, err := store.GetSeatSwap(removed.ID); err != ErrSwapNotFound {
+		t.Errorf("Expected the swap to be dropped with the ticket, got: %v", err)
+	}
+	if _, err := store.GetSeatSwap(kept.ID); err != nil {
+		t.Errorf("Expected an unrelated swap to survive, got: %v", err)
+	}
+
+	// A new proposal replaces the requester's earlier one
+	replacement, err := store.RequestSeatSwap(ctx, "c@example.com", "a@example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := store.GetSeatSwap(kept.ID); err != ErrSwapNotFound {
+		t.Errorf("Expected the earlier proposal to be replaced, got: %v", err)
+	}
+
+	if _, err := store.HoldTicket(ctx, model.User{Email: "d@example.com"}, config.RouteFrom, config.RouteTo, config.TicketPriceCents, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Failed to hold ticket: %v", err)
+	}
+	if _, err := store.RequestSeatSwap(ctx, "d@example.com", "c@example.com"); err != ErrTicketPending {
+		t.Errorf("Expected ErrTicketPending, got: %v", err)
+	}
+	if _, err := store.GetSeatSwap(replacement.ID); err != nil {
+		t.Errorf("Expected a failed request to leave earlier swaps alone, got: %v", err)
+	}
+}
+
+func TestSeatSwap_PendingTicket(t *testing.T) {
+	store := NewStore()
+	ctx := context.Background()
+	purchaseTestTickets(t, store, "a@example.com", "b@example.com")
+
+	held := model.User{Email: "held@example.com", FirstName: "Test", LastName: "User"}
+	if _, err := store.HoldTicket(ctx, held, config.RouteFrom, config.RouteTo, config.TicketPriceCents, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Failed to hold ticket: %v", err)
+	}
+	// A pending counterparty looks the same as one without a ticket
+	if _, err := store.RequestSeatSwap(ctx, "a@example.com", held.Email); err != ErrTicketNotFound {
+		t.Errorf("Expected ErrTicketNotFound, got: %v", err)
+	}
+	if _, err := store.RequestSeatSwap(ctx, held.Email, "a@example.com"); err != ErrTicketPending {
+		t.Errorf("Expected ErrTicketPending, got: %v", err)
+	}
+	if _, err := store.SwapSeats(ctx, held.Email, "a@example.com"); err != ErrTicketPending {
+		t.Errorf("Expected ErrTicketPending, got: %v", err)
+	}
+}
+
+func TestSwapSeats(t *testing.T) {
+	store := NewStore()
+	purchaseTestTickets(t, store, "a@example.com", "b@example.com")
+
+	if _, err := store.SwapSeats(context.Background(), "a@example.com", "a@example.com"); err != ErrSwapWithSelf {
+		t.Errorf("Expected ErrSwapWithSelf, got: %v", err)
+	}
+
+	if _, err := store.SwapSeats(context.Background(), "a@example.com", "missing@example.com"); err != ErrTicketNotFound {
+		t.Errorf("Expected ErrTicketNotFound, got: %v", err)
+	}
+
+	swap, err := store.SwapSeats(context.Background(), "a@example.com", "b@example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	a, _ := store.GetTicketByEmail("a@example.com")
+	if a.Seat != swap.CounterpartySeat {
+		t.Errorf("Expected a to have seat %v, got %v", swap.CounterpartySeat, a.Seat)
+	}
+}
@@ -0,0 +1,32 @@
+package store
+
+// Subscribe returns a channel that receives a value after each write to the
+// store, and a function that stops the subscription. Notifications are
+// coalesced: a slow reader gets one wake-up and reads the latest state.
+func (s *Store) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	s.subMu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.subMu.Unlock()
+
+	return ch, func() {
+		s.subMu.Lock()
+		delete(s.subscribers, ch)
+		s.subMu.Unlock()
+	}
+}
+
+// unlock releases the write lock and wakes subscribers.
+func (s *Store) unlock() {
+	s.mu.Unlock()
+
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	for ch := range s.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Package tickettoken issues the signed tokens printed on e-tickets. A token
+// is a compact JWS signed with Ed25519, so anyone holding the public key can
+// check a ticket without asking the service; see Verifier.
+package tickettoken
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cloudbees/train-ticket-service/internal/model"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims are what a ticket token asserts about its ticket.
+type Claims struct {
+	Train      string `json:"train"`
+	Section    string `json:"section"`
+	SeatNumber int32  `json:"seat"`
+	// Name is the passenger's full name, to check against their ID.
+	Name string `json:"name"`
+	// Revision is the ticket's revision when the token was issued. The
+	// ticket ID is the jti claim.
+	Revision int32 `json:"rev"`
+	jwt.RegisteredClaims
+}
+
+// Signer signs ticket tokens with one private key.
+type Signer struct {
+	key   ed25519.PrivateKey
+	keyID string
+}
+
+func NewSigner(key ed25519.PrivateKey) *Signer {
+	return &Signer{key: key, keyID: KeyID(key.Public().(ed25519.PublicKey))}
+}
+
+// GenerateSigner returns a Signer with a new random key.
+func GenerateSigner() *Signer {
+	// Reading from crypto/rand does not fail
+	_, key, _ := ed25519.GenerateKey(nil)
+	return NewSigner(key)
+}
+
+// LoadSigner reads a PEM PKCS #8 Ed25519 private key, as written by
+// `openssl genpkey -algorithm ed25519`.
+func LoadSigner(path string) (*Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("%s: no PEM PRIVATE KEY block", path)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s: not an Ed25519 key", path)
+	}
+	return NewSigner(edKey), nil
+}
+
+// KeyID names a public key in the kid header of the tokens it verifies.
+func KeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return base64.RawURLEncoding.EncodeToString(sum[:8])
+}
+
+func (s *Signer) PublicKey() ed25519.PublicKey {
+	return s.key.Public().(ed25519.PublicKey)
+}
+
+// Sign returns the token for t on train, issued at now.
+func (s *Signer) Sign(t *model.Ticket, train string, now time.Time) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, Claims{
+		Train:      train,
+		Section:    t.Seat.Section,
+		SeatNumber: t.Seat.SeatNumber,
+		Name:       t.User.FirstName + " " + t.User.LastName,
+		Revision:   t.Revision,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       t.ID,
+			IssuedAt: jwt.NewNumericDate(now),
+		},
+	})
+	token.Header["kid"] = s.keyID
+	return token.SignedString(s.key)
+}
@@ -0,0 +1,59 @@
+package tickettoken
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cloudbees/train-ticket-service/internal/model"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestSign(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(nil)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	signer, err := LoadSigner(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	ticket := &model.Ticket{
+		User: model.User{FirstName: "Zoë", LastName: "Lee", Email: "zoe@example.com"},
+		Seat: model.Seat{Section: "B", SeatNumber: 7},
+	}
+	issued := time.Unix(1700000000, 0)
+	signed, err := signer.Sign(ticket, "London-France", issued)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var claims Claims
+	token, err := jwt.ParseWithClaims(signed, &claims, func(*jwt.Token) (any, error) {
+		return key.Public(), nil
+	}, jwt.WithValidMethods([]string{"EdDSA"}))
+	if err != nil {
+		t.Fatalf("Expected a valid signature, got: %v", err)
+	}
+	if token.Header["kid"] != KeyID(key.Public().(ed25519.PublicKey)) {
+		t.Errorf("Expected the key ID in the header, got %v", token.Header["kid"])
+	}
+	if claims.Train != "London-France" || claims.Section != "B" || claims.SeatNumber != 7 || claims.Name != "Zoë Lee" || !claims.IssuedAt.Equal(issued) {
+		t.Errorf("Unexpected claims %+v", claims)
+	}
+
+	if _, err := LoadSigner(filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+		t.Error("Expected an error for a missing key file")
+	}
+}
@@ -0,0 +1,104 @@
+package tickettoken
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid ticket token")
+	ErrUnknownKey   = errors.New("ticket token signed by an unknown key")
+	ErrWrongTrain   = errors.New("ticket is for another train")
+	ErrRevoked      = errors.New("ticket token is revoked")
+)
+
+// Revoked is the latest revocation of a ticket: its tokens up to Revision
+// are no longer valid.
+type Revoked struct {
+	Revision int32  `json:"revision"`
+	Reason   string `json:"reason"`
+}
+
+// RevocationList holds the revoked tickets as of Cursor, the sequence number
+// to sync from next. Epoch names the server process Cursor counts in; a
+// server with another epoch sends its whole list again.
+type RevocationList struct {
+	Epoch   string             `json:"epoch"`
+	Cursor  int64              `json:"cursor"`
+	Tickets map[string]Revoked `json:"tickets"`
+}
+
+// Add records that ticketID's tokens up to revision were revoked.
+func (l *RevocationList) Add(ticketID string, revision int32, reason string) {
+	if l.Tickets == nil {
+		l.Tickets = make(map[string]Revoked)
+	}
+	if r, ok := l.Tickets[ticketID]; ok && r.Revision >= revision {
+		return
+	}
+	l.Tickets[ticketID] = Revoked{Revision: revision, Reason: reason}
+}
+
+// Lookup returns the revocation covering a token for ticketID at revision.
+func (l *RevocationList) Lookup(ticketID string, revision int32) (Revoked, bool) {
+	r, ok := l.Tickets[ticketID]
+	if !ok || revision > r.Revision {
+		return Revoked{}, false
+	}
+	return r, true
+}
+
+// Verifier checks ticket tokens without a connection to the service, using
+// the keys and revocations last synced from it. It marshals to JSON so a
+// conductor's device can keep it between syncs.
+type Verifier struct {
+	Train       string                       `json:"train"`
+	Keys        map[string]ed25519.PublicKey `json:"keys"`
+	Revocations RevocationList               `json:"revocations"`
+	// SyncedAt is when the revocations were last brought up to date.
+	SyncedAt time.Time `json:"synced_at"`
+}
+
+// AddKey trusts pub for tokens naming it in their kid header.
+func (v *Verifier) AddKey(pub ed25519.PublicKey) {
+	if v.Keys == nil {
+		v.Keys = make(map[string]ed25519.PublicKey)
+	}
+	v.Keys[KeyID(pub)] = pub
+}
+
+// VerifyTicket checks the token's signature, that it is for v's train and
+// that the seat it names has not been revoked since. Claims are returned
+// with ErrWrongTrain and ErrRevoked so the caller can say what was wrong.
+func (v *Verifier) VerifyTicket(token string) (*Claims, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, ok := v.Keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrUnknownKey, kid)
+		}
+		return key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}))
+	if errors.Is(err, ErrUnknownKey) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if claims.ID == "" {
+		return nil, fmt.Errorf("%w: no ticket id", ErrInvalidToken)
+	}
+
+	if v.Train != "" && claims.Train != v.Train {
+		return &claims, fmt.Errorf("%w: %s", ErrWrongTrain, claims.Train)
+	}
+	if r, ok := v.Revocations.Lookup(claims.ID, claims.Revision); ok {
+		return &claims, fmt.Errorf("%w: %s", ErrRevoked, r.Reason)
+	}
+	return &claims, nil
+}
@@ -0,0 +1,76 @@
+package tickettoken
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloudbees/train-ticket-service/internal/model"
+)
+
+func TestVerifyTicket(t *testing.T) {
+	signer := GenerateSigner()
+	ticket := &model.Ticket{
+		ID:   "t1",
+		User: model.User{FirstName: "Ada", LastName: "Byron"},
+		Seat: model.Seat{Section: "A", SeatNumber: 3},
+	}
+	sign := func(train string) string {
+		token, err := signer.Sign(ticket, train, time.Now())
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		return token
+	}
+	first := sign("London-France")
+
+	var v Verifier
+	if _, err := v.VerifyTicket(first); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected ErrUnknownKey without keys, got %v", err)
+	}
+
+	// The verifier survives being stored and loaded as JSON
+	saved := Verifier{Train: "London-France"}
+	saved.AddKey(signer.PublicKey())
+	data, err := json.Marshal(saved)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	claims, err := v.VerifyTicket(first)
+	if err != nil {
+		t.Fatalf("Expected a valid ticket, got: %v", err)
+	}
+	if claims.ID != "t1" || claims.Revision != 0 || claims.Name != "Ada Byron" || claims.Section != "A" || claims.SeatNumber != 3 {
+		t.Errorf("Unexpected claims %+v", claims)
+	}
+
+	if _, err := v.VerifyTicket(first[:len(first)-4] + "AAAA"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for a bad signature, got %v", err)
+	}
+	if _, err := v.VerifyTicket(sign("Paris-Berlin")); !errors.Is(err, ErrWrongTrain) {
+		t.Errorf("Expected ErrWrongTrain, got %v", err)
+	}
+
+	// Moving seat revokes revision 0; the token for the new seat still verifies
+	v.Revocations.Add("t1", 0, model.RevokedSeatChanged)
+	ticket.Revision = 1
+	ticket.Seat.SeatNumber = 4
+	if _, err := v.VerifyTicket(first); !errors.Is(err, ErrRevoked) {
+		t.Errorf("Expected the old seat's token to be revoked, got %v", err)
+	}
+	second := sign("London-France")
+	if _, err := v.VerifyTicket(second); err != nil {
+		t.Errorf("Expected the new seat's token to verify, got %v", err)
+	}
+
+	v.Revocations.Add("t1", 1, model.RevokedRemoved)
+	v.Revocations.Add("t1", 0, model.RevokedSeatChanged)
+	if _, err := v.VerifyTicket(second); !errors.Is(err, ErrRevoked) || err.Error() != "ticket token is revoked: removed" {
+		t.Errorf("Expected the ticket to be revoked as removed, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultReloadInterval bounds how often the certificate files are checked for changes.
+const DefaultReloadInterval = time.Second
+
+// CertReloader serves a certificate and key pair from disk and reloads them
+// when either file changes. A failed reload keeps the previous pair, so a
+// half-written rotation never breaks new handshakes.
+type CertReloader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	certMod fileStamp
+	keyMod  fileStamp
+	checked time.Time
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func NewCertReloader(certFile, keyFile string, interval time.Duration) (*CertReloader, error) {
+	r := &CertReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		interval: interval,
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload loads the pair from disk unconditionally.
+func (r *CertReloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.reloadLocked()
+}
+
+func (r *CertReloader) reloadLocked() error {
+	certMod, err := stat(r.certFile)
+	if err != nil {
+		return err
+	}
+	keyMod, err := stat(r.keyFile)
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+
+	r.cert = &cert
+	r.certMod = certMod
+	r.keyMod = keyMod
+	return nil
+}
+
+func (r *CertReloader) current() *tls.Certificate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(r.checked) < r.interval {
+		return r.cert
+	}
+	r.checked = now
+
+	certMod, certErr := stat(r.certFile)
+	keyMod, keyErr := stat(r.keyFile)
+	if certErr != nil || keyErr != nil || (certMod == r.certMod && keyMod == r.keyMod) {
+		return r.cert
+	}
+
+	if err := r.reloadLocked(); err != nil {
+		slog.Error("tls: keeping previous certificate, reload failed", "cert_file", r.certFile, "error", err)
+	} else {
+		slog.Info("tls: reloaded certificate", "cert_file", r.certFile)
+	}
+	return r.cert
+}
+
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.current(), nil
+}
+
+func (r *CertReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return r.current(), nil
+}
+
+func stat(path string) (fileStamp, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, err
+	}
+	return fileStamp{modTime: fi.ModTime(), size: fi.Size()}, nil
+}
@@ -0,0 +1,106 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Client certificate policies for ServerConfig.
+const (
+	ClientAuthNone     = "none"
+	ClientAuthOptional = "optional"
+	ClientAuthRequire  = "require"
+)
+
+type ServerOptions struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+	ClientAuth   string
+}
+
+// ServerConfig builds a TLS config whose certificate is reloaded from disk
+// when it changes. When ClientAuth is optional or require, client
+// certificates are verified against ClientCAFile.
+func ServerConfig(opts ServerOptions) (*tls.Config, error) {
+	reloader, err := NewCertReloader(opts.CertFile, opts.KeyFile, DefaultReloadInterval)
+	if err != nil {
+		return nil, fmt.Errorf("load server certificate: %w", err)
+	}
+
+	cfg := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+
+	switch opts.ClientAuth {
+	case "", ClientAuthNone:
+		return cfg, nil
+	case ClientAuthOptional:
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	case ClientAuthRequire:
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("unknown client auth policy %q", opts.ClientAuth)
+	}
+
+	pool, err := loadCertPool(opts.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("load client CA: %w", err)
+	}
+	cfg.ClientCAs = pool
+
+	return cfg, nil
+}
+
+type ClientOptions struct {
+	CAFile     string
+	CertFile   string // Optional: client certificate for mTLS
+	KeyFile    string
+	ServerName string
+}
+
+func ClientConfig(opts ClientOptions) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: opts.ServerName,
+	}
+
+	if opts.CAFile != "" {
+		pool, err := loadCertPool(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("load CA: %w", err)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if opts.CertFile != "" {
+		reloader, err := NewCertReloader(opts.CertFile, opts.KeyFile, DefaultReloadInterval)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.GetClientCertificate = reloader.GetClientCertificate
+	}
+
+	return cfg, nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	if path == "" {
+		return nil, errors.New("CA file is required")
+	}
+
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
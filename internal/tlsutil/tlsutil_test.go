@@ -0,0 +1,156 @@
+package tlsutil
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io"
+	"net"
+	"testing"
+)
+
+func TestCertReloader_Reloads(t *testing.T) {
+	dir := t.TempDir()
+	certs, err := GenerateDevCerts(dir, []string{"localhost"}, "client")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	reloader, err := NewCertReloader(certs.ServerCertFile, certs.ServerKeyFile, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	before, _ := reloader.GetCertificate(nil)
+
+	// Rotating the files in place must be picked up on the next handshake
+	if _, err := GenerateDevCerts(dir, []string{"localhost"}, "client"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	after, _ := reloader.GetCertificate(nil)
+	if bytes.Equal(before.Certificate[0], after.Certificate[0]) {
+		t.Error("Expected rotated certificate to be served")
+	}
+}
+
+func TestCertReloader_KeepsPreviousOnBadFile(t *testing.T) {
+	certs, err := GenerateDevCerts(t.TempDir(), []string{"localhost"}, "client")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	reloader, err := NewCertReloader(certs.ServerCertFile, certs.ServerKeyFile, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	before, _ := reloader.GetCertificate(nil)
+
+	if err := writePEM(certs.ServerCertFile, "CERTIFICATE", []byte("truncated"), 0o644); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	after, _ := reloader.GetCertificate(nil)
+	if after != before {
+		t.Error("Expected previous certificate to be kept after a failed reload")
+	}
+}
+
+func TestServerConfig_ClientAuth(t *testing.T) {
+	certs, err := GenerateDevCerts(t.TempDir(), []string{"localhost", "127.0.0.1"}, "billing")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	serverConfig, err := ServerConfig(ServerOptions{
+		CertFile:     certs.ServerCertFile,
+		KeyFile:      certs.ServerKeyFile,
+		ClientCAFile: certs.CAFile,
+		ClientAuth:   ClientAuthRequire,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    ClientOptions
+		wantErr bool
+	}{
+		{"with client certificate", ClientOptions{CAFile: certs.CAFile, CertFile: certs.ClientCertFile, KeyFile: certs.ClientKeyFile}, false},
+		{"without client certificate", ClientOptions{CAFile: certs.CAFile}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientConfig, err := ClientConfig(tt.opts)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			err = handshake(t, serverConfig, clientConfig)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestServerConfig_UnknownClientAuth(t *testing.T) {
+	certs, err := GenerateDevCerts(t.TempDir(), []string{"localhost"}, "client")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	_, err = ServerConfig(ServerOptions{CertFile: certs.ServerCertFile, KeyFile: certs.ServerKeyFile, ClientAuth: "sometimes"})
+	if err == nil {
+		t.Error("Expected error for unknown client auth policy")
+	}
+}
+
+// handshake runs a TLS handshake over loopback and returns the first error
+// either side saw.
+func handshake(t *testing.T, serverConfig, clientConfig *tls.Config) error {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer lis.Close()
+
+	clientConfig = clientConfig.Clone()
+	clientConfig.ServerName = "localhost"
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverConn, err := lis.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		conn := tls.Server(serverConn, serverConfig)
+		err = conn.Handshake()
+		if err == nil {
+			// TLS 1.3 reports client certificate failures on the first read
+			_, err = conn.Write([]byte("ok"))
+		}
+		conn.Close()
+		serverErr <- err
+	}()
+
+	clientConn, err := net.Dial("tcp", lis.Addr().String())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	conn := tls.Client(clientConn, clientConfig)
+	err = conn.Handshake()
+	if err == nil {
+		_, err = io.ReadFull(conn, make([]byte, 2))
+	}
+	conn.Close()
+
+	if sErr := <-serverErr; err == nil {
+		err = sErr
+	}
+	return err
+}
@@ -0,0 +1,92 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// installMemoryProvider routes spans to an in-memory exporter for the test.
+func installMemoryProvider(t *testing.T) (*tracetest.InMemoryExporter, func()) {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := NewProvider(exporter, 1)
+	previous := otel.GetTracerProvider()
+	Install(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return exporter, func() { tp.ForceFlush(context.Background()) }
+}
+
+func TestUnaryServerInterceptor_ContinuesIncomingTrace(t *testing.T) {
+	exporter, flush := installMemoryProvider(t)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	))
+	info := &grpc.UnaryServerInfo{FullMethod: "/ticket.TicketService/PurchaseTicket"}
+
+	UnaryServerInterceptor()(ctx, nil, info, func(ctx context.Context, _ any) (any, error) {
+		_, child := otel.Tracer("test").Start(ctx, "child")
+		child.End()
+		return nil, status.Error(codes.ResourceExhausted, "train is full")
+	})
+	flush()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+
+	child, server := spans[0], spans[1]
+	if server.Name != info.FullMethod {
+		t.Errorf("Expected server span %s, got %s", info.FullMethod, server.Name)
+	}
+	if got := server.SpanContext.TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected incoming trace id, got %s", got)
+	}
+	if got := server.Parent.SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("Expected incoming parent span id, got %s", got)
+	}
+	if child.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Error("Expected handler span to be a child of the server span")
+	}
+	if server.Status.Code != otelcodes.Error || server.Status.Description != "train is full" {
+		t.Errorf("Expected error status, got %+v", server.Status)
+	}
+}
+
+func TestUnaryServerInterceptor_StartsNewTrace(t *testing.T) {
+	exporter, flush := installMemoryProvider(t)
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/ticket.TicketService/ViewAllocations"}
+	UnaryServerInterceptor()(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return nil, nil
+	})
+	flush()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Parent.IsValid() {
+		t.Error("Expected a root span without incoming trace context")
+	}
+}
+
+func TestNewExporter(t *testing.T) {
+	if exp, err := NewExporter(ExporterNone, nil); err != nil || exp != nil {
+		t.Errorf("Expected no exporter for none, got %v, %v", exp, err)
+	}
+	if _, err := NewExporter("zipkin", nil); err == nil {
+		t.Error("Expected error for unknown exporter")
+	}
+}
@@ -0,0 +1,119 @@
+// Package validation checks and normalizes the names and emails callers send.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// MaxEmailLength and MaxLocalPartLength are the RFC 5321 limits.
+	MaxEmailLength     = 254
+	MaxLocalPartLength = 64
+	// MaxNameLength is in characters, after normalization.
+	MaxNameLength = 100
+)
+
+var ErrRequired = errors.New("is required")
+
+// NormalizeEmail returns the canonical form of email: NFC, without
+// surrounding whitespace and lowercased, so A@x.com and a@x.com are one user.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(norm.NFC.String(strings.TrimSpace(email)))
+}
+
+// Email normalizes email and checks it is a plain RFC 5322 addr-spec, such
+// as jane@example.com, with a domain that has at least one dot.
+func Email(email string) (string, error) {
+	email = NormalizeEmail(email)
+	if email == "" {
+		return "", ErrRequired
+	}
+	if len(email) > MaxEmailLength {
+		return "", fmt.Errorf("must be at most %d bytes", MaxEmailLength)
+	}
+
+	// ParseAddress also accepts "Jane <jane@example.com>", which is not an email
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" || strings.HasPrefix(email, "<") {
+		return "", errors.New("is not a valid email address")
+	}
+	// Quote the local part only where needed, so "jane"@x.com is jane@x.com
+	email = strings.TrimSuffix(strings.TrimPrefix(addr.String(), "<"), ">")
+
+	at := strings.LastIndexByte(email, '@')
+	local, domain := email[:at], email[at+1:]
+	if len(local) > MaxLocalPartLength {
+		return "", fmt.Errorf("local part must be at most %d bytes", MaxLocalPartLength)
+	}
+	if strings.HasPrefix(domain, "[") || !strings.Contains(domain, ".") {
+		return "", errors.New("must have a domain name such as example.com")
+	}
+	return email, nil
+}
+
+// Name normalizes a first or last name to NFC with single spaces and checks
+// its length and characters: letters, combining marks, spaces, apostrophes,
+// hyphens and periods.
+func Name(name string) (string, error) {
+	name = strings.Join(strings.Fields(norm.NFC.String(name)), " ")
+	if name == "" {
+		return "", ErrRequired
+	}
+	if utf8.RuneCountInString(name) > MaxNameLength {
+		return "", fmt.Errorf("must be at most %d characters", MaxNameLength)
+	}
+
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r), unicode.Is(unicode.M, r):
+		case r == ' ', r == '\'', r == '’', r == '-', r == '.':
+		default:
+			return "", fmt.Errorf("must not contain %q", r)
+		}
+	}
+	if first, _ := utf8.DecodeRuneInString(name); !unicode.IsLetter(first) {
+		return "", errors.New("must start with a letter")
+	}
+	return name, nil
+}
+
+// Violations collects invalid fields so a caller learns about all of them at once.
+type Violations []*errdetails.BadRequest_FieldViolation
+
+// Check records err, if any, against field.
+func (v *Violations) Check(field string, err error) {
+	if err != nil {
+		*v = append(*v, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: field + " " + err.Error(),
+		})
+	}
+}
+
+// Err returns nil when there are no violations, otherwise an InvalidArgument
+// status carrying them as a BadRequest detail.
+func (v Violations) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	descriptions := make([]string, len(v))
+	for i, fv := range v {
+		descriptions[i] = fv.Description
+	}
+	st := status.New(codes.InvalidArgument, strings.Join(descriptions, "; "))
+	if withDetails, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: v}); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
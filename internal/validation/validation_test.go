@@ -0,0 +1,126 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestEmail(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"jane@example.com", "jane@example.com", false},
+		{"  Jane.Doe@Example.COM ", "jane.doe@example.com", false},
+		{"jane+trains@mail.example.co.uk", "jane+trains@mail.example.co.uk", false},
+		{`"jane doe"@example.com`, `"jane doe"@example.com`, false},
+		{`"jane"@example.com`, "jane@example.com", false},
+		{"josé@example.com", "josé@example.com", false},
+		{"", "", true},
+		{"x", "", true},
+		{"@@", "", true},
+		{"jane@", "", true},
+		{"@example.com", "", true},
+		{"jane@localhost", "", true},
+		{"jane@[192.0.2.1]", "", true},
+		{"Jane <jane@example.com>", "", true},
+		{"<jane@example.com>", "", true},
+		{"jane..doe@example.com", "", true},
+		{"jane@exa mple.com", "", true},
+		{strings.Repeat("a", 65) + "@example.com", "", true},
+		{"jane@" + strings.Repeat("a", 250) + ".com", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Email(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"John", "John", false},
+		{"  Mary   Jane ", "Mary Jane", false},
+		{"O'Brien-Smith", "O'Brien-Smith", false},
+		{"Zoë", "Zoë", false},
+		{"Zoë", "Zoë", false},
+		{"李", "李", false},
+		{"J.", "J.", false},
+		{"", "", true},
+		{"   ", "", true},
+		{"-John", "", true},
+		{"R2D2", "", true},
+		{"John<script>", "", true},
+		{"John\x00", "", true},
+		{strings.Repeat("a", MaxNameLength+1), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Name(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestViolations(t *testing.T) {
+	var v Violations
+	if v.Err() != nil {
+		t.Fatal("Expected no error without violations")
+	}
+
+	v.Check("first_name", nil)
+	v.Check("last_name", ErrRequired)
+	_, err := Email("@@")
+	v.Check("email", err)
+
+	st := status.Convert(v.Err())
+	if st.Code() != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", st.Code())
+	}
+
+	var fields []string
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, fv := range br.FieldViolations {
+				fields = append(fields, fv.Field)
+			}
+		}
+	}
+	if strings.Join(fields, ",") != "last_name,email" {
+		t.Errorf("Expected violations for last_name and email, got %v", fields)
+	}
+}
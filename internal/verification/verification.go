@@ -0,0 +1,126 @@
+package verification
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/cloudbees/train-ticket-service/internal/logging"
+)
+
+var (
+	ErrNoPendingCode   = errors.New("no pending verification for email")
+	ErrCodeExpired     = errors.New("verification code expired")
+	ErrCodeMismatch    = errors.New("verification code does not match")
+	ErrTooManyAttempts = errors.New("too many verification attempts")
+)
+
+const maxAttempts = 5
+
+// Sender delivers verification codes to the purchaser's email address.
+type Sender interface {
+	SendVerificationCode(email, code string) error
+}
+
+// LogSender stands in for email delivery by logging that a code was issued.
+// The code itself is only logged with ShowCodes, for local development.
+type LogSender struct {
+	Logger    *slog.Logger
+	Redactor  logging.Redactor
+	ShowCodes bool
+}
+
+func (s LogSender) SendVerificationCode(email, code string) error {
+	attrs := []any{"email", s.Redactor.Value(email)}
+	if s.ShowCodes {
+		attrs = append(attrs, "code", code)
+	}
+	s.Logger.Info("verification code issued", attrs...)
+	return nil
+}
+
+type pendingCode struct {
+	code      string
+	expiresAt time.Time
+	attempts  int
+}
+
+// Codes tracks outstanding verification codes keyed by email.
+type Codes struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	pending map[string]*pendingCode
+	now     func() time.Time
+}
+
+func NewCodes(ttl time.Duration) *Codes {
+	return &Codes{
+		ttl:     ttl,
+		pending: make(map[string]*pendingCode),
+		now:     time.Now,
+	}
+}
+
+// Issue generates a fresh code for email, replacing any earlier one.
+func (c *Codes) Issue(email string) (string, time.Time, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	code := fmt.Sprintf("%06d", n.Int64())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.pruneLocked(now)
+
+	expiresAt := now.Add(c.ttl)
+	c.pending[email] = &pendingCode{code: code, expiresAt: expiresAt}
+
+	return code, expiresAt, nil
+}
+
+// Check consumes the code for email if it matches.
+func (c *Codes) Check(email, code string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	p, exists := c.pending[email]
+	c.pruneLocked(now)
+	if !exists {
+		return ErrNoPendingCode
+	}
+
+	if !now.Before(p.expiresAt) {
+		return ErrCodeExpired
+	}
+
+	if subtle.ConstantTimeCompare([]byte(p.code), []byte(code)) != 1 {
+		p.attempts++
+		if p.attempts >= maxAttempts {
+			delete(c.pending, email)
+			return ErrTooManyAttempts
+		}
+		return ErrCodeMismatch
+	}
+
+	delete(c.pending, email)
+	return nil
+}
+
+// pruneLocked drops codes that expired without being checked, so purchases
+// that are never verified do not accumulate.
+func (c *Codes) pruneLocked(now time.Time) {
+	for email, p := range c.pending {
+		if !now.Before(p.expiresAt) {
+			delete(c.pending, email)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package verification
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudbees/train-ticket-service/internal/logging"
+)
+
+func TestCodesCheck(t *testing.T) {
+	c := NewCodes(time.Minute)
+
+	code, _, err := c.Issue("jane@example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(code) != 6 {
+		t.Errorf("Expected 6 digit code, got %q", code)
+	}
+
+	if err := c.Check("jane@example.com", "wrong"); err != ErrCodeMismatch {
+		t.Errorf("Expected ErrCodeMismatch, got: %v", err)
+	}
+
+	if err := c.Check("jane@example.com", code); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	// Codes are single use
+	if err := c.Check("jane@example.com", code); err != ErrNoPendingCode {
+		t.Errorf("Expected ErrNoPendingCode, got: %v", err)
+	}
+}
+
+func TestCodesExpiry(t *testing.T) {
+	now := time.Now()
+	c := NewCodes(time.Minute)
+	c.now = func() time.Time { return now }
+
+	code, _, _ := c.Issue("jane@example.com")
+
+	now = now.Add(2 * time.Minute)
+	if err := c.Check("jane@example.com", code); err != ErrCodeExpired {
+		t.Errorf("Expected ErrCodeExpired, got: %v", err)
+	}
+}
+
+func TestCodesPrunesExpired(t *testing.T) {
+	now := time.Now()
+	c := NewCodes(time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Issue("jane@example.com")
+	c.Issue("john@example.com")
+
+	now = now.Add(2 * time.Minute)
+	code, _, _ := c.Issue("ann@example.com")
+	if len(c.pending) != 1 {
+		t.Errorf("Expected expired codes to be pruned on issue, got %d pending", len(c.pending))
+	}
+
+	c.Issue("bob@example.com")
+	now = now.Add(2 * time.Minute)
+	if err := c.Check("ann@example.com", code); err != ErrCodeExpired {
+		t.Errorf("Expected ErrCodeExpired, got: %v", err)
+	}
+	if len(c.pending) != 0 {
+		t.Errorf("Expected expired codes to be pruned on check, got %d pending", len(c.pending))
+	}
+}
+
+func TestCodesTooManyAttempts(t *testing.T) {
+	c := NewCodes(time.Minute)
+	code, _, _ := c.Issue("jane@example.com")
+
+	var err error
+	for i := 0; i < maxAttempts; i++ {
+		err = c.Check("jane@example.com", "bad")
+	}
+	if err != ErrTooManyAttempts {
+		t.Errorf("Expected ErrTooManyAttempts, got: %v", err)
+	}
+
+	if err := c.Check("jane@example.com", code); err != ErrNoPendingCode {
+		t.Errorf("Expected code to be invalidated, got: %v", err)
+	}
+}
+
+func TestLogSender(t *testing.T) {
+	var buf bytes.Buffer
+	redactor, _ := logging.NewRedactor(logging.RedactionRedact)
+	sender := LogSender{Logger: slog.New(slog.NewJSONHandler(&buf, nil)), Redactor: redactor}
+
+	if err := sender.SendVerificationCode("jane@example.com", "123456"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if logged := buf.String(); strings.Contains(logged, "123456") || strings.Contains(logged, "jane@") {
+		t.Errorf("Expected neither the code nor the email to be logged, got %q", logged)
+	}
+
+	buf.Reset()
+	sender.ShowCodes = true
+	sender.SendVerificationCode("jane@example.com", "123456")
+	if logged := buf.String(); !strings.Contains(logged, `"code":"123456"`) || strings.Contains(logged, "jane@") {
+		t.Errorf("Expected only the code to be logged in development, got %q", logged)
+	}
+}